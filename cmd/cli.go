@@ -9,12 +9,15 @@ import (
 	"os/signal"
 	"path/filepath"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"syscall"
 
 	"github.com/fatih/color"
 	"github.com/hashicorp/logutils"
 	flags "github.com/jessevdk/go-flags"
 	"github.com/terraform-linters/tflint/formatter"
+	"github.com/terraform-linters/tflint/plugin"
 	"github.com/terraform-linters/tflint/terraform"
 	"github.com/terraform-linters/tflint/tflint"
 )
@@ -33,6 +36,18 @@ type CLI struct {
 	outStream, errStream io.Writer
 	originalWorkingDir   string
 	sources              map[string][]byte
+	// suggestions is the sources fixed by each plugin separately, keyed by rule names and file paths.
+	// It is collected only when the format suggests changes without --fix.
+	suggestions map[string]map[string][]byte
+	// summary is the statistics of the inspection. It is nil unless --summary is given.
+	summary *tflint.Summary
+	// profile is the timing profile of the inspection. It is nil unless --profile is given.
+	profile *tflint.Profile
+	// shutdownHooks are called before exiting on signals, since os.Exit does not run deferred functions.
+	shutdownHooks []func()
+	shutdownMu    sync.Mutex
+	// interrupted is set when a signal is received, so that partial results are not committed.
+	interrupted atomic.Bool
 
 	// fields for each module
 	config    *tflint.Config
@@ -49,6 +64,7 @@ func NewCLI(outStream io.Writer, errStream io.Writer) (*CLI, error) {
 		errStream:          errStream,
 		originalWorkingDir: wd,
 		sources:            map[string][]byte{},
+		suggestions:        map[string]map[string][]byte{},
 	}, err
 }
 
@@ -56,7 +72,7 @@ func NewCLI(outStream io.Writer, errStream io.Writer) (*CLI, error) {
 func (cli *CLI) Run(args []string) int {
 	var opts Options
 	parser := flags.NewParser(&opts, flags.HelpFlag)
-	parser.Usage = "--chdir=DIR/--recursive [OPTIONS]"
+	parser.Usage = "--chdir=DIR/--recursive [OPTIONS]\n  tflint plugins (list|upgrade|prune) [OPTIONS]"
 	parser.UnknownOptionHandler = unknownOptionHandler
 	// Parse commandline flag
 	args, err := parser.ParseArgs(args)
@@ -92,10 +108,39 @@ func (cli *CLI) Run(args []string) int {
 		cli.formatter.Print(tflint.Issues{}, fmt.Errorf("Failed to parse CLI options; %w", err), map[string][]byte{})
 		return ExitCodeError
 	}
+	if len(args) > 1 && args[1] == "plugins" {
+		return cli.plugins(opts, args[2:])
+	}
 	if len(args) > 1 {
 		cli.formatter.Print(tflint.Issues{}, fmt.Errorf("Command line arguments support was dropped in v0.47. Use --chdir or --filter instead."), map[string][]byte{})
 		return ExitCodeError
 	}
+	if opts.UpgradeLock && !opts.Init {
+		cli.formatter.Print(tflint.Issues{}, fmt.Errorf("--upgrade-lock must be used with --init"), map[string][]byte{})
+		return ExitCodeError
+	}
+	if opts.Mirror != "" && !opts.Init {
+		cli.formatter.Print(tflint.Issues{}, fmt.Errorf("--mirror must be used with --init"), map[string][]byte{})
+		return ExitCodeError
+	}
+	if opts.Mirror != "" && opts.UpgradeLock {
+		cli.formatter.Print(tflint.Issues{}, fmt.Errorf("--mirror cannot be used with --upgrade-lock"), map[string][]byte{})
+		return ExitCodeError
+	}
+	if len(opts.Platforms) > 0 && opts.Mirror == "" {
+		cli.formatter.Print(tflint.Issues{}, fmt.Errorf("--platform must be used with --mirror"), map[string][]byte{})
+		return ExitCodeError
+	}
+	for _, platform := range opts.Platforms {
+		if err := plugin.ValidatePlatform(platform); err != nil {
+			cli.formatter.Print(tflint.Issues{}, fmt.Errorf("Failed to parse CLI options; %w", err), map[string][]byte{})
+			return ExitCodeError
+		}
+	}
+	if opts.OutputFile != "" && (opts.Version || opts.Init || opts.Langserver) {
+		cli.formatter.Print(tflint.Issues{}, fmt.Errorf("--output-file cannot be used with --version, --init, or --langserver"), map[string][]byte{})
+		return ExitCodeError
+	}
 	if opts.MaxWorkers != nil && *opts.MaxWorkers <= 0 {
 		cli.formatter.Print(tflint.Issues{}, fmt.Errorf("Max workers should be greater than 0"), map[string][]byte{})
 		return ExitCodeError
@@ -111,11 +156,16 @@ func (cli *CLI) Run(args []string) int {
 	case opts.ActAsBundledPlugin:
 		return cli.actAsBundledPlugin()
 	default:
-		if opts.Recursive {
-			return cli.inspectParallel(opts)
-		} else {
+		inspect := func() int {
+			if opts.Recursive {
+				return cli.inspectParallel(opts)
+			}
 			return cli.inspect(opts)
 		}
+		if opts.OutputFile != "" {
+			return cli.withOutputFile(opts.OutputFile, inspect)
+		}
+		return inspect()
 	}
 }
 
@@ -206,9 +256,27 @@ func registerShutdownCh() <-chan os.Signal {
 	return ch
 }
 
+// onShutdown registers a hook called before exiting on signals.
+func (cli *CLI) onShutdown(hook func()) {
+	cli.shutdownMu.Lock()
+	defer cli.shutdownMu.Unlock()
+	cli.shutdownHooks = append(cli.shutdownHooks, hook)
+}
+
+// runShutdownHooks calls the hooks registered by onShutdown.
+// It should be called by shutdown callbacks that exit the process.
+func (cli *CLI) runShutdownHooks() {
+	cli.shutdownMu.Lock()
+	defer cli.shutdownMu.Unlock()
+	for _, hook := range cli.shutdownHooks {
+		hook()
+	}
+}
+
 func (cli *CLI) registerShutdownHandler(callback func()) {
 	ch := registerShutdownCh()
 	sig := <-ch
 	fmt.Fprintf(cli.errStream, "Received %s, shutting down...\n", sig)
+	cli.interrupted.Store(true)
 	callback()
 }
@@ -3,9 +3,14 @@ package cmd
 import (
 	"errors"
 	"fmt"
+	"log"
 	"os"
+	"path/filepath"
+	"runtime"
+	"sync"
 
 	"github.com/fatih/color"
+	"github.com/mitchellh/go-homedir"
 	"github.com/spf13/afero"
 	"github.com/terraform-linters/tflint/plugin"
 	"github.com/terraform-linters/tflint/tflint"
@@ -18,7 +23,22 @@ func (cli *CLI) init(opts Options) int {
 		return ExitCodeError
 	}
 
-	installed := false
+	if opts.Mirror != "" {
+		// The mirror directory is resolved before changing directories
+		mirrorDir, err := filepath.Abs(opts.Mirror)
+		if err != nil {
+			cli.formatter.Print(tflint.Issues{}, fmt.Errorf("Failed to resolve the mirror directory; %w", err), map[string][]byte{})
+			return ExitCodeError
+		}
+		opts.Mirror = mirrorDir
+	}
+
+	targets := []*initTarget{}
+	// Plugins with the same release are installed once even if referenced from multiple working directories
+	installs := []*pluginInstall{}
+	installsByPath := map[string]*pluginInstall{}
+	resolvedVersions := map[string]string{}
+
 	for _, wd := range workingDirs {
 		err := cli.withinChangedDir(wd, func() error {
 			cfg, err := tflint.LoadConfig(afero.Afero{Fs: afero.NewOsFs()}, opts.Config)
@@ -30,6 +50,39 @@ func (cli *CLI) init(opts Options) int {
 				}
 			}
 
+			if opts.Mirror != "" {
+				return cli.mirror(cfg, opts, wd, resolvedVersions)
+			}
+
+			// Plugins are installed concurrently after leaving the working directory,
+			// so the plugin directory is resolved to an absolute path here
+			pluginDir, err := plugin.PluginDir(cfg)
+			if err != nil {
+				return fmt.Errorf("Failed to get plugin dir; %w", err)
+			}
+			if cfg.PluginDir, err = filepath.Abs(pluginDir); err != nil {
+				return fmt.Errorf("Failed to resolve the plugin dir; %w", err)
+			}
+			// Relative paths to the trusted root are also resolved for the same reason
+			for _, pluginCfg := range cfg.Plugins {
+				if pluginCfg.TrustedRoot == "" {
+					continue
+				}
+				trustedRoot, err := homedir.Expand(pluginCfg.TrustedRoot)
+				if err != nil {
+					return fmt.Errorf("Failed to resolve the trusted root of plugin \"%s\"; %w", pluginCfg.Name, err)
+				}
+				if pluginCfg.TrustedRoot, err = filepath.Abs(trustedRoot); err != nil {
+					return fmt.Errorf("Failed to resolve the trusted root of plugin \"%s\"; %w", pluginCfg.Name, err)
+				}
+			}
+
+			lock, err := plugin.LoadLockFile(plugin.LockFilePath(cfg))
+			if err != nil {
+				return fmt.Errorf("Failed to load the plugin lock file; %w", err)
+			}
+			target := &initTarget{wd: wd, lock: lock, locked: map[string]*plugin.LockedPlugin{}, pending: map[string]*pendingPlugin{}}
+
 			for _, pluginCfg := range cfg.Plugins {
 				installCfg := plugin.NewInstallConfig(cfg, pluginCfg)
 
@@ -38,44 +91,43 @@ func (cli *CLI) init(opts Options) int {
 					continue
 				}
 
-				_, err := plugin.FindPluginPath(installCfg)
-				if os.IsNotExist(err) {
-					if opts.Recursive {
-						fmt.Fprintf(cli.outStream, "Installing \"%s\" plugin in %s...\n", pluginCfg.Name, wd)
-					} else {
-						fmt.Fprintf(cli.outStream, "Installing \"%s\" plugin...\n", pluginCfg.Name)
-					}
+				installCfg, err := resolvePluginVersion(installCfg, lock, opts.UpgradeLock, resolvedVersions)
+				if err != nil {
+					return err
+				}
 
-					_, err = installCfg.Install()
-					if err != nil {
-						if errors.Is(err, plugin.ErrPluginNotVerified) {
-							if installCfg.Signature == string(plugin.SignatureModeNone) {
-								_, _ = color.New(color.FgYellow).Fprintln(cli.outStream, `The plugin signature verification is disabled. Please be aware that disabling verification can pose security risks`)
-							} else {
-								_, _ = color.New(color.FgYellow).Fprintln(cli.outStream, `No signing key or attestations found. The plugin signature is not verified`)
-							}
-							err = nil
-						} else if errors.Is(err, plugin.ErrLegacySigningKeyUsed) {
-							_, _ = color.New(color.FgYellow).Fprintln(cli.outStream, `The plugin was signed using a legacy PGP signing key. Please update the plugin to the latest version`)
-							err = nil
-						} else {
-							return fmt.Errorf("Failed to install a plugin; %w", err)
-						}
+				path, err := plugin.FindPluginPath(installCfg)
+				if err == nil && !opts.UpgradeLock {
+					// Installed plugins are reinstalled unless they match the lock file,
+					// so that the lock file records hashes of the verified release
+					if locked := lock.Locked(installCfg); locked != nil && lock.Verify(installCfg, path) == nil {
+						target.locked[pluginCfg.Name] = locked
+						continue
 					}
-
-					installed = true
-					fmt.Fprintf(cli.outStream, "Installed \"%s\" (source: %s, version: %s)\n", pluginCfg.Name, pluginCfg.Source, pluginCfg.Version)
+					err = os.ErrNotExist
 				}
-
-				if err != nil {
+				if !os.IsNotExist(err) {
 					if opts.Recursive {
 						return fmt.Errorf("Failed to find a plugin in %s; %w", wd, err)
 					} else {
 						return fmt.Errorf("Failed to find a plugin; %w", err)
 					}
 				}
+
+				installPath := filepath.Join(cfg.PluginDir, installCfg.InstallPath())
+				install, exists := installsByPath[installPath]
+				if !exists {
+					install = &pluginInstall{wd: wd, installCfg: installCfg}
+					installsByPath[installPath] = install
+					installs = append(installs, install)
+				}
+				if !opts.UpgradeLock {
+					install.previous = append(install.previous, lock.Locked(installCfg))
+				}
+				target.pending[pluginCfg.Name] = &pendingPlugin{installCfg: installCfg, install: install}
 			}
 
+			targets = append(targets, target)
 			return nil
 		})
 		if err != nil {
@@ -83,9 +135,218 @@ func (cli *CLI) init(opts Options) int {
 			return ExitCodeError
 		}
 	}
-	if !installed {
+	if opts.Mirror != "" {
+		return ExitCodeOK
+	}
+
+	if err := cli.installPlugins(installs, opts); err != nil {
+		cli.formatter.Print(tflint.Issues{}, err, map[string][]byte{})
+		return ExitCodeError
+	}
+
+	for _, target := range targets {
+		err := cli.withinChangedDir(target.wd, func() error {
+			// Plugins that are no longer configured are removed from the lock file
+			lockedPlugins := target.locked
+			for name, pending := range target.pending {
+				lockedPlugins[name] = pending.install.locked.WithConstraints(pending.installCfg)
+			}
+
+			target.lock.Plugins = lockedPlugins
+			saved, err := target.lock.Save()
+			if err != nil {
+				return fmt.Errorf("Failed to save the plugin lock file; %w", err)
+			}
+			if saved {
+				fmt.Fprintf(cli.outStream, "Updated the plugin lock file %s\n", target.lock.Path())
+			}
+			return nil
+		})
+		if err != nil {
+			cli.formatter.Print(tflint.Issues{}, err, map[string][]byte{})
+			return ExitCodeError
+		}
+	}
+	if len(installs) == 0 {
 		fmt.Fprint(cli.outStream, "All plugins are already installed\n")
 	}
 
 	return ExitCodeOK
 }
+
+// initTarget is a working directory to be initialized.
+type initTarget struct {
+	wd   string
+	lock *plugin.LockFile
+	// locked are the plugins that are already installed and match the lock file
+	locked map[string]*plugin.LockedPlugin
+	// pending are the plugins that are installed by --init, keyed by plugin names
+	pending map[string]*pendingPlugin
+}
+
+// pendingPlugin is a plugin configured in a working directory that is waiting for installation.
+type pendingPlugin struct {
+	installCfg *plugin.InstallConfig
+	install    *pluginInstall
+}
+
+// pluginInstall is a distinct plugin release to be installed.
+// A release referenced from multiple working directories is installed once, and verified
+// against the lock files of all of them.
+type pluginInstall struct {
+	// wd is the first working directory that references the release
+	wd         string
+	installCfg *plugin.InstallConfig
+	previous   []*plugin.LockedPlugin
+
+	locked *plugin.LockedPlugin
+}
+
+// installPlugins installs plugins concurrently and reports the progress per plugin.
+// The number of parallelism is controlled by --max-workers flag. The default is the number of CPUs.
+// If any installation fails, installations that have not started yet are skipped and the errors are returned.
+func (cli *CLI) installPlugins(installs []*pluginInstall, opts Options) error {
+	maxWorkers := runtime.NumCPU()
+	if opts.MaxWorkers != nil {
+		if c := *opts.MaxWorkers; c > 0 {
+			maxWorkers = c
+		}
+	}
+
+	// The mutex serializes outputs and guards errs
+	var mu sync.Mutex
+	var errs []error
+	semaphore := make(chan struct{}, maxWorkers)
+
+	var wg sync.WaitGroup
+	for _, install := range installs {
+		wg.Add(1)
+		go func(install *pluginInstall) {
+			defer wg.Done()
+
+			// Blocks from exceeding the maximum number of workers
+			semaphore <- struct{}{}
+			defer func() {
+				<-semaphore
+			}()
+
+			installCfg := install.installCfg
+			mu.Lock()
+			failed := len(errs) > 0
+			if !failed {
+				if opts.Recursive {
+					fmt.Fprintf(cli.outStream, "Installing \"%s\" plugin in %s...\n", installCfg.Name, install.wd)
+				} else {
+					fmt.Fprintf(cli.outStream, "Installing \"%s\" plugin...\n", installCfg.Name)
+				}
+			}
+			mu.Unlock()
+			if failed {
+				log.Printf("[DEBUG] Installing \"%s\" plugin is skipped due to a previous failure", installCfg.Name)
+				return
+			}
+
+			previous, err := plugin.MergeLockedPlugins(install.previous)
+			if err == nil {
+				_, install.locked, err = installCfg.InstallWithLock(previous, opts.UpgradeLock)
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil && !cli.printVerificationWarning(installCfg, err) {
+				errs = append(errs, fmt.Errorf("Failed to install \"%s\" plugin (source: %s, version: %s); %w", installCfg.Name, installCfg.Source, installCfg.Version, err))
+				return
+			}
+			fmt.Fprintf(cli.outStream, "Installed \"%s\" (source: %s, version: %s)\n", installCfg.Name, installCfg.Source, installCfg.Version)
+		}(install)
+	}
+	wg.Wait()
+
+	return errors.Join(errs...)
+}
+
+// printVerificationWarning prints a warning if the error means the plugin is installed without full verification.
+// It returns false if the error is not such a warning.
+func (cli *CLI) printVerificationWarning(installCfg *plugin.InstallConfig, err error) bool {
+	switch {
+	case errors.Is(err, plugin.ErrPluginNotVerified):
+		if installCfg.Signature == string(plugin.SignatureModeNone) {
+			_, _ = color.New(color.FgYellow).Fprintln(cli.outStream, `The plugin signature verification is disabled. Please be aware that disabling verification can pose security risks`)
+		} else {
+			_, _ = color.New(color.FgYellow).Fprintln(cli.outStream, `No signing key or attestations found. The plugin signature is not verified`)
+		}
+		return true
+	case errors.Is(err, plugin.ErrLegacySigningKeyUsed):
+		_, _ = color.New(color.FgYellow).Fprintln(cli.outStream, `The plugin was signed using a legacy PGP signing key. Please update the plugin to the latest version`)
+		return true
+	default:
+		return false
+	}
+}
+
+// mirror downloads plugins into the mirror directory for the target platforms instead of installing them.
+// The lock file is not updated since no plugins are installed.
+func (cli *CLI) mirror(cfg *tflint.Config, opts Options, wd string, resolvedVersions map[string]string) error {
+	// The lock file is only used to resolve version constraints
+	lock, err := plugin.LoadLockFile(plugin.LockFilePath(cfg))
+	if err != nil {
+		return fmt.Errorf("Failed to load the plugin lock file; %w", err)
+	}
+
+	for _, pluginCfg := range cfg.Plugins {
+		installCfg := plugin.NewInstallConfig(cfg, pluginCfg)
+
+		// Plugins installed manually cannot be mirrored
+		if installCfg.ManuallyInstalled() {
+			continue
+		}
+
+		installCfg, err := resolvePluginVersion(installCfg, lock, false, resolvedVersions)
+		if err != nil {
+			return err
+		}
+
+		if opts.Recursive {
+			fmt.Fprintf(cli.outStream, "Mirroring \"%s\" plugin in %s...\n", pluginCfg.Name, wd)
+		} else {
+			fmt.Fprintf(cli.outStream, "Mirroring \"%s\" plugin...\n", pluginCfg.Name)
+		}
+
+		dir, err := installCfg.Mirror(opts.Mirror, opts.Platforms)
+		if err != nil && !cli.printVerificationWarning(installCfg, err) {
+			return fmt.Errorf("Failed to mirror a plugin; %w", err)
+		}
+
+		fmt.Fprintf(cli.outStream, "Mirrored \"%s\" (source: %s, version: %s) to %s\n", pluginCfg.Name, pluginCfg.Source, installCfg.Version, dir)
+	}
+
+	return nil
+}
+
+// resolvePluginVersion resolves the version constraint of the plugin.
+// The version recorded in the lock file is preferred as long as it satisfies the constraint,
+// so that the same version is installed until the lock file is upgraded.
+// Otherwise, the constraint is resolved to the newest matching release.
+// Resolved versions are cached by source and constraint, so that releases are listed once across working directories.
+func resolvePluginVersion(installCfg *plugin.InstallConfig, lock *plugin.LockFile, upgrade bool, resolvedVersions map[string]string) (*plugin.InstallConfig, error) {
+	if !installCfg.HasVersionConstraints() {
+		return installCfg, nil
+	}
+
+	if !upgrade {
+		if resolved := lock.Resolve(installCfg); resolved != nil {
+			return resolved, nil
+		}
+	}
+
+	key := fmt.Sprintf("%s@%s", installCfg.Source, installCfg.Version)
+	if v, exists := resolvedVersions[key]; exists {
+		return installCfg.WithVersion(v), nil
+	}
+	resolved, err := installCfg.ResolveVersion()
+	if err != nil {
+		return nil, fmt.Errorf("Failed to resolve the version of a plugin; %w", err)
+	}
+	resolvedVersions[key] = resolved.Version
+	return resolved, nil
+}
@@ -0,0 +1,226 @@
+package cmd
+
+import (
+	"archive/zip"
+	"bytes"
+	"crypto/sha256"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/terraform-linters/tflint/plugin"
+)
+
+// writeInitRelease writes an unsigned v0.1.0 release of the "foo" plugin for the current platform
+// to the directory in the layout of file sources, and returns the source URL.
+func writeInitRelease(t *testing.T, dir string) string {
+	t.Helper()
+
+	releaseDir := filepath.Join(dir, "v0.1.0")
+	if err := os.MkdirAll(releaseDir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	buf := new(bytes.Buffer)
+	zw := zip.NewWriter(buf)
+	w, err := zw.Create("tflint-ruleset-foo" + fileExt())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := w.Write([]byte("binary")); err != nil {
+		t.Fatal(err)
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	assetName := fmt.Sprintf("tflint-ruleset-foo_%s_%s.zip", runtime.GOOS, runtime.GOARCH)
+	if err := os.WriteFile(filepath.Join(releaseDir, assetName), buf.Bytes(), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	checksums := fmt.Sprintf("%x  %s\n", sha256.Sum256(buf.Bytes()), assetName)
+	if err := os.WriteFile(filepath.Join(releaseDir, "checksums.txt"), []byte(checksums), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	if runtime.GOOS == "windows" {
+		return "file:///" + filepath.ToSlash(dir)
+	}
+	return "file://" + filepath.ToSlash(dir)
+}
+
+func Test_init_recursive(t *testing.T) {
+	dir := t.TempDir()
+	sourceURL := writeInitRelease(t, filepath.Join(dir, "source"))
+	t.Setenv("TFLINT_PLUGIN_DIR", filepath.Join(dir, "plugins"))
+
+	workDir := filepath.Join(dir, "work")
+	configs := map[string]string{
+		"a": "0.1.0",
+		"b": "0.1.0",
+		"c": "~> 0.1",
+	}
+	for name, version := range configs {
+		config := fmt.Sprintf(`
+plugin "foo" {
+  enabled = true
+  version = "%s"
+  source  = "%s"
+}`, version, sourceURL)
+		if err := os.MkdirAll(filepath.Join(workDir, name), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(workDir, name, ".tflint.hcl"), []byte(config), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	t.Chdir(workDir)
+
+	stdout, stderr := new(bytes.Buffer), new(bytes.Buffer)
+	cli, err := NewCLI(stdout, stderr)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if status := cli.Run([]string{"tflint", "--recursive", "--init"}); status != ExitCodeOK {
+		t.Fatalf("unexpected exit status: %d, stdout: %s, stderr: %s", status, stdout, stderr)
+	}
+	// The same release is installed once for all directories
+	if count := strings.Count(stdout.String(), `Installing "foo" plugin in a...`); count != 1 {
+		t.Errorf("expected to install the plugin once, but got %d times: %s", count, stdout)
+	}
+	if count := strings.Count(stdout.String(), `Installed "foo"`); count != 1 {
+		t.Errorf("expected to install the plugin once, but got %d times: %s", count, stdout)
+	}
+
+	for name, version := range configs {
+		lock, err := plugin.LoadLockFile(filepath.Join(workDir, name, plugin.LockFileName))
+		if err != nil {
+			t.Fatal(err)
+		}
+		locked, exists := lock.Plugins["foo"]
+		if !exists {
+			t.Fatalf("plugin is not locked in %s", name)
+		}
+		if locked.Version != "0.1.0" {
+			t.Errorf("unexpected locked version in %s: %s", name, locked.Version)
+		}
+		// Constraints are recorded per config
+		expected := ""
+		if strings.HasPrefix(version, "~>") {
+			expected = version
+		}
+		if locked.Constraints != expected {
+			t.Errorf("unexpected constraints in %s: %s", name, locked.Constraints)
+		}
+	}
+
+	stdout.Reset()
+	if status := cli.Run([]string{"tflint", "--recursive", "--init"}); status != ExitCodeOK {
+		t.Fatalf("unexpected exit status: %d, stdout: %s, stderr: %s", status, stdout, stderr)
+	}
+	if !strings.Contains(stdout.String(), "All plugins are already installed") {
+		t.Errorf("unexpected output: %s", stdout)
+	}
+}
+
+func Test_init_recursiveFailure(t *testing.T) {
+	dir := t.TempDir()
+	sourceURL := writeInitRelease(t, filepath.Join(dir, "source"))
+	t.Setenv("TFLINT_PLUGIN_DIR", filepath.Join(dir, "plugins"))
+
+	workDir := filepath.Join(dir, "work")
+	for name, version := range map[string]string{"a": "0.1.0", "b": "0.2.0"} {
+		config := fmt.Sprintf(`
+plugin "foo" {
+  enabled = true
+  version = "%s"
+  source  = "%s"
+}`, version, sourceURL)
+		if err := os.MkdirAll(filepath.Join(workDir, name), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(workDir, name, ".tflint.hcl"), []byte(config), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	t.Chdir(workDir)
+
+	stdout, stderr := new(bytes.Buffer), new(bytes.Buffer)
+	cli, err := NewCLI(stdout, stderr)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if status := cli.Run([]string{"tflint", "--recursive", "--init", "--max-workers=1"}); status != ExitCodeError {
+		t.Fatalf("unexpected exit status: %d, stdout: %s, stderr: %s", status, stdout, stderr)
+	}
+	if !strings.Contains(stderr.String(), fmt.Sprintf(`Failed to install "foo" plugin (source: %s, version: 0.2.0)`, sourceURL)) {
+		t.Errorf("unexpected stderr: %s", stderr)
+	}
+	// Lock files are not updated if any installation fails
+	for _, name := range []string{"a", "b"} {
+		if _, err := os.Stat(filepath.Join(workDir, name, plugin.LockFileName)); !os.IsNotExist(err) {
+			t.Errorf("expected the lock file in %s not to be created, but got %v", name, err)
+		}
+	}
+}
+
+func Test_init_recursiveTrustedRoot(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("TFLINT_PLUGIN_DIR", filepath.Join(dir, "plugins"))
+
+	// The mirror has attestations, but no trusted root, so that the configured trusted root is loaded
+	mirror := filepath.Join(dir, "mirror")
+	releaseDir := filepath.Join(mirror, "github.com", "example", "tflint-ruleset-foo", "v0.1.0")
+	if err := os.MkdirAll(releaseDir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	files := map[string]string{
+		"checksums.txt":                   "checksums",
+		"checksums.txt.attestations.json": `[{"bundle":{"mediaType":"application/vnd.dev.sigstore.bundle.v0.3+json"},"repository_id":1}]`,
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(releaseDir, name), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	t.Setenv("TFLINT_PLUGIN_MIRROR", mirror)
+
+	workDir := filepath.Join(dir, "work")
+	config := `
+plugin "foo" {
+  enabled      = true
+  version      = "0.1.0"
+  source       = "github.com/example/tflint-ruleset-foo"
+  signature    = "attestation"
+  trusted_root = "trusted_root.json"
+}`
+	if err := os.MkdirAll(filepath.Join(workDir, "nested"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(workDir, "nested", ".tflint.hcl"), []byte(config), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(workDir, "nested", "trusted_root.json"), []byte("invalid"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	t.Chdir(workDir)
+
+	stdout, stderr := new(bytes.Buffer), new(bytes.Buffer)
+	cli, err := NewCLI(stdout, stderr)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if status := cli.Run([]string{"tflint", "--recursive", "--init"}); status != ExitCodeError {
+		t.Fatalf("unexpected exit status: %d, stdout: %s, stderr: %s", status, stdout, stderr)
+	}
+	// The relative path is resolved in the working directory, not in the directory where plugins are installed
+	want := fmt.Sprintf("failed to load trusted root from %s: ", filepath.Join(workDir, "nested", "trusted_root.json"))
+	if !strings.Contains(stderr.String(), want) || strings.Contains(stderr.String(), "no such file") {
+		t.Errorf("unexpected stderr: %s", stderr)
+	}
+}
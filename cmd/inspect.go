@@ -1,24 +1,47 @@
 package cmd
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"log"
 	"maps"
 	"os"
 	"path/filepath"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"time"
 
+	"github.com/hashicorp/go-version"
 	"github.com/hashicorp/hcl/v2"
 	"github.com/spf13/afero"
 	"github.com/terraform-linters/tflint-plugin-sdk/hclext"
+	"github.com/terraform-linters/tflint-plugin-sdk/plugin/host2plugin"
+	sdk "github.com/terraform-linters/tflint-plugin-sdk/tflint"
+	"github.com/terraform-linters/tflint/formatter"
 	"github.com/terraform-linters/tflint/plugin"
 	"github.com/terraform-linters/tflint/terraform"
 	"github.com/terraform-linters/tflint/tflint"
 )
 
-func (cli *CLI) inspect(opts Options) int {
+func (cli *CLI) inspect(opts Options) (status int) {
+	start := time.Now()
 	issues := tflint.Issues{}
 	changes := map[string][]byte{}
+	var pluginErrs []error
+	if opts.Summary {
+		cli.summary = tflint.NewSummary()
+	}
+	if opts.Profile != "" {
+		cli.profile = tflint.NewProfile()
+		// Workers return the profile to the coordinator instead of writing it
+		if !opts.ActAsWorker {
+			defer func() { status = cli.writeProfileOnExit(opts.Profile, status) }()
+		}
+	}
 
 	err := cli.withinChangedDir(opts.Chdir, func() error {
 		filterFiles := []string{}
@@ -40,7 +63,7 @@ func (cli *CLI) inspect(opts Options) int {
 		}
 
 		var err error
-		issues, changes, err = cli.inspectModule(opts, ".", filterFiles)
+		issues, changes, pluginErrs, err = cli.inspectModule(opts, ".", filterFiles)
 		return err
 	})
 	if err != nil {
@@ -55,14 +78,37 @@ func (cli *CLI) inspect(opts Options) int {
 	if opts.ActAsWorker {
 		// When acting as a recursive inspection worker, the formatter is ignored
 		// and the serialized issues are output.
-		out, err := json.Marshal(issues)
+		workerOut := workerOutput{Issues: issues, Changes: changes, Suggestions: cli.suggestions, Sources: map[string][]byte{}, Summary: cli.summary, Profile: cli.profile}
+		for path := range changes {
+			if source, exists := cli.sources[path]; exists {
+				workerOut.Sources[path] = source
+			}
+		}
+		for _, suggestions := range cli.suggestions {
+			for path := range suggestions {
+				if source, exists := cli.sources[path]; exists {
+					workerOut.Sources[path] = source
+				}
+			}
+		}
+		for _, err := range pluginErrs {
+			workerOut.PluginErrors = append(workerOut.PluginErrors, err.Error())
+		}
+		out, err := json.Marshal(workerOut)
 		if err != nil {
 			fmt.Fprint(cli.errStream, err)
 			return ExitCodeError
 		}
 		fmt.Fprint(cli.outStream, string(out))
 	} else {
-		cli.formatter.Print(issues, nil, cli.sources)
+		if cli.summary != nil {
+			cli.summary.Elapsed = time.Since(start)
+			cli.formatter.Summary = cli.summary
+		}
+		cli.formatter.Changes = changes
+		cli.formatter.Suggestions = cli.suggestions
+		// Failed plugins are reported together with issues found by other plugins
+		cli.formatter.Print(issues, errors.Join(pluginErrs...), cli.sources)
 	}
 
 	if opts.Fix {
@@ -72,6 +118,10 @@ func (cli *CLI) inspect(opts Options) int {
 		}
 	}
 
+	if len(pluginErrs) > 0 {
+		return ExitCodeError
+	}
+
 	if len(issues) > 0 && !cli.config.Force && exceedsMinimumFailure(issues, opts.MinimumFailureSeverity) {
 		return ExitCodeIssuesFound
 	}
@@ -79,62 +129,90 @@ func (cli *CLI) inspect(opts Options) int {
 	return ExitCodeOK
 }
 
-func (cli *CLI) inspectModule(opts Options, dir string, filterFiles []string) (tflint.Issues, map[string][]byte, error) {
+// inspectModule inspects the module in the directory and returns issues and changes by autofixes.
+// Errors of failed plugins are returned separately unless --fail-on-plugin-error is given,
+// so that issues found by other plugins can still be reported.
+func (cli *CLI) inspectModule(opts Options, dir string, filterFiles []string) (tflint.Issues, map[string][]byte, []error, error) {
 	issues := tflint.Issues{}
 	changes := map[string][]byte{}
 	var err error
 
 	// Setup config
+	start := time.Now()
+	endProfile := cli.profile.Start(&tflint.ProfileEvent{Category: tflint.ProfileCategoryTFLint, Name: "LoadConfig"})
 	cli.config, err = tflint.LoadConfig(afero.Afero{Fs: afero.NewOsFs()}, opts.Config)
+	endProfile()
 	if err != nil {
-		return issues, changes, fmt.Errorf("Failed to load TFLint config; %w", err)
+		return issues, changes, nil, fmt.Errorf("Failed to load TFLint config; %w", err)
 	}
 	cli.config.Merge(opts.toConfig())
 	// Apply format set in config file
 	cli.formatter.Format = cli.config.Format
+	cli.recordPhase(tflint.PhaseConfig, start)
 
 	// Setup loader
+	start = time.Now()
 	cli.loader, err = terraform.NewLoader(afero.Afero{Fs: afero.NewOsFs()}, cli.originalWorkingDir)
 	if err != nil {
-		return issues, changes, fmt.Errorf("Failed to prepare loading; %w", err)
+		return issues, changes, nil, fmt.Errorf("Failed to prepare loading; %w", err)
 	}
 	if opts.ActAsWorker && !cli.loader.IsConfigDir(dir) {
 		// Ignore non-module directories in worker mode
-		return issues, changes, nil
+		return issues, changes, nil, nil
 	}
 
 	// Setup runners
-	rootRunner, moduleRunners, err := tflint.BuildRunners(cli.loader, cli.config, cli.originalWorkingDir, dir)
+	endProfile = cli.profile.Start(&tflint.ProfileEvent{Category: tflint.ProfileCategoryTFLint, Name: "BuildRunners"})
+	rootRunner, moduleRunners, err := tflint.BuildRunners(context.Background(), cli.loader, cli.config, cli.originalWorkingDir, dir)
+	endProfile()
 	if err != nil {
-		return issues, changes, err
+		return issues, changes, nil, err
 	}
+	cli.recordPhase(tflint.PhaseLoading, start)
 
 	// Launch plugin processes
-	rulesetPlugin, err := launchPlugins(cli.config, opts.Fix)
+	start = time.Now()
+	rulesetPlugin, err := launchPlugins(cli.config, opts.Fix, cli.profile)
 	if rulesetPlugin != nil {
 		defer rulesetPlugin.Clean()
 		go cli.registerShutdownHandler(func() {
 			rulesetPlugin.Clean()
+			cli.runShutdownHooks()
 			os.Exit(ExitCodeError)
 		})
 	}
 	if err != nil {
-		return issues, changes, err
+		return issues, changes, nil, err
 	}
 
 	// Validate and collect plugin versions
 	sdkVersions, err := plugin.ValidatePluginVersions(rulesetPlugin, cli.config.IsJSONConfig())
 	if err != nil {
-		return issues, changes, err
+		return issues, changes, nil, err
+	}
+	// Rule names are unique across plugins, so issues can be attributed to plugins by rule names.
+	// They are collected before the inspection because failed plugins may not respond anymore.
+	rulePlugins := map[string]string{}
+	for name, ruleset := range rulesetPlugin.RuleSets {
+		ruleNames, err := ruleset.RuleNames()
+		if err != nil {
+			return issues, changes, nil, fmt.Errorf(`Failed to get rule names from "%s" plugin; %w`, name, err)
+		}
+		for _, ruleName := range ruleNames {
+			rulePlugins[ruleName] = name
+		}
 	}
+	cli.recordPhase(tflint.PhasePluginLaunch, start)
 
 	// Run inspection
 	//
 	// Repeat an inspection until there are no more changes or the limit is reached,
 	// in case an autofix introduces new issues.
+	start = time.Now()
+	pluginErrs := map[string]error{}
 	for loop := 1; ; loop++ {
 		if loop > 10 {
-			return issues, changes, fmt.Errorf(`Reached the limit of autofix attempts, and the changes made by the autofix will not be applied. This may be due to the following reasons:
+			return issues, changes, nil, fmt.Errorf(`Reached the limit of autofix attempts, and the changes made by the autofix will not be applied. This may be due to the following reasons:
 
 1. The autofix is making changes that do not fix the issue.
 2. The autofix is continuing to introduce new issues.
@@ -142,30 +220,8 @@ func (cli *CLI) inspectModule(opts Options, dir string, filterFiles []string) (t
 By setting TFLINT_LOG=trace, you can confirm the changes made by the autofix and start troubleshooting.`)
 		}
 
-		for name, ruleset := range rulesetPlugin.RuleSets {
-			if err := ruleset.Check(plugin.NewGRPCServer(rootRunner, rootRunner, cli.loader.Files(), sdkVersions[name])); err != nil {
-				return issues, changes, fmt.Errorf("Failed to check ruleset; %w", err)
-			}
-			// Run checks for module calls are performed in parallel.
-			// The rootRunner is shared between goroutines but read-only, so this is goroutine-safe.
-			// Note that checks against the rootRunner are not parallelized, as autofix may cause the module to be rebuilt.
-			ch := make(chan error, len(moduleRunners))
-			for _, runner := range moduleRunners {
-				if opts.NoParallelRunners {
-					ch <- ruleset.Check(plugin.NewGRPCServer(runner, rootRunner, cli.loader.Files(), sdkVersions[name]))
-				} else {
-					go func(runner *tflint.Runner) {
-						ch <- ruleset.Check(plugin.NewGRPCServer(runner, rootRunner, cli.loader.Files(), sdkVersions[name]))
-					}(runner)
-				}
-			}
-			for range moduleRunners {
-				err = <-ch
-				if err != nil {
-					return issues, changes, fmt.Errorf("Failed to check ruleset; %w", err)
-				}
-			}
-			close(ch)
+		if err := cli.checkRulesets(opts, rulesetPlugin, pluginErrs, rootRunner, moduleRunners, sdkVersions); err != nil {
+			return issues, changes, nil, err
 		}
 
 		changesInAttempt := map[string][]byte{}
@@ -189,14 +245,239 @@ By setting TFLINT_LOG=trace, you can confirm the changes made by the autofix and
 			break
 		}
 	}
+	if cli.suggestsChanges(opts) {
+		if err := cli.collectSuggestions(opts, dir, filterFiles, issues, rulePlugins, rulesetPlugin, pluginErrs, sdkVersions); err != nil {
+			return issues, changes, nil, err
+		}
+	}
+	// Plugins may fail in a later attempt, so issues found by them in earlier attempts are also dropped.
+	// Issues emitted before the failure may be incomplete, so they are not reported.
+	issues = slices.DeleteFunc(issues, func(issue *tflint.Issue) bool {
+		_, failed := pluginErrs[rulePlugins[issue.Rule.Name()]]
+		return failed
+	})
+
+	cli.recordPhase(tflint.PhaseCheck, start)
 
 	// Set module sources to CLI
 	maps.Copy(cli.sources, cli.loader.Sources())
 
-	return issues, changes, nil
+	if cli.summary != nil {
+		summaryDir := opts.Chdir
+		if summaryDir == "" {
+			summaryDir = "."
+		}
+		cli.summary.AddIssues(summaryDir, issues, rulePlugins)
+		cli.summary.Files += len(cli.loader.Files())
+		cli.summary.ModuleRunners += len(moduleRunners)
+	}
+
+	return issues, changes, sortedPluginErrors(pluginErrs), nil
 }
 
-func launchPlugins(config *tflint.Config, fix bool) (*plugin.Plugin, error) {
+// sortedPluginErrors returns the errors of failed plugins sorted by plugin names.
+func sortedPluginErrors(pluginErrs map[string]error) []error {
+	errs := make([]error, 0, len(pluginErrs))
+	for _, name := range slices.Sorted(maps.Keys(pluginErrs)) {
+		errs = append(errs, pluginErrs[name])
+	}
+	return errs
+}
+
+// suggestsChanges returns whether changes by autofixes are collected as suggestions without --fix.
+// Workers are told by the coordinator, since the format of workers is ignored.
+func (cli *CLI) suggestsChanges(opts Options) bool {
+	if opts.Fix {
+		return false
+	}
+	if opts.ActAsWorker {
+		return opts.SuggestChanges
+	}
+	return formatter.SuggestsChanges(cli.formatter.Format)
+}
+
+// collectSuggestions runs rulesets that emitted fixable issues again with autofix enabled, and records
+// the changes as suggestions for the fixable rules. Each ruleset is run against its own runners built
+// from the original files, so that it does not see changes made by other rulesets.
+func (cli *CLI) collectSuggestions(opts Options, dir string, filterFiles []string, issues tflint.Issues, rulePlugins map[string]string, rulesetPlugin *plugin.Plugin, pluginErrs map[string]error, sdkVersions map[string]*version.Version) error {
+	fixable := map[string]bool{}
+	for _, issue := range issues {
+		if issue.Fixable {
+			fixable[rulePlugins[issue.Rule.Name()]] = true
+		}
+	}
+
+	pluginConf := cli.config.ToPluginConfig()
+	pluginConf.Fix = true
+
+	for _, name := range slices.Sorted(maps.Keys(rulesetPlugin.RuleSets)) {
+		if _, failed := pluginErrs[name]; failed || !fixable[name] {
+			continue
+		}
+		ruleset := rulesetPlugin.RuleSets[name]
+
+		if err := applyPluginConfig(cli.config, name, ruleset, pluginConf, cli.profile); err != nil {
+			return err
+		}
+		rootRunner, moduleRunners, err := tflint.BuildRunners(context.Background(), cli.loader, cli.config, cli.originalWorkingDir, dir)
+		if err != nil {
+			return err
+		}
+		if err := cli.checkRuleset(opts, rulesetPlugin, name, ruleset, rootRunner, moduleRunners, sdkVersions[name]); err != nil {
+			if err := cli.handlePluginError(opts, rulesetPlugin, pluginErrs, name, err); err != nil {
+				return err
+			}
+			continue
+		}
+
+		for _, runner := range append(moduleRunners, rootRunner) {
+			changes := runner.LookupChanges(filterFiles...)
+			if len(changes) == 0 {
+				continue
+			}
+			for _, issue := range runner.LookupIssues(filterFiles...) {
+				if !issue.Fixable {
+					continue
+				}
+				if cli.suggestions[issue.Rule.Name()] == nil {
+					cli.suggestions[issue.Rule.Name()] = map[string][]byte{}
+				}
+				maps.Copy(cli.suggestions[issue.Rule.Name()], changes)
+			}
+		}
+	}
+	return nil
+}
+
+// checkRulesets runs rulesets of all plugins except failed ones, and records errors of newly failed plugins.
+// Rulesets are checked concurrently unless autofix is enabled. With autofix, changes made by a ruleset
+// rebuild the module read by other rulesets, so they are checked one by one in the order of plugin names.
+func (cli *CLI) checkRulesets(opts Options, rulesetPlugin *plugin.Plugin, pluginErrs map[string]error, rootRunner *tflint.Runner, moduleRunners []*tflint.Runner, sdkVersions map[string]*version.Version) error {
+	names := []string{}
+	for _, name := range slices.Sorted(maps.Keys(rulesetPlugin.RuleSets)) {
+		// Failed plugins may be in a broken state, so they are not used in later attempts
+		if _, failed := pluginErrs[name]; !failed {
+			names = append(names, name)
+		}
+	}
+
+	errs := make([]error, len(names))
+	if opts.Fix || opts.NoParallelRunners {
+		for i, name := range names {
+			errs[i] = cli.checkRuleset(opts, rulesetPlugin, name, rulesetPlugin.RuleSets[name], rootRunner, moduleRunners, sdkVersions[name])
+			if errs[i] != nil && opts.FailOnPluginError {
+				break
+			}
+		}
+	} else {
+		// Runners are shared between goroutines. This is goroutine-safe as long as autofix is not applied.
+		// See also GRPCServer and Runner.EmitIssue.
+		var wg sync.WaitGroup
+		for i, name := range names {
+			wg.Add(1)
+			go func(i int, name string) {
+				defer wg.Done()
+				errs[i] = cli.checkRuleset(opts, rulesetPlugin, name, rulesetPlugin.RuleSets[name], rootRunner, moduleRunners, sdkVersions[name])
+			}(i, name)
+		}
+		wg.Wait()
+	}
+
+	for i, name := range names {
+		if errs[i] == nil {
+			continue
+		}
+		if err := cli.handlePluginError(opts, rulesetPlugin, pluginErrs, name, errs[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// handlePluginError records the error of the failed plugin and kills it.
+// If --fail-on-plugin-error is given, the error is returned instead.
+func (cli *CLI) handlePluginError(opts Options, rulesetPlugin *plugin.Plugin, pluginErrs map[string]error, name string, checkErr error) error {
+	err := fmt.Errorf(`Failed to check ruleset of "%s" plugin; %w`, name, checkErr)
+	if opts.FailOnPluginError {
+		return err
+	}
+	// Report the failure with issues from other plugins instead of aborting the inspection
+	log.Printf("[ERROR] %s", err)
+	pluginErrs[name] = err
+	rulesetPlugin.Kill(name)
+	return nil
+}
+
+// checkRuleset runs the ruleset against the root module and module calls.
+// If the timeout of the plugin is exceeded, the plugin process is killed so that the pending checks fail.
+func (cli *CLI) checkRuleset(opts Options, rulesetPlugin *plugin.Plugin, name string, ruleset *host2plugin.Client, rootRunner *tflint.Runner, moduleRunners []*tflint.Runner, sdkVersion *version.Version) error {
+	var timeout time.Duration
+	if pluginCfg, exists := cli.config.Plugins[name]; exists {
+		timeout = pluginCfg.CheckTimeout
+	}
+	var timedOut atomic.Bool
+	if timeout > 0 {
+		timer := time.AfterFunc(timeout, func() {
+			log.Printf(`[ERROR] "%s" plugin timed out after %s, killing the plugin process`, name, timeout)
+			timedOut.Store(true)
+			rulesetPlugin.Kill(name)
+		})
+		defer timer.Stop()
+	}
+
+	err := cli.runRulesetCheck(opts, name, ruleset, rootRunner, moduleRunners, sdkVersion)
+	if err != nil && timedOut.Load() {
+		return fmt.Errorf("timed out after %s", timeout)
+	}
+	return err
+}
+
+// runRulesetCheck runs checks for the root module and then module calls.
+func (cli *CLI) runRulesetCheck(opts Options, name string, ruleset *host2plugin.Client, rootRunner *tflint.Runner, moduleRunners []*tflint.Runner, sdkVersion *version.Version) error {
+	if err := cli.checkRunner(name, ruleset, rootRunner, rootRunner, sdkVersion); err != nil {
+		return err
+	}
+	// Run checks for module calls are performed in parallel.
+	// The rootRunner is shared between goroutines but read-only, so this is goroutine-safe.
+	// Note that checks against the rootRunner are not parallelized, as autofix may cause the module to be rebuilt.
+	ch := make(chan error, len(moduleRunners))
+	for _, runner := range moduleRunners {
+		if opts.NoParallelRunners {
+			ch <- cli.checkRunner(name, ruleset, runner, rootRunner, sdkVersion)
+		} else {
+			go func(runner *tflint.Runner) {
+				ch <- cli.checkRunner(name, ruleset, runner, rootRunner, sdkVersion)
+			}(runner)
+		}
+	}
+	// All checks are waited for even if one fails, so that no issues are emitted after returning
+	var err error
+	for range moduleRunners {
+		if checkErr := <-ch; checkErr != nil && err == nil {
+			err = checkErr
+		}
+	}
+	close(ch)
+	return err
+}
+
+// checkRunner runs the ruleset of the named plugin against the runner.
+func (cli *CLI) checkRunner(name string, ruleset *host2plugin.Client, runner *tflint.Runner, rootRunner *tflint.Runner, sdkVersion *version.Version) error {
+	defer cli.profile.Start(&tflint.ProfileEvent{Category: tflint.ProfileCategoryPlugin, Name: "Check", Plugin: name, Module: runner.ModulePath()})()
+
+	return ruleset.Check(plugin.NewGRPCServer(context.Background(), runner, rootRunner, cli.loader.Files(), sdkVersion).WithProfile(cli.profile, name))
+}
+
+// recordPhase adds the time elapsed since start to the given phase of the summary.
+// It does nothing unless --summary is given.
+func (cli *CLI) recordPhase(phase string, start time.Time) {
+	if cli.summary == nil {
+		return
+	}
+	cli.summary.Phases[phase] += time.Since(start)
+}
+
+func launchPlugins(config *tflint.Config, fix bool, profile *tflint.Profile) (*plugin.Plugin, error) {
 	// Lookup plugins
 	rulesetPlugin, err := plugin.Discovery(config)
 	if err != nil {
@@ -223,24 +504,8 @@ func launchPlugins(config *tflint.Config, fix bool) (*plugin.Plugin, error) {
 			return rulesetPlugin, err
 		}
 
-		if err := ruleset.ApplyGlobalConfig(pluginConf); err != nil {
-			return rulesetPlugin, fmt.Errorf(`Failed to apply global config to "%s" plugin; %w`, name, err)
-		}
-		configSchema, err := ruleset.ConfigSchema()
-		if err != nil {
-			return rulesetPlugin, fmt.Errorf(`Failed to fetch config schema from "%s" plugin; %w`, name, err)
-		}
-		content := &hclext.BodyContent{}
-		if plugin, exists := config.Plugins[name]; exists {
-			var diags hcl.Diagnostics
-			content, diags = plugin.Content(configSchema)
-			if diags.HasErrors() {
-				return rulesetPlugin, fmt.Errorf(`Failed to parse "%s" plugin config; %w`, name, diags)
-			}
-		}
-		err = ruleset.ApplyConfig(content, config.Sources())
-		if err != nil {
-			return rulesetPlugin, fmt.Errorf(`Failed to apply config to "%s" plugin; %w`, name, err)
+		if err := applyPluginConfig(config, name, ruleset, pluginConf, profile); err != nil {
+			return rulesetPlugin, err
 		}
 
 		rulesets = append(rulesets, ruleset)
@@ -254,6 +519,59 @@ func launchPlugins(config *tflint.Config, fix bool) (*plugin.Plugin, error) {
 	return rulesetPlugin, nil
 }
 
+// applyPluginConfig applies the global config and the plugin config to the ruleset.
+func applyPluginConfig(config *tflint.Config, name string, ruleset *host2plugin.Client, pluginConf *sdk.Config, profile *tflint.Profile) error {
+	endProfile := profile.Start(&tflint.ProfileEvent{Category: tflint.ProfileCategoryPlugin, Name: "ApplyGlobalConfig", Plugin: name})
+	err := ruleset.ApplyGlobalConfig(pluginConf)
+	endProfile()
+	if err != nil {
+		return fmt.Errorf(`Failed to apply global config to "%s" plugin; %w`, name, err)
+	}
+	configSchema, err := ruleset.ConfigSchema()
+	if err != nil {
+		return fmt.Errorf(`Failed to fetch config schema from "%s" plugin; %w`, name, err)
+	}
+	content := &hclext.BodyContent{}
+	if plugin, exists := config.Plugins[name]; exists {
+		var diags hcl.Diagnostics
+		content, diags = plugin.Content(configSchema)
+		if diags.HasErrors() {
+			return fmt.Errorf(`Failed to parse "%s" plugin config; %w`, name, diags)
+		}
+	}
+	endProfile = profile.Start(&tflint.ProfileEvent{Category: tflint.ProfileCategoryPlugin, Name: "ApplyConfig", Plugin: name})
+	err = ruleset.ApplyConfig(content, config.Sources())
+	endProfile()
+	if err != nil {
+		return fmt.Errorf(`Failed to apply config to "%s" plugin; %w`, name, err)
+	}
+	return nil
+}
+
+// writeProfileOnExit writes the profile and returns the exit status. It is deferred so that
+// the profile is written even if the inspection fails, such as config or plugin errors.
+func (cli *CLI) writeProfileOnExit(path string, status int) int {
+	if err := writeProfile(path, cli.profile); err != nil {
+		cli.formatter.Print(tflint.Issues{}, err, cli.sources)
+		return ExitCodeError
+	}
+	return status
+}
+
+// writeProfile writes the timing profile to the file.
+func writeProfile(path string, profile *tflint.Profile) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("Failed to write profile; %w", err)
+	}
+	defer f.Close()
+
+	if err := profile.Write(f); err != nil {
+		return fmt.Errorf("Failed to write profile; %w", err)
+	}
+	return nil
+}
+
 func writeChanges(changes map[string][]byte) error {
 	fs := afero.NewOsFs()
 	for path, source := range changes {
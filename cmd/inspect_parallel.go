@@ -8,6 +8,7 @@ import (
 	"fmt"
 	"io"
 	"log"
+	"maps"
 	"os"
 	"os/exec"
 	"runtime"
@@ -17,6 +18,20 @@ import (
 	"github.com/terraform-linters/tflint/tflint"
 )
 
+// workerOutput is the serialized result that a worker process outputs to stdout.
+type workerOutput struct {
+	Issues  tflint.Issues     `json:"issues"`
+	Changes map[string][]byte `json:"changes,omitempty"`
+	// Suggestions are the sources fixed by each plugin separately, keyed by rule names and file paths.
+	Suggestions map[string]map[string][]byte `json:"suggestions,omitempty"`
+	// Sources are the original sources of changed files, which are required to suggest changes.
+	Sources map[string][]byte `json:"sources,omitempty"`
+	Summary *tflint.Summary   `json:"summary,omitempty"`
+	Profile *tflint.Profile   `json:"profile,omitempty"`
+	// PluginErrors are the errors of failed plugins, which are reported with issues found by other plugins.
+	PluginErrors []string `json:"plugin_errors,omitempty"`
+}
+
 // worker is a struct to store the result of each directory
 type worker struct {
 	dir    string
@@ -25,7 +40,16 @@ type worker struct {
 	err    error
 }
 
-func (cli *CLI) inspectParallel(opts Options) int {
+func (cli *CLI) inspectParallel(opts Options) (status int) {
+	start := time.Now()
+	if opts.Summary {
+		cli.summary = tflint.NewSummary()
+	}
+	if opts.Profile != "" {
+		cli.profile = tflint.NewProfile()
+		defer func() { status = cli.writeProfileOnExit(opts.Profile, status) }()
+	}
+
 	workingDirs, err := findWorkingDirs(opts)
 	if err != nil {
 		cli.formatter.Print(tflint.Issues{}, fmt.Errorf("Failed to find workspaces; %w", err), map[string][]byte{})
@@ -43,17 +67,18 @@ func (cli *CLI) inspectParallel(opts Options) int {
 	}
 
 	issues := tflint.Issues{}
+	changes := map[string][]byte{}
 	var canceled bool
 
 	for worker := range workers {
 		stdout, err := io.ReadAll(worker.stdout)
 		if err != nil {
-			cli.formatter.PrintErrorParallel(fmt.Errorf("Failed to read stdout in %s; %w", worker.dir, err), cli.sources)
+			cli.formatter.PrintErrorParallel(worker.dir, fmt.Errorf("Failed to read stdout in %s; %w", worker.dir, err), cli.sources)
 			continue
 		}
 		stderr, err := io.ReadAll(worker.stderr)
 		if err != nil {
-			cli.formatter.PrintErrorParallel(fmt.Errorf("Failed to read stderr in %s; %w", worker.dir, err), cli.sources)
+			cli.formatter.PrintErrorParallel(worker.dir, fmt.Errorf("Failed to read stderr in %s; %w", worker.dir, err), cli.sources)
 			continue
 		}
 		if worker.err != nil {
@@ -64,16 +89,35 @@ func (cli *CLI) inspectParallel(opts Options) int {
 			}
 
 			log.Printf("[DEBUG] Failed to run in %s; %s; stdout=%s", worker.dir, worker.err, stdout)
-			cli.formatter.PrintErrorParallel(fmt.Errorf("Failed to run in %s; %w\n\n%s", worker.dir, worker.err, stderr), cli.sources)
+			cli.formatter.PrintErrorParallel(worker.dir, fmt.Errorf("Failed to run in %s; %w\n\n%s", worker.dir, worker.err, stderr), cli.sources)
 			continue
 		}
 
-		var workerIssues tflint.Issues
-		if err := json.Unmarshal(stdout, &workerIssues); err != nil {
+		var out workerOutput
+		if err := json.Unmarshal(stdout, &out); err != nil {
 			panic(fmt.Errorf("failed to parse issues in %s; %s; stdout=%s; stderr=%s", worker.dir, err, stdout, stderr))
 		}
+		workerIssues := out.Issues
+		maps.Copy(changes, out.Changes)
+		for rule, suggestions := range out.Suggestions {
+			if cli.suggestions[rule] == nil {
+				cli.suggestions[rule] = map[string][]byte{}
+			}
+			maps.Copy(cli.suggestions[rule], suggestions)
+		}
+		maps.Copy(cli.sources, out.Sources)
+		if cli.summary != nil && out.Summary != nil {
+			cli.summary.Merge(out.Summary)
+		}
+		if cli.profile != nil && out.Profile != nil {
+			cli.profile.Merge(worker.dir, out.Profile)
+		}
 		fillNoRangeIssueFilenames(worker.dir, workerIssues)
+		cli.formatter.PrintIssuesParallel(worker.dir, workerIssues)
 		issues = append(issues, workerIssues...)
+		for _, pluginErr := range out.PluginErrors {
+			cli.formatter.PrintErrorParallel(worker.dir, fmt.Errorf("%s in %s", pluginErr, worker.dir), cli.sources)
+		}
 
 		if len(stderr) > 0 {
 			// Regardless of format, output to stderr is synchronized.
@@ -91,6 +135,13 @@ func (cli *CLI) inspectParallel(opts Options) int {
 		force = *opts.Force
 	}
 
+	if cli.summary != nil {
+		cli.summary.Elapsed = time.Since(start)
+		cli.formatter.Summary = cli.summary
+	}
+	cli.formatter.Changes = changes
+	cli.formatter.Suggestions = cli.suggestions
+
 	// Parallel inspection ignores the format set in the config file
 	// and the --format CLI flag always takes precedence.
 	if err := cli.formatter.PrintParallel(issues, cli.sources); err != nil {
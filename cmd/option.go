@@ -5,6 +5,7 @@ import (
 	"log"
 	"strings"
 
+	"github.com/terraform-linters/tflint/formatter"
 	"github.com/terraform-linters/tflint/terraform"
 	"github.com/terraform-linters/tflint/tflint"
 )
@@ -13,8 +14,12 @@ import (
 type Options struct {
 	Version                bool     `short:"v" long:"version" description:"Print TFLint version"`
 	Init                   bool     `long:"init" description:"Install plugins"`
+	UpgradeLock            bool     `long:"upgrade-lock" description:"Refresh the plugin lock file, including hashes for all platforms (use with --init)"`
+	Mirror                 string   `long:"mirror" description:"Download plugins into a mirror directory instead of installing them (use with --init)" value-name:"DIR"`
+	Platforms              []string `long:"platform" description:"Target platforms of the mirror (default: current platform)" value-name:"GOOS_GOARCH"`
 	Langserver             bool     `long:"langserver" description:"Start language server"`
-	Format                 string   `short:"f" long:"format" description:"Output format" choice:"default" choice:"json" choice:"checkstyle" choice:"junit" choice:"compact" choice:"sarif"`
+	Format                 string   `short:"f" long:"format" description:"Output format" choice:"default" choice:"json" choice:"checkstyle" choice:"junit" choice:"compact" choice:"sarif" choice:"ndjson" choice:"markdown" choice:"html" choice:"rdjson" choice:"rdjsonl"`
+	OutputFile             string   `long:"output-file" description:"Write the report to a file instead of stdout" value-name:"PATH"`
 	Config                 string   `short:"c" long:"config" description:"Config file name (default: .tflint.hcl)" value-name:"FILE"`
 	IgnoreModules          []string `long:"ignore-module" description:"Ignore module sources" value-name:"SOURCE"`
 	EnableRules            []string `long:"enable-rule" description:"Enable rules from the command line" value-name:"RULE_NAME"`
@@ -27,15 +32,19 @@ type Options struct {
 	Chdir                  string   `long:"chdir" description:"Switch to a different working directory before executing the command" value-name:"DIR"`
 	Recursive              bool     `long:"recursive" description:"Run command in each directory recursively"`
 	Filter                 []string `long:"filter" description:"Filter issues by file names or globs" value-name:"FILE"`
-	Force                  *bool    `long:"force" description:"Return zero exit status even if issues found"`
+	Force                  *bool    `long:"force" description:"Return zero exit status even if issues found, or remove plugins with plugins prune"`
 	MinimumFailureSeverity string   `long:"minimum-failure-severity" description:"Sets minimum severity level for exiting with a non-zero error code" choice:"error" choice:"warning" choice:"notice"`
 	Color                  bool     `long:"color" description:"Enable colorized output"`
 	NoColor                bool     `long:"no-color" description:"Disable colorized output"`
 	Fix                    bool     `long:"fix" description:"Fix issues automatically"`
-	NoParallelRunners      bool     `long:"no-parallel-runners" description:"Disable per-runner parallelism"`
-	MaxWorkers             *int     `long:"max-workers" description:"Set maximum number of workers in recursive inspection (default: number of CPUs)" value-name:"N"`
+	NoParallelRunners      bool     `long:"no-parallel-runners" description:"Disable parallelism across runners and plugins"`
+	FailOnPluginError      bool     `long:"fail-on-plugin-error" description:"Abort the inspection if a plugin fails, instead of reporting the error with issues from other plugins"`
+	MaxWorkers             *int     `long:"max-workers" description:"Set maximum number of workers in recursive inspection and plugin installation (default: number of CPUs)" value-name:"N"`
+	Summary                bool     `long:"summary" description:"Print summary statistics of the inspection"`
+	Profile                string   `long:"profile" description:"Write a timing profile of the inspection to the file in Chrome trace event format" value-name:"FILE"`
 	ActAsBundledPlugin     bool     `long:"act-as-bundled-plugin" hidden:"true"`
 	ActAsWorker            bool     `long:"act-as-worker" hidden:"true"`
+	SuggestChanges         bool     `long:"suggest-changes" hidden:"true"`
 }
 
 func (opts *Options) toConfig() *tflint.Config {
@@ -152,7 +161,7 @@ func (opts *Options) toWorkerCommands(workingDir string) []string {
 
 	// opts.Version, opts.Init, and opts.Langserver are not supported
 
-	// opt.Format is ignored because workers always output serialized issues
+	// opt.Format and opts.OutputFile are ignored because workers always output serialized issues
 
 	if opts.Config != "" {
 		commands = append(commands, fmt.Sprintf("--config=%s", opts.Config))
@@ -197,13 +206,28 @@ func (opts *Options) toWorkerCommands(workingDir string) []string {
 	if opts.Fix {
 		commands = append(commands, "--fix")
 	}
+	// Workers collect suggestions by autofixes if the coordinator's format suggests changes
+	if !opts.Fix && formatter.SuggestsChanges(opts.Format) {
+		commands = append(commands, "--suggest-changes")
+	}
 	if opts.NoParallelRunners {
 		commands = append(commands, "--no-parallel-runners")
 	}
+	if opts.FailOnPluginError {
+		commands = append(commands, "--fail-on-plugin-error")
+	}
 
 	// opts.MaxWorkers is ignored because the coordinator is responsible for parallelism
 
-	// opts.ActAsBundledPlugin and opts.ActAsWorker are not supported
+	if opts.Summary {
+		commands = append(commands, "--summary")
+	}
+	// Workers output the profile instead of writing it to the file, and the coordinator merges them
+	if opts.Profile != "" {
+		commands = append(commands, fmt.Sprintf("--profile=%s", opts.Profile))
+	}
+
+	// opts.ActAsBundledPlugin, opts.ActAsWorker, and opts.SuggestChanges are not supported
 
 	return commands
 }
@@ -273,6 +273,18 @@ func Test_toWorkerCommands(t *testing.T) {
 			workingDir: "subdir",
 			want:       []string{"--act-as-worker", "--chdir=subdir", "--force"},
 		},
+		{
+			name:       "format suggesting changes",
+			in:         []string{"--format=rdjson"},
+			workingDir: "subdir",
+			want:       []string{"--act-as-worker", "--chdir=subdir", "--force", "--suggest-changes"},
+		},
+		{
+			name:       "format suggesting changes with fix",
+			in:         []string{"--format=rdjsonl", "--fix"},
+			workingDir: "subdir",
+			want:       []string{"--act-as-worker", "--chdir=subdir", "--force", "--fix"},
+		},
 		{
 			name: "all",
 			in: []string{
@@ -280,6 +292,7 @@ func Test_toWorkerCommands(t *testing.T) {
 				"--init",
 				"--langserver",
 				"--format=json",
+				"--output-file=result.json",
 				"--config=tflint.hcl",
 				"--ignore-module=module1",
 				"--ignore-module=module2",
@@ -306,9 +319,13 @@ func Test_toWorkerCommands(t *testing.T) {
 				"--no-color",
 				"--fix",
 				"--no-parallel-runners",
+				"--fail-on-plugin-error",
 				"--max-workers=2",
+				"--summary",
+				"--profile=profile.json",
 				"--act-as-bundled-plugin",
 				"--act-as-worker",
+				"--suggest-changes",
 			},
 			workingDir: "subdir",
 			want: []string{
@@ -316,6 +333,7 @@ func Test_toWorkerCommands(t *testing.T) {
 				// "--init",
 				// "--langserver",
 				// "--format=json",
+				// "--output-file=result.json",
 				"--config=tflint.hcl",
 				"--ignore-module=module1",
 				"--ignore-module=module2",
@@ -342,9 +360,13 @@ func Test_toWorkerCommands(t *testing.T) {
 				// "--no-color",
 				"--fix",
 				"--no-parallel-runners",
+				"--fail-on-plugin-error",
 				// "--max-workers=2",
+				"--summary",
+				"--profile=profile.json",
 				// "--act-as-bundled-plugin",
 				"--act-as-worker",
+				// "--suggest-changes",
 			},
 		},
 	}
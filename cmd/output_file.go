@@ -0,0 +1,73 @@
+package cmd
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"os"
+	"path/filepath"
+
+	"github.com/terraform-linters/tflint/tflint"
+)
+
+// withOutputFile runs the given function while redirecting the report to the file at the given path.
+// The report is written to a temporary file in the same directory and renamed when done,
+// so the file never contains a half-written report. Other messages are still output to stderr.
+func (cli *CLI) withOutputFile(path string, proc func() int) int {
+	// Resolve the path before the working directory is changed by --chdir
+	path, err := filepath.Abs(path)
+	if err != nil {
+		cli.formatter.Print(tflint.Issues{}, fmt.Errorf("Failed to resolve the output file path; %w", err), map[string][]byte{})
+		return ExitCodeError
+	}
+
+	tmp, err := createTempFile(path)
+	if err != nil {
+		cli.formatter.Print(tflint.Issues{}, fmt.Errorf("Failed to create the output file; %w", err), map[string][]byte{})
+		return ExitCodeError
+	}
+	defer os.Remove(tmp.Name()) // no-op if renamed
+	// Deferred functions are not called when exiting on signals, so remove the temporary file in the shutdown path
+	cli.onShutdown(func() { os.Remove(tmp.Name()) })
+
+	cli.formatter.Stdout = tmp
+	status := proc()
+	cli.formatter.Stdout = cli.outStream
+
+	// The report of an interrupted run is partial, so the existing file is kept in both modes.
+	// In recursive mode, the signal only cancels workers and the function returns normally.
+	if cli.interrupted.Load() {
+		tmp.Close()
+		return status
+	}
+
+	// Keep the mode of the existing file. Otherwise, the mode is the default for new files with the umask applied
+	if info, statErr := os.Stat(path); statErr == nil {
+		err = tmp.Chmod(info.Mode().Perm())
+	}
+	if err1 := tmp.Close(); err == nil {
+		err = err1
+	}
+	if err == nil {
+		err = os.Rename(tmp.Name(), path)
+	}
+	if err != nil {
+		cli.formatter.Print(tflint.Issues{}, fmt.Errorf("Failed to write the output file; %w", err), map[string][]byte{})
+		return ExitCodeError
+	}
+
+	return status
+}
+
+// createTempFile creates a new temporary file in the same directory as the given path.
+// Unlike os.CreateTemp, the file is created with 0666 before the umask, the same as os.Create.
+func createTempFile(path string) (*os.File, error) {
+	for range 10000 {
+		name := filepath.Join(filepath.Dir(path), fmt.Sprintf(".%s.%d.tmp", filepath.Base(path), rand.Uint32()))
+		f, err := os.OpenFile(name, os.O_RDWR|os.O_CREATE|os.O_EXCL, 0o666)
+		if os.IsExist(err) {
+			continue
+		}
+		return f, err
+	}
+	return nil, fmt.Errorf("failed to create a temporary file for %s", path)
+}
@@ -0,0 +1,184 @@
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/terraform-linters/tflint/formatter"
+)
+
+func Test_withOutputFile(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "report.txt")
+
+	stdout, stderr := new(bytes.Buffer), new(bytes.Buffer)
+	cli := &CLI{outStream: stdout, errStream: stderr}
+	cli.formatter = &formatter.Formatter{Stdout: stdout, Stderr: stderr}
+
+	status := cli.withOutputFile(path, func() int {
+		fmt.Fprint(cli.formatter.Stdout, "report")
+		fmt.Fprint(cli.formatter.Stderr, "progress")
+
+		// The report must not be visible until the process is complete
+		if _, err := os.Stat(path); !os.IsNotExist(err) {
+			t.Errorf("the output file exists before completion: %v", err)
+		}
+		return ExitCodeIssuesFound
+	})
+
+	if status != ExitCodeIssuesFound {
+		t.Errorf("unexpected exit status: %d", status)
+	}
+	report, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(report) != "report" {
+		t.Errorf("unexpected report: %s", report)
+	}
+	if stdout.String() != "" {
+		t.Errorf("unexpected stdout: %s", stdout.String())
+	}
+	if stderr.String() != "progress" {
+		t.Errorf("unexpected stderr: %s", stderr.String())
+	}
+	if cli.formatter.Stdout != stdout {
+		t.Error("the formatter output is not restored")
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 {
+		t.Errorf("temporary files are left: %v", entries)
+	}
+}
+
+func Test_withOutputFile_notExistDir(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "not_exist", "report.txt")
+
+	stdout, stderr := new(bytes.Buffer), new(bytes.Buffer)
+	cli := &CLI{outStream: stdout, errStream: stderr}
+	cli.formatter = &formatter.Formatter{Stdout: stdout, Stderr: stderr}
+
+	status := cli.withOutputFile(path, func() int {
+		t.Error("the function should not be called")
+		return ExitCodeOK
+	})
+
+	if status != ExitCodeError {
+		t.Errorf("unexpected exit status: %d", status)
+	}
+	if !strings.Contains(stderr.String(), "Failed to create the output file") {
+		t.Errorf("unexpected stderr: %s", stderr.String())
+	}
+}
+
+func Test_withOutputFile_mode(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("file modes are not supported on Windows")
+	}
+	dir := t.TempDir()
+
+	// The mode of a new file is the default with the umask applied, the same as os.Create
+	created, err := os.Create(filepath.Join(dir, "created.txt"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	created.Close()
+	createdInfo, err := os.Stat(created.Name())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cli := &CLI{outStream: new(bytes.Buffer), errStream: new(bytes.Buffer)}
+	cli.formatter = &formatter.Formatter{Stdout: cli.outStream, Stderr: cli.errStream}
+
+	newPath := filepath.Join(dir, "new.txt")
+	cli.withOutputFile(newPath, func() int { return ExitCodeOK })
+	info, err := os.Stat(newPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Mode().Perm() != createdInfo.Mode().Perm() {
+		t.Errorf("expected the mode %s, got %s", createdInfo.Mode().Perm(), info.Mode().Perm())
+	}
+
+	// The mode of the existing file is kept
+	existingPath := filepath.Join(dir, "existing.txt")
+	if err := os.WriteFile(existingPath, []byte("old"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	cli.withOutputFile(existingPath, func() int { return ExitCodeOK })
+	info, err = os.Stat(existingPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Mode().Perm() != 0o600 {
+		t.Errorf("expected the mode 0600, got %s", info.Mode().Perm())
+	}
+}
+
+func Test_withOutputFile_shutdown(t *testing.T) {
+	dir := t.TempDir()
+
+	cli := &CLI{outStream: new(bytes.Buffer), errStream: new(bytes.Buffer)}
+	cli.formatter = &formatter.Formatter{Stdout: cli.outStream, Stderr: cli.errStream}
+
+	cli.withOutputFile(filepath.Join(dir, "report.txt"), func() int {
+		// Shutdown callbacks run the hooks before calling os.Exit
+		cli.runShutdownHooks()
+
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(entries) != 0 {
+			t.Errorf("temporary files are left: %v", entries)
+		}
+		return ExitCodeError
+	})
+}
+
+func Test_withOutputFile_interrupted(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "report.txt")
+	if err := os.WriteFile(path, []byte("old"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	cli := &CLI{outStream: new(bytes.Buffer), errStream: new(bytes.Buffer)}
+	cli.formatter = &formatter.Formatter{Stdout: cli.outStream, Stderr: cli.errStream}
+
+	status := cli.withOutputFile(path, func() int {
+		fmt.Fprint(cli.formatter.Stdout, "partial")
+		// In recursive mode, the function returns normally after workers are canceled by a signal
+		cli.interrupted.Store(true)
+		return ExitCodeError
+	})
+
+	if status != ExitCodeError {
+		t.Errorf("unexpected exit status: %d", status)
+	}
+	report, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(report) != "old" {
+		t.Errorf("the existing file is replaced: %s", report)
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 {
+		t.Errorf("temporary files are left: %v", entries)
+	}
+}
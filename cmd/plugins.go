@@ -0,0 +1,379 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/hashicorp/go-version"
+	hcl "github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclwrite"
+	"github.com/spf13/afero"
+	"github.com/terraform-linters/tflint/plugin"
+	"github.com/terraform-linters/tflint/tflint"
+	"github.com/zclconf/go-cty/cty"
+)
+
+// PluginsListOutput is the JSON output structure for "tflint plugins list"
+type PluginsListOutput struct {
+	PluginDir string                  `json:"plugin_dir"`
+	Plugins   []InstalledPluginOutput `json:"plugins"`
+}
+
+// InstalledPluginOutput represents a plugin installed in the plugin directory
+type InstalledPluginOutput struct {
+	Name         string   `json:"name"`
+	Source       string   `json:"source,omitempty"`
+	Version      string   `json:"version,omitempty"`
+	SDKVersion   string   `json:"sdk_version,omitempty"`
+	Path         string   `json:"path"`
+	ReferencedBy []string `json:"referenced_by"`
+}
+
+// pluginReferences are plugins referenced by the configs in the working directories.
+type pluginReferences struct {
+	// dirs are the plugin directories used by the configs in order of appearance
+	dirs []string
+	// configs are the config files referencing the plugin, keyed by the path of the plugin binary
+	configs map[string][]string
+}
+
+// plugins runs the "plugins" subcommand to manage plugins installed in the plugin directory.
+func (cli *CLI) plugins(opts Options, args []string) int {
+	if len(args) != 1 {
+		cli.formatter.Print(tflint.Issues{}, fmt.Errorf("Usage: tflint plugins (list|upgrade|prune) [OPTIONS]"), map[string][]byte{})
+		return ExitCodeError
+	}
+
+	switch args[0] {
+	case "list":
+		return cli.listPlugins(opts)
+	case "upgrade":
+		return cli.upgradePlugins(opts)
+	case "prune":
+		return cli.prunePlugins(opts)
+	default:
+		cli.formatter.Print(tflint.Issues{}, fmt.Errorf(`"%s" is not a plugins subcommand. Available subcommands: list, upgrade, prune`, args[0]), map[string][]byte{})
+		return ExitCodeError
+	}
+}
+
+// listPlugins prints plugins installed in the plugin directories with the configs referencing them.
+func (cli *CLI) listPlugins(opts Options) int {
+	refs, err := cli.findPluginReferences(opts)
+	if err != nil {
+		cli.formatter.Print(tflint.Issues{}, err, map[string][]byte{})
+		return ExitCodeError
+	}
+
+	outputs := []PluginsListOutput{}
+	for _, dir := range refs.dirs {
+		installed, err := plugin.FindInstalledPlugins(dir)
+		if err != nil {
+			cli.formatter.Print(tflint.Issues{}, err, map[string][]byte{})
+			return ExitCodeError
+		}
+
+		output := PluginsListOutput{PluginDir: dir, Plugins: []InstalledPluginOutput{}}
+		for _, p := range installed {
+			out := InstalledPluginOutput{
+				Name:         p.Name,
+				Source:       p.SourceDir,
+				Version:      p.Version,
+				Path:         p.Path,
+				ReferencedBy: refs.configs[p.Path],
+			}
+			if out.ReferencedBy == nil {
+				out.ReferencedBy = []string{}
+			}
+			sdkVersion, err := p.SDKVersion()
+			if err != nil {
+				log.Printf("[ERROR] Failed to get SDK version of %s: %s", p.Path, err)
+			} else if sdkVersion != nil {
+				out.SDKVersion = sdkVersion.String()
+			}
+			output.Plugins = append(output.Plugins, out)
+		}
+		outputs = append(outputs, output)
+	}
+
+	if opts.Format == "json" {
+		out, err := json.MarshalIndent(outputs, "", "  ")
+		if err != nil {
+			log.Printf("[ERROR] Failed to marshal JSON: %s", err)
+			return ExitCodeError
+		}
+		fmt.Fprintln(cli.outStream, string(out))
+		return ExitCodeOK
+	}
+
+	for i, output := range outputs {
+		if i > 0 {
+			fmt.Fprint(cli.outStream, "\n")
+		}
+		fmt.Fprintf(cli.outStream, "Plugin directory: %s\n", output.PluginDir)
+		if len(output.Plugins) == 0 {
+			fmt.Fprint(cli.outStream, "No plugins installed\n")
+			continue
+		}
+
+		w := tabwriter.NewWriter(cli.outStream, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "NAME\tSOURCE\tVERSION\tSDK VERSION\tREFERENCED BY")
+		for _, p := range output.Plugins {
+			fmt.Fprintf(
+				w, "%s\t%s\t%s\t%s\t%s\n",
+				p.Name, valueOr(p.Source, "(manual)"), valueOr(p.Version, "-"), valueOr(p.SDKVersion, "unknown"), valueOr(strings.Join(p.ReferencedBy, ", "), "-"),
+			)
+		}
+		if err := w.Flush(); err != nil {
+			log.Printf("[ERROR] Failed to write output: %s", err)
+			return ExitCodeError
+		}
+	}
+
+	return ExitCodeOK
+}
+
+// upgradePlugins updates the versions of plugins in the config files to the latest releases.
+// Plugins with version constraints are not changed. Run "tflint --init --upgrade-lock" for them instead.
+func (cli *CLI) upgradePlugins(opts Options) int {
+	workingDirs, err := findWorkingDirs(opts)
+	if err != nil {
+		cli.formatter.Print(tflint.Issues{}, fmt.Errorf("Failed to find workspaces; %w", err), map[string][]byte{})
+		return ExitCodeError
+	}
+
+	upgraded := false
+	// Config files shared by working directories, like ~/.tflint.hcl, are upgraded only once
+	visited := map[string]bool{}
+	for _, wd := range workingDirs {
+		err := cli.withinChangedDir(wd, func() error {
+			cfg, err := tflint.LoadConfig(afero.Afero{Fs: afero.NewOsFs()}, opts.Config)
+			if err != nil {
+				return fmt.Errorf("Failed to load TFLint config in %s; %w", wd, err)
+			}
+			if cfg.Path() == "" {
+				return nil
+			}
+			path, err := filepath.Abs(cfg.Path())
+			if err != nil {
+				return err
+			}
+			if visited[path] {
+				return nil
+			}
+			visited[path] = true
+
+			versions := map[string]string{}
+			for _, name := range slices.Sorted(maps.Keys(cfg.Plugins)) {
+				installCfg := plugin.NewInstallConfig(cfg, cfg.Plugins[name])
+				if installCfg.ManuallyInstalled() {
+					continue
+				}
+				if installCfg.HasVersionConstraints() {
+					fmt.Fprintf(cli.outStream, "Plugin \"%s\" has the version constraint \"%s\". Run \"tflint --init --upgrade-lock\" to upgrade it within the constraint\n", name, installCfg.Version)
+					continue
+				}
+
+				latest, err := installCfg.LatestVersion()
+				if err != nil {
+					return fmt.Errorf("Failed to find the latest version of plugin \"%s\"; %w", name, err)
+				}
+				current, err := version.NewVersion(installCfg.Version)
+				if err != nil {
+					return err
+				}
+				if !version.Must(version.NewVersion(latest)).GreaterThan(current) {
+					continue
+				}
+
+				versions[name] = latest
+			}
+			if len(versions) == 0 {
+				return nil
+			}
+
+			if cfg.IsJSONConfig() {
+				return fmt.Errorf("Failed to upgrade plugins in %s; JSON config files must be updated manually", configLabel(wd, cfg))
+			}
+			if err := writePluginVersions(cfg.Path(), versions); err != nil {
+				return fmt.Errorf("Failed to upgrade plugins in %s; %w", configLabel(wd, cfg), err)
+			}
+			for _, name := range slices.Sorted(maps.Keys(versions)) {
+				fmt.Fprintf(cli.outStream, "Upgraded \"%s\" from %s to %s in %s\n", name, cfg.Plugins[name].Version, versions[name], configLabel(wd, cfg))
+			}
+			upgraded = true
+			return nil
+		})
+		if err != nil {
+			cli.formatter.Print(tflint.Issues{}, err, map[string][]byte{})
+			return ExitCodeError
+		}
+	}
+
+	if upgraded {
+		fmt.Fprint(cli.outStream, "Run \"tflint --init\" to install the upgraded plugins\n")
+	} else {
+		fmt.Fprint(cli.outStream, "All plugins are up to date\n")
+	}
+	return ExitCodeOK
+}
+
+// prunePlugins removes installed plugin versions that are not referenced by the configs in the working directories.
+// Manually installed plugins are never removed.
+//
+// Since the plugin directory is often shared by other projects whose configs are not loaded here,
+// it only prints the version directories to be removed unless --force is given.
+func (cli *CLI) prunePlugins(opts Options) int {
+	refs, err := cli.findPluginReferences(opts)
+	if err != nil {
+		cli.formatter.Print(tflint.Issues{}, err, map[string][]byte{})
+		return ExitCodeError
+	}
+	force := opts.Force != nil && *opts.Force
+
+	pruned := false
+	for _, dir := range refs.dirs {
+		installed, err := plugin.FindInstalledPlugins(dir)
+		if err != nil {
+			cli.formatter.Print(tflint.Issues{}, err, map[string][]byte{})
+			return ExitCodeError
+		}
+
+		for _, p := range installed {
+			if p.ManuallyInstalled() || len(refs.configs[p.Path]) > 0 {
+				continue
+			}
+			pruned = true
+			if !force {
+				fmt.Fprintf(cli.outStream, "Would remove \"%s\" (source: %s, version: %s) in %s\n", p.Name, p.SourceDir, p.Version, filepath.Dir(p.Path))
+				continue
+			}
+			if err := p.Uninstall(dir); err != nil {
+				cli.formatter.Print(tflint.Issues{}, fmt.Errorf("Failed to remove %s; %w", p.Path, err), map[string][]byte{})
+				return ExitCodeError
+			}
+			fmt.Fprintf(cli.outStream, "Removed \"%s\" (source: %s, version: %s) in %s\n", p.Name, p.SourceDir, p.Version, filepath.Dir(p.Path))
+		}
+	}
+
+	if !pruned {
+		fmt.Fprint(cli.outStream, "No unused plugins found\n")
+	} else if !force {
+		fmt.Fprint(cli.outStream, "Plugins used only by config files outside the working directories are also listed. Run \"tflint plugins prune --force\" to remove them\n")
+	}
+	return ExitCodeOK
+}
+
+// findPluginReferences loads the configs in the working directories, and returns the plugin directories
+// and the installed plugins referenced by the configs. Version constraints are resolved with the lock file.
+func (cli *CLI) findPluginReferences(opts Options) (*pluginReferences, error) {
+	workingDirs, err := findWorkingDirs(opts)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to find workspaces; %w", err)
+	}
+
+	refs := &pluginReferences{dirs: []string{}, configs: map[string][]string{}}
+	for _, wd := range workingDirs {
+		err := cli.withinChangedDir(wd, func() error {
+			cfg, err := tflint.LoadConfig(afero.Afero{Fs: afero.NewOsFs()}, opts.Config)
+			if err != nil {
+				return fmt.Errorf("Failed to load TFLint config in %s; %w", wd, err)
+			}
+			cfg.Merge(opts.toConfig())
+
+			dir, err := plugin.PluginDir(cfg)
+			if err != nil {
+				return fmt.Errorf("Failed to get plugin dir; %w", err)
+			}
+			if dir, err = filepath.Abs(dir); err != nil {
+				return err
+			}
+			if !slices.Contains(refs.dirs, dir) {
+				refs.dirs = append(refs.dirs, dir)
+			}
+
+			lock, err := plugin.LoadLockFile(plugin.LockFilePath(cfg))
+			if err != nil {
+				return fmt.Errorf("Failed to load the plugin lock file; %w", err)
+			}
+
+			label := configLabel(wd, cfg)
+			for _, pluginCfg := range cfg.Plugins {
+				installCfg := lock.Resolve(plugin.NewInstallConfig(cfg, pluginCfg))
+				// Version constraints that are not resolved yet do not reference any installed plugins
+				if installCfg == nil {
+					continue
+				}
+				path, err := plugin.FindPluginPath(installCfg)
+				if err != nil {
+					continue
+				}
+				if path, err = filepath.Abs(path); err != nil {
+					return err
+				}
+				if !slices.Contains(refs.configs[path], label) {
+					refs.configs[path] = append(refs.configs[path], label)
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return refs, nil
+}
+
+// writePluginVersions rewrites the version attributes of the plugin blocks in the config file.
+// Other parts of the file, including comments and formatting, are preserved.
+func writePluginVersions(path string, versions map[string]string) error {
+	src, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	file, diags := hclwrite.ParseConfig(src, path, hcl.InitialPos)
+	if diags.HasErrors() {
+		return diags
+	}
+
+	for _, block := range file.Body().Blocks() {
+		if block.Type() != "plugin" || len(block.Labels()) != 1 {
+			continue
+		}
+		if v, exists := versions[block.Labels()[0]]; exists {
+			block.Body().SetAttributeValue("version", cty.StringVal(v))
+		}
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, file.Bytes(), info.Mode())
+}
+
+// configLabel returns the config file path relative to the original working directory for display.
+func configLabel(wd string, cfg *tflint.Config) string {
+	if cfg.Path() == "" {
+		return fmt.Sprintf("%s (default config)", wd)
+	}
+	if filepath.IsAbs(cfg.Path()) {
+		return cfg.Path()
+	}
+	return filepath.Join(wd, cfg.Path())
+}
+
+func valueOr(value string, fallback string) string {
+	if value == "" {
+		return fallback
+	}
+	return value
+}
@@ -0,0 +1,229 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/terraform-linters/tflint/plugin"
+	"github.com/terraform-linters/tflint/tflint"
+)
+
+// setupPluginsTest creates a config referencing the "foo" plugin in a file source with v0.1.0 and v0.2.0,
+// and a plugin directory in which v0.0.9 and v0.1.0 are installed, then changes the working directory.
+// It returns the CLI, its stdout, and the plugin directory.
+func setupPluginsTest(t *testing.T) (*CLI, *bytes.Buffer, string) {
+	t.Helper()
+
+	dir := t.TempDir()
+	source := filepath.Join(dir, "source")
+	for _, tag := range []string{"v0.1.0", "v0.2.0"} {
+		if err := os.MkdirAll(filepath.Join(source, tag), 0o755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	sourceURL := "file://" + filepath.ToSlash(source)
+	if runtime.GOOS == "windows" {
+		sourceURL = "file:///" + filepath.ToSlash(source)
+	}
+
+	pluginDir := filepath.Join(dir, "plugins")
+	for _, v := range []string{"0.0.9", "0.1.0"} {
+		installCfg := plugin.NewInstallConfig(tflint.EmptyConfig(), &tflint.PluginConfig{Name: "foo", Version: v, Source: sourceURL, SourceType: tflint.SourceTypeFile})
+		path := filepath.Join(pluginDir, installCfg.InstallPath()+fileExt())
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte("binary"), 0o755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// Manually installed plugins are never pruned
+	if err := os.WriteFile(filepath.Join(pluginDir, "tflint-ruleset-bar"+fileExt()), []byte("binary"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("TFLINT_PLUGIN_DIR", pluginDir)
+
+	config := `# comment
+plugin "foo" {
+  enabled = true
+  version = "0.1.0"
+  source  = "` + sourceURL + `"
+}
+`
+	workDir := filepath.Join(dir, "work")
+	if err := os.Mkdir(workDir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(workDir, ".tflint.hcl"), []byte(config), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	t.Chdir(workDir)
+
+	stdout := new(bytes.Buffer)
+	cli, err := NewCLI(stdout, new(bytes.Buffer))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return cli, stdout, pluginDir
+}
+
+func Test_plugins_prune(t *testing.T) {
+	cli, stdout, pluginDir := setupPluginsTest(t)
+
+	installedPlugins := func() []string {
+		installed, err := plugin.FindInstalledPlugins(pluginDir)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got := []string{}
+		for _, p := range installed {
+			got = append(got, fmt.Sprintf("%s@%s", p.Name, p.Version))
+		}
+		return got
+	}
+	installed, err := plugin.FindInstalledPlugins(pluginDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// foo@0.0.9 is the first plugin sorted by path
+	unusedDir := filepath.Dir(installed[0].Path)
+
+	// Without --force, plugins to be removed are only listed
+	if status := cli.Run([]string{"tflint", "plugins", "prune"}); status != ExitCodeOK {
+		t.Fatalf("unexpected exit status: %d, stdout: %s", status, stdout)
+	}
+	if diff := cmp.Diff([]string{"foo@0.0.9", "foo@0.1.0", "bar@"}, installedPlugins()); diff != "" {
+		t.Errorf("unexpected installed plugins: %s", diff)
+	}
+	if !strings.Contains(stdout.String(), `Would remove "foo"`) || !strings.Contains(stdout.String(), unusedDir) {
+		t.Errorf("unexpected output: %s", stdout)
+	}
+
+	stdout.Reset()
+	if status := cli.Run([]string{"tflint", "plugins", "prune", "--force"}); status != ExitCodeOK {
+		t.Fatalf("unexpected exit status: %d, stdout: %s", status, stdout)
+	}
+	if diff := cmp.Diff([]string{"foo@0.1.0", "bar@"}, installedPlugins()); diff != "" {
+		t.Errorf("unexpected installed plugins: %s", diff)
+	}
+	if !strings.Contains(stdout.String(), `Removed "foo"`) || !strings.Contains(stdout.String(), unusedDir) {
+		t.Errorf("unexpected output: %s", stdout)
+	}
+}
+
+func Test_plugins_upgrade(t *testing.T) {
+	cli, stdout, _ := setupPluginsTest(t)
+
+	if status := cli.Run([]string{"tflint", "plugins", "upgrade"}); status != ExitCodeOK {
+		t.Fatalf("unexpected exit status: %d, stdout: %s", status, stdout)
+	}
+	if !strings.Contains(stdout.String(), `Upgraded "foo" from 0.1.0 to 0.2.0 in .tflint.hcl`) {
+		t.Errorf("unexpected output: %s", stdout)
+	}
+
+	src, err := os.ReadFile(".tflint.hcl")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(string(src), "# comment\n") || !strings.Contains(string(src), `version = "0.2.0"`) {
+		t.Errorf("unexpected config: %s", src)
+	}
+
+	stdout.Reset()
+	if status := cli.Run([]string{"tflint", "plugins", "upgrade"}); status != ExitCodeOK {
+		t.Fatalf("unexpected exit status: %d, stdout: %s", status, stdout)
+	}
+	if stdout.String() != "All plugins are up to date\n" {
+		t.Errorf("unexpected output: %s", stdout)
+	}
+}
+
+func Test_plugins_invalidSubcommand(t *testing.T) {
+	stdout, stderr := new(bytes.Buffer), new(bytes.Buffer)
+	cli, err := NewCLI(stdout, stderr)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if status := cli.Run([]string{"tflint", "plugins", "remove"}); status != ExitCodeError {
+		t.Fatalf("unexpected exit status: %d", status)
+	}
+	if !strings.Contains(stderr.String(), `"remove" is not a plugins subcommand. Available subcommands: list, upgrade, prune`) {
+		t.Errorf("unexpected stderr: %s", stderr)
+	}
+}
+
+func Test_writePluginVersions(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ".tflint.hcl")
+	src := `plugin "aws" {
+  enabled = true
+  version = "0.37.0" # pinned
+  source  = "github.com/terraform-linters/tflint-ruleset-aws"
+}
+
+plugin "google" {
+  enabled = true
+  version = "0.30.0"
+  source  = "github.com/terraform-linters/tflint-ruleset-google"
+}
+`
+	if err := os.WriteFile(path, []byte(src), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := writePluginVersions(path, map[string]string{"aws": "0.38.2"}); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := strings.Replace(src, `version = "0.37.0" # pinned`, `version = "0.38.2" # pinned`, 1)
+	if diff := cmp.Diff(expected, string(got)); diff != "" {
+		t.Fatal(diff)
+	}
+}
+
+func fileExt() string {
+	if runtime.GOOS == "windows" {
+		return ".exe"
+	}
+	return ""
+}
+
+func Test_plugins_list(t *testing.T) {
+	cli, stdout, pluginDir := setupPluginsTest(t)
+
+	if status := cli.Run([]string{"tflint", "plugins", "list", "--format", "json"}); status != ExitCodeOK {
+		t.Fatalf("unexpected exit status: %d, stdout: %s", status, stdout)
+	}
+
+	var got []PluginsListOutput
+	if err := json.Unmarshal(stdout.Bytes(), &got); err != nil {
+		t.Fatalf("Failed to parse output: %s\n%s", err, stdout)
+	}
+	if len(got) != 1 || got[0].PluginDir != pluginDir {
+		t.Fatalf("unexpected output: %s", stdout)
+	}
+
+	referenced := map[string][]string{}
+	for _, p := range got[0].Plugins {
+		referenced[fmt.Sprintf("%s@%s", p.Name, p.Version)] = p.ReferencedBy
+	}
+	expected := map[string][]string{
+		"foo@0.0.9": {},
+		"foo@0.1.0": {".tflint.hcl"},
+		"bar@":      {},
+	}
+	if diff := cmp.Diff(expected, referenced); diff != "" {
+		t.Errorf("unexpected references: %s", diff)
+	}
+}
@@ -20,6 +20,18 @@ type Formatter struct {
 	Fix     bool
 	NoColor bool
 
+	// Changes is the sources rewritten by autofixes.
+	// It is set only when --fix is given.
+	Changes map[string][]byte
+
+	// Suggestions is the sources fixed by each plugin separately, keyed by rule names and file paths.
+	// It is set only when the format suggests changes without --fix.
+	Suggestions map[string]map[string][]byte
+
+	// Summary is the statistics of the inspection printed with issues.
+	// It is nil unless --summary is given.
+	Summary *tflint.Summary
+
 	// Errors occurred in parallel workers.
 	// Some formats do not output immediately, so they are saved here.
 	errInParallel error
@@ -38,6 +50,20 @@ type bufferedFormat struct{}
 
 func (bufferedFormat) buffersErrors() bool { return true }
 
+// streamingFormat is implemented by formats that print the results of each
+// parallel worker as soon as the worker finishes, rather than at the end.
+type streamingFormat interface {
+	format
+	printWorker(f *Formatter, dir string, issues tflint.Issues, err error)
+}
+
+// suggestingFormat is implemented by formats that suggest changes by autofixes.
+// Suggestions are collected for these formats without --fix, but are not written to files.
+type suggestingFormat interface {
+	format
+	suggestsChanges() bool
+}
+
 var formats = map[string]format{
 	"default":    prettyFormat{},
 	"json":       jsonFormat{},
@@ -45,6 +71,11 @@ var formats = map[string]format{
 	"junit":      junitFormat{},
 	"compact":    compactFormat{},
 	"sarif":      sarifFormat{},
+	"ndjson":     ndjsonFormat{},
+	"markdown":   markdownFormat{},
+	"html":       htmlFormat{},
+	"rdjson":     rdjsonFormat{},
+	"rdjsonl":    rdjsonlFormat{},
 }
 
 func (f *Formatter) resolveFormat() format {
@@ -54,21 +85,32 @@ func (f *Formatter) resolveFormat() format {
 	return prettyFormat{} // unknown format falls back to pretty, matching today's default
 }
 
+// SuggestsChanges returns whether the given format suggests changes by autofixes.
+func SuggestsChanges(format string) bool {
+	f, ok := formats[format].(suggestingFormat)
+	return ok && f.suggestsChanges()
+}
+
 // Print outputs the given issues and errors according to configured format
 func (f *Formatter) Print(issues tflint.Issues, err error, sources map[string][]byte) {
 	f.resolveFormat().print(f, issues, err, sources)
 }
 
-// PrintErrorParallel outputs an error occurred in parallel workers.
+// PrintErrorParallel outputs an error occurred in a parallel worker in the given directory.
 // Depending on the configured format, errors may not be output immediately.
 // This function itself is called serially, so changes to f.errInParallel are safe.
-func (f *Formatter) PrintErrorParallel(err error, sources map[string][]byte) {
+func (f *Formatter) PrintErrorParallel(dir string, err error, sources map[string][]byte) {
 	if f.errInParallel == nil {
 		f.errInParallel = err
 	} else {
 		f.errInParallel = errors.Join(f.errInParallel, err)
 	}
 
+	if format, ok := f.resolveFormat().(streamingFormat); ok {
+		format.printWorker(f, dir, tflint.Issues{}, err)
+		return
+	}
+
 	if f.resolveFormat().buffersErrors() {
 		// These formats require errors to be printed at the end, so do nothing here
 		return
@@ -78,22 +120,33 @@ func (f *Formatter) PrintErrorParallel(err error, sources map[string][]byte) {
 	f.prettyPrintErrors(err, sources, true)
 }
 
+// PrintIssuesParallel outputs issues found by a parallel worker in the given directory.
+// Only streaming formats output them immediately. Other formats output
+// all issues at the end with PrintParallel, so do nothing here.
+func (f *Formatter) PrintIssuesParallel(dir string, issues tflint.Issues) {
+	if format, ok := f.resolveFormat().(streamingFormat); ok {
+		format.printWorker(f, dir, issues, nil)
+	}
+}
+
 // PrintParallel outputs issues and errors in parallel workers.
 // Errors stored with PrintErrorParallel are output,
 // but in the default format they are output in real time, so they are ignored.
+// In streaming formats, both issues and errors are already output.
 func (f *Formatter) PrintParallel(issues tflint.Issues, sources map[string][]byte) error {
-	if f.resolveFormat().buffersErrors() {
-		f.Print(issues, f.errInParallel, sources)
+	if _, ok := f.resolveFormat().(streamingFormat); ok {
 		return f.errInParallel
 	}
 
-	if f.errInParallel != nil {
-		// Do not print the errors since they are already printed in real time
+	if f.resolveFormat().buffersErrors() {
+		f.Print(issues, f.errInParallel, sources)
 		return f.errInParallel
 	}
 
+	// Do not print the errors since they are already printed in real time.
+	// Issues found by other workers or plugins are still printed.
 	f.Print(issues, nil, sources)
-	return nil
+	return f.errInParallel
 }
 
 func toSeverity(lintType tflint.Severity) string {
@@ -73,6 +73,36 @@ func TestResolveFormat(t *testing.T) {
 			resolved:      sarifFormat{},
 			buffersErrors: true,
 		},
+		{
+			name:          "ndjson",
+			format:        "ndjson",
+			resolved:      ndjsonFormat{},
+			buffersErrors: true,
+		},
+		{
+			name:          "markdown",
+			format:        "markdown",
+			resolved:      markdownFormat{},
+			buffersErrors: true,
+		},
+		{
+			name:          "html",
+			format:        "html",
+			resolved:      htmlFormat{},
+			buffersErrors: true,
+		},
+		{
+			name:          "rdjson",
+			format:        "rdjson",
+			resolved:      rdjsonFormat{},
+			buffersErrors: true,
+		},
+		{
+			name:          "rdjsonl",
+			format:        "rdjsonl",
+			resolved:      rdjsonlFormat{},
+			buffersErrors: true,
+		},
 		{
 			name:          "unknown format falls back to pretty",
 			format:        "unknown",
@@ -121,6 +151,12 @@ failed
 			err:    errors.New("an error occurred\n\nfailed"),
 			stderr: "", // no errors
 		},
+		{
+			name:   "NDJSON",
+			format: "ndjson",
+			err:    errors.New("an error occurred\n\nfailed"),
+			stderr: "", // errors are streamed to stdout
+		},
 	}
 
 	for _, test := range tests {
@@ -132,7 +168,7 @@ failed
 				Format: test.format,
 			}
 
-			formatter.PrintErrorParallel(test.err, map[string][]byte{})
+			formatter.PrintErrorParallel(".", test.err, map[string][]byte{})
 
 			if diff := cmp.Diff(test.stderr, stderr.String()); diff != "" {
 				t.Errorf("diff: %s", diff)
@@ -154,11 +190,20 @@ func TestPrintParallel(t *testing.T) {
 			name:   "default with errors",
 			format: "default",
 			before: func(f *Formatter) {
-				f.PrintErrorParallel(errors.New("an error occurred"), map[string][]byte{})
-				f.PrintErrorParallel(errors.New("failed"), map[string][]byte{})
+				f.PrintErrorParallel(".", errors.New("an error occurred"), map[string][]byte{})
+				f.PrintErrorParallel(".", errors.New("failed"), map[string][]byte{})
 			},
-			stdout: "", // no issues
-			stderr: "", // no errors
+			stdout: `1 issue(s) found:
+
+Error: test (test_rule)
+
+  on test.tf line 1:
+   (source code not available)
+
+Reference: https://github.com
+
+`,
+			stderr: "", // already printed
 			error:  true,
 		},
 		{
@@ -180,8 +225,8 @@ Reference: https://github.com
 			name:   "JSON with errors",
 			format: "json",
 			before: func(f *Formatter) {
-				f.PrintErrorParallel(errors.New("an error occurred"), map[string][]byte{})
-				f.PrintErrorParallel(errors.New("failed"), map[string][]byte{})
+				f.PrintErrorParallel(".", errors.New("an error occurred"), map[string][]byte{})
+				f.PrintErrorParallel(".", errors.New("failed"), map[string][]byte{})
 			},
 			stdout: `{"issues":[{"rule":{"name":"test_rule","severity":"error","link":"https://github.com"},"message":"test","range":{"filename":"test.tf","start":{"line":1,"column":1},"end":{"line":1,"column":4}},"callers":[],"fixable":false,"fixed":false}],"errors":[{"message":"an error occurred","severity":"error"},{"message":"failed","severity":"error"}]}`,
 			error:  true,
@@ -192,6 +237,21 @@ Reference: https://github.com
 			before: func(f *Formatter) {},
 			stdout: `{"issues":[{"rule":{"name":"test_rule","severity":"error","link":"https://github.com"},"message":"test","range":{"filename":"test.tf","start":{"line":1,"column":1},"end":{"line":1,"column":4}},"callers":[],"fixable":false,"fixed":false}],"errors":[]}`,
 		},
+		{
+			name:   "NDJSON with errors",
+			format: "ndjson",
+			before: func(f *Formatter) {
+				f.PrintErrorParallel(".", errors.New("an error occurred"), map[string][]byte{})
+			},
+			stdout: "", // already streamed
+			error:  true,
+		},
+		{
+			name:   "NDJSON without errors",
+			format: "ndjson",
+			before: func(f *Formatter) {},
+			stdout: "", // already streamed
+		},
 	}
 
 	issues := tflint.Issues{
@@ -0,0 +1,146 @@
+package formatter
+
+import (
+	"bufio"
+	_ "embed"
+	"fmt"
+	"html/template"
+
+	hcl "github.com/hashicorp/hcl/v2"
+	sdk "github.com/terraform-linters/tflint-plugin-sdk/tflint"
+	"github.com/terraform-linters/tflint/tflint"
+)
+
+//go:embed html.tmpl
+var htmlTemplateText string
+
+var htmlTemplate = template.Must(template.New("report").Parse(htmlTemplateText))
+
+type htmlReport struct {
+	Version string
+	Counts  map[string]int
+	Issues  []htmlIssue
+	Errors  []htmlError
+}
+
+type htmlIssue struct {
+	Severity string
+	Message  string
+	Rule     string
+	Link     string
+	Range    hcl.Range
+	Callers  []hcl.Range
+	Lines    []htmlLine
+}
+
+// htmlLine is a line of the source snippet, split around the highlighted range.
+type htmlLine struct {
+	Number      int
+	Before      string
+	Highlighted string
+	After       string
+}
+
+type htmlError struct {
+	Severity string
+	Summary  string
+	Message  string
+	Range    *hcl.Range
+}
+
+type htmlFormat struct{ bufferedFormat }
+
+func (htmlFormat) print(f *Formatter, issues tflint.Issues, appErr error, sources map[string][]byte) {
+	report := htmlReport{
+		Version: tflint.Version.String(),
+		Counts:  map[string]int{"error": 0, "warning": 0, "info": 0},
+		Issues:  make([]htmlIssue, len(issues)),
+		Errors:  f.htmlErrors(appErr),
+	}
+
+	for i, issue := range issues.Sort() {
+		severity := toSeverity(issue.Rule.Severity())
+		report.Counts[severity]++
+
+		message := issue.Message
+		if issue.Fixable {
+			if f.Fix {
+				message = "[Fixed] " + message
+			} else {
+				message = "[Fixable] " + message
+			}
+		}
+
+		src := issue.Source
+		if src == nil {
+			src = sources[issue.Range.Filename]
+		}
+
+		report.Issues[i] = htmlIssue{
+			Severity: severity,
+			Message:  message,
+			Rule:     issue.Rule.Name(),
+			Link:     issue.Rule.Link(),
+			Range:    issue.Range,
+			Callers:  issue.Callers,
+			Lines:    htmlSourceLines(src, issue.Range),
+		}
+	}
+
+	if err := htmlTemplate.Execute(f.Stdout, report); err != nil {
+		fmt.Fprint(f.Stderr, err)
+	}
+}
+
+// htmlSourceLines returns the lines of the source overlapping the given range.
+// Returns nil if the source is not available.
+func htmlSourceLines(src []byte, rng hcl.Range) []htmlLine {
+	if src == nil {
+		return nil
+	}
+
+	lines := []htmlLine{}
+	sc := hcl.NewRangeScanner(src, rng.Filename, bufio.ScanLines)
+	for sc.Scan() {
+		lineRange := sc.Range()
+		if !lineRange.Overlaps(rng) {
+			continue
+		}
+
+		beforeRange, highlightedRange, afterRange := lineRange.PartitionAround(rng)
+		if highlightedRange.Empty() {
+			lines = append(lines, htmlLine{Number: lineRange.Start.Line, Before: string(sc.Bytes())})
+		} else {
+			lines = append(lines, htmlLine{
+				Number:      lineRange.Start.Line,
+				Before:      string(beforeRange.SliceBytes(src)),
+				Highlighted: string(highlightedRange.SliceBytes(src)),
+				After:       string(afterRange.SliceBytes(src)),
+			})
+		}
+	}
+	return lines
+}
+
+func (f *Formatter) htmlErrors(err error) []htmlError {
+	return mapErrors(err, errorMapper[htmlError]{
+		diagnostics: func(_ error, diags hcl.Diagnostics) []htmlError {
+			errors := make([]htmlError, len(diags))
+			for i, diag := range diags {
+				errors[i] = htmlError{
+					Severity: fromHclSeverity(diag.Severity),
+					Summary:  diag.Summary,
+					Message:  diag.Detail,
+					Range:    diag.Subject,
+				}
+			}
+			return errors
+		},
+		error: func(err error) htmlError {
+			return htmlError{
+				Severity: toSeverity(sdk.ERROR),
+				Message:  err.Error(),
+			}
+		},
+	})
+}
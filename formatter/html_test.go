@@ -0,0 +1,103 @@
+package formatter
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+
+	hcl "github.com/hashicorp/hcl/v2"
+	"github.com/terraform-linters/tflint/tflint"
+)
+
+func Test_htmlPrint(t *testing.T) {
+	cases := []struct {
+		Name     string
+		Issues   tflint.Issues
+		Error    error
+		Sources  map[string][]byte
+		Contains []string
+	}{
+		{
+			Name:   "no issues",
+			Issues: tflint.Issues{},
+			Contains: []string{
+				"<tr><td>0</td><td>0</td><td>0</td></tr>",
+				"<p>No issues found.</p>",
+			},
+		},
+		{
+			Name: "issue with source",
+			Issues: tflint.Issues{
+				{
+					Rule:    &testRule{},
+					Message: "<test>",
+					Range: hcl.Range{
+						Filename: "test.tf",
+						Start:    hcl.Pos{Line: 1, Column: 1, Byte: 0},
+						End:      hcl.Pos{Line: 1, Column: 4, Byte: 3},
+					},
+					Callers: []hcl.Range{
+						{
+							Filename: "main.tf",
+							Start:    hcl.Pos{Line: 2, Column: 1},
+							End:      hcl.Pos{Line: 2, Column: 3},
+						},
+					},
+				},
+			},
+			Sources: map[string][]byte{"test.tf": []byte("foo = 1\n")},
+			Contains: []string{
+				"<tr><td>1</td><td>0</td><td>0</td></tr>",
+				`<span class="severity error">error</span>: <strong>&lt;test&gt;</strong> (<a href="https://github.com">test_rule</a>)`,
+				`<pre><span class="lineno">   1:</span> <mark>foo</mark> = 1`,
+				"<li><code>main.tf:2,1-3</code></li>",
+			},
+		},
+		{
+			Name: "issue without source",
+			Issues: tflint.Issues{
+				{
+					Rule:    &testRule{},
+					Message: "test",
+					Range: hcl.Range{
+						Filename: "test.tf",
+						Start:    hcl.Pos{Line: 1, Column: 1, Byte: 0},
+						End:      hcl.Pos{Line: 1, Column: 4, Byte: 3},
+					},
+				},
+			},
+			Contains: []string{
+				"<p>(source code not available)</p>",
+			},
+		},
+		{
+			Name:   "error",
+			Issues: tflint.Issues{},
+			Error:  errors.New("Failed to work; I don't feel like working"),
+			Contains: []string{
+				"<h2>Errors</h2>",
+				`<li><span class="severity error">error</span>: Failed to work; I don&#39;t feel like working</li>`,
+			},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.Name, func(t *testing.T) {
+			stdout := &bytes.Buffer{}
+			stderr := &bytes.Buffer{}
+			formatter := &Formatter{Stdout: stdout, Stderr: stderr, Format: "html"}
+
+			formatter.Print(tc.Issues, tc.Error, tc.Sources)
+
+			for _, expected := range tc.Contains {
+				if !strings.Contains(stdout.String(), expected) {
+					t.Errorf("stdout does not contain %q:\n%s", expected, stdout.String())
+				}
+			}
+			if stderr.String() != "" {
+				t.Errorf("unexpected stderr: %s", stderr.String())
+			}
+		})
+	}
+}
@@ -49,39 +49,18 @@ type JSONError struct {
 
 // JSONOutput is a temporary structure for converting to JSON.
 type JSONOutput struct {
-	Issues []JSONIssue `json:"issues"`
-	Errors []JSONError `json:"errors"`
+	Issues  []JSONIssue  `json:"issues"`
+	Errors  []JSONError  `json:"errors"`
+	Summary *JSONSummary `json:"summary,omitempty"`
 }
 
 type jsonFormat struct{ bufferedFormat }
 
 func (jsonFormat) print(f *Formatter, issues tflint.Issues, appErr error, _ map[string][]byte) {
-	ret := &JSONOutput{Issues: make([]JSONIssue, len(issues)), Errors: f.jsonErrors(appErr)}
+	ret := &JSONOutput{Issues: make([]JSONIssue, len(issues)), Errors: f.jsonErrors(appErr), Summary: f.jsonSummary()}
 
 	for idx, issue := range issues.Sort() {
-		ret.Issues[idx] = JSONIssue{
-			Rule: JSONRule{
-				Name:     issue.Rule.Name(),
-				Severity: toSeverity(issue.Rule.Severity()),
-				Link:     issue.Rule.Link(),
-			},
-			Message: issue.Message,
-			Range: JSONRange{
-				Filename: issue.Range.Filename,
-				Start:    JSONPos{Line: issue.Range.Start.Line, Column: issue.Range.Start.Column},
-				End:      JSONPos{Line: issue.Range.End.Line, Column: issue.Range.End.Column},
-			},
-			Callers: make([]JSONRange, len(issue.Callers)),
-			Fixable: issue.Fixable,
-			Fixed:   issue.Fixable && f.Fix,
-		}
-		for i, caller := range issue.Callers {
-			ret.Issues[idx].Callers[i] = JSONRange{
-				Filename: caller.Filename,
-				Start:    JSONPos{Line: caller.Start.Line, Column: caller.Start.Column},
-				End:      JSONPos{Line: caller.End.Line, Column: caller.End.Column},
-			}
-		}
+		ret.Issues[idx] = f.jsonIssue(issue)
 	}
 
 	out, err := json.Marshal(ret)
@@ -91,6 +70,33 @@ func (jsonFormat) print(f *Formatter, issues tflint.Issues, appErr error, _ map[
 	fmt.Fprint(f.Stdout, string(out))
 }
 
+func (f *Formatter) jsonIssue(issue *tflint.Issue) JSONIssue {
+	ret := JSONIssue{
+		Rule: JSONRule{
+			Name:     issue.Rule.Name(),
+			Severity: toSeverity(issue.Rule.Severity()),
+			Link:     issue.Rule.Link(),
+		},
+		Message: issue.Message,
+		Range: JSONRange{
+			Filename: issue.Range.Filename,
+			Start:    JSONPos{Line: issue.Range.Start.Line, Column: issue.Range.Start.Column},
+			End:      JSONPos{Line: issue.Range.End.Line, Column: issue.Range.End.Column},
+		},
+		Callers: make([]JSONRange, len(issue.Callers)),
+		Fixable: issue.Fixable,
+		Fixed:   issue.Fixable && f.Fix,
+	}
+	for i, caller := range issue.Callers {
+		ret.Callers[i] = JSONRange{
+			Filename: caller.Filename,
+			Start:    JSONPos{Line: caller.Start.Line, Column: caller.Start.Column},
+			End:      JSONPos{Line: caller.End.Line, Column: caller.End.Column},
+		}
+	}
+	return ret
+}
+
 func (f *Formatter) jsonErrors(err error) []JSONError {
 	return mapErrors(err, errorMapper[JSONError]{
 		diagnostics: func(_ error, diags hcl.Diagnostics) []JSONError {
@@ -0,0 +1,133 @@
+package formatter
+
+import (
+	"fmt"
+	"strings"
+
+	hcl "github.com/hashicorp/hcl/v2"
+	sdk "github.com/terraform-linters/tflint-plugin-sdk/tflint"
+	"github.com/terraform-linters/tflint/tflint"
+)
+
+// markdownMaxLength is the maximum length of a markdown report.
+// GitHub rejects comments longer than 65536 characters, so files that
+// don't fit are omitted and only counted in the summary.
+const markdownMaxLength = 60000
+
+type markdownFormat struct{ bufferedFormat }
+
+func (markdownFormat) print(f *Formatter, issues tflint.Issues, appErr error, _ map[string][]byte) {
+	var out strings.Builder
+
+	out.WriteString("### TFLint\n\n")
+
+	errors := f.markdownErrors(appErr)
+	if len(issues) == 0 && len(errors) == 0 {
+		out.WriteString("No issues found.\n")
+		fmt.Fprint(f.Stdout, out.String())
+		return
+	}
+
+	counts := map[tflint.Severity]int{}
+	for _, issue := range issues {
+		counts[issue.Rule.Severity()]++
+	}
+	fmt.Fprintf(&out, "%d issue(s) found.\n\n", len(issues))
+	out.WriteString("| Error | Warning | Notice |\n")
+	out.WriteString("| ---: | ---: | ---: |\n")
+	fmt.Fprintf(&out, "| %d | %d | %d |\n", counts[sdk.ERROR], counts[sdk.WARNING], counts[sdk.NOTICE])
+
+	sections := []string{}
+	var filename string
+	var section strings.Builder
+	var sectionIssues int
+	flush := func() {
+		if sectionIssues == 0 {
+			return
+		}
+		sections = append(sections, fmt.Sprintf(
+			"\n<details><summary><code>%s</code> (%d)</summary>\n\n| Line | Severity | Rule | Message |\n| ---: | --- | --- | --- |\n%s\n</details>\n",
+			markdownEscape(filename), sectionIssues, section.String(),
+		))
+		section.Reset()
+		sectionIssues = 0
+	}
+	for _, issue := range issues.Sort() {
+		if issue.Range.Filename != filename {
+			flush()
+			filename = issue.Range.Filename
+		}
+
+		message := issue.Message
+		if issue.Fixable {
+			if f.Fix {
+				message = "[Fixed] " + message
+			} else {
+				message = "[Fixable] " + message
+			}
+		}
+		rule := fmt.Sprintf("`%s`", issue.Rule.Name())
+		if issue.Rule.Link() != "" {
+			rule = fmt.Sprintf("[%s](%s)", rule, issue.Rule.Link())
+		}
+		fmt.Fprintf(&section, "| %d | %s | %s | %s |\n", issue.Range.Start.Line, issue.Rule.Severity(), rule, markdownEscape(message))
+		sectionIssues++
+	}
+	flush()
+
+	omitted := 0
+	for i, s := range sections {
+		if out.Len()+len(s) > markdownMaxLength {
+			omitted = len(sections) - i
+			break
+		}
+		out.WriteString(s)
+	}
+	if omitted > 0 {
+		fmt.Fprintf(&out, "\n%d file(s) are omitted because the report is too long.\n", omitted)
+	}
+
+	if len(errors) > 0 {
+		out.WriteString("\n#### Errors\n\n")
+		for _, err := range errors {
+			fmt.Fprintf(&out, "- %s\n", err)
+		}
+	}
+
+	fmt.Fprint(f.Stdout, out.String())
+}
+
+func (f *Formatter) markdownErrors(err error) []string {
+	return mapErrors(err, errorMapper[string]{
+		diagnostics: func(_ error, diags hcl.Diagnostics) []string {
+			errors := make([]string, len(diags))
+			for i, diag := range diags {
+				rng := diagRange(diag)
+				errors[i] = fmt.Sprintf(
+					"**%s**: `%s:%d`: %s %s",
+					fromHclSeverity(diag.Severity),
+					rng.Filename,
+					rng.Start.Line,
+					markdownEscape(diag.Summary),
+					markdownEscape(diag.Detail),
+				)
+			}
+			return errors
+		},
+		error: func(err error) string {
+			return fmt.Sprintf("**%s**: %s", toSeverity(sdk.ERROR), markdownEscape(err.Error()))
+		},
+	})
+}
+
+// markdownEscape escapes the given text so that it can be embedded in a table cell.
+func markdownEscape(text string) string {
+	replacer := strings.NewReplacer(
+		"|", `\|`,
+		"<", "&lt;",
+		">", "&gt;",
+		"\r\n", "<br>",
+		"\n", "<br>",
+	)
+	return replacer.Replace(strings.TrimSpace(text))
+}
@@ -0,0 +1,133 @@
+package formatter
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	hcl "github.com/hashicorp/hcl/v2"
+	"github.com/terraform-linters/tflint/tflint"
+)
+
+func Test_markdownPrint(t *testing.T) {
+	cases := []struct {
+		Name   string
+		Issues tflint.Issues
+		Error  error
+		Fix    bool
+		Stdout string
+	}{
+		{
+			Name:   "no issues",
+			Issues: tflint.Issues{},
+			Stdout: `### TFLint
+
+No issues found.
+`,
+		},
+		{
+			Name: "issues",
+			Issues: tflint.Issues{
+				{
+					Rule:    &testRule{},
+					Message: "test | message",
+					Range: hcl.Range{
+						Filename: "test.tf",
+						Start:    hcl.Pos{Line: 1, Column: 1},
+						End:      hcl.Pos{Line: 1, Column: 5},
+					},
+					Fixable: true,
+				},
+				{
+					Rule:    &testRule{},
+					Message: "other message",
+					Range: hcl.Range{
+						Filename: "main.tf",
+						Start:    hcl.Pos{Line: 2, Column: 1},
+						End:      hcl.Pos{Line: 2, Column: 5},
+					},
+				},
+			},
+			Stdout: "### TFLint\n\n2 issue(s) found.\n\n" +
+				"| Error | Warning | Notice |\n| ---: | ---: | ---: |\n| 2 | 0 | 0 |\n\n" +
+				"<details><summary><code>main.tf</code> (1)</summary>\n\n" +
+				"| Line | Severity | Rule | Message |\n| ---: | --- | --- | --- |\n" +
+				"| 2 | Error | [`test_rule`](https://github.com) | other message |\n\n</details>\n\n" +
+				"<details><summary><code>test.tf</code> (1)</summary>\n\n" +
+				"| Line | Severity | Rule | Message |\n| ---: | --- | --- | --- |\n" +
+				"| 1 | Error | [`test_rule`](https://github.com) | [Fixable] test \\| message |\n\n</details>\n",
+		},
+		{
+			Name:   "error",
+			Issues: tflint.Issues{},
+			Error:  errors.New("Failed to work; <I don't feel like working>"),
+			Stdout: "### TFLint\n\n0 issue(s) found.\n\n" +
+				"| Error | Warning | Notice |\n| ---: | ---: | ---: |\n| 0 | 0 | 0 |\n\n" +
+				"#### Errors\n\n- **error**: Failed to work; &lt;I don't feel like working&gt;\n",
+		},
+		{
+			Name:   "diagnostics",
+			Issues: tflint.Issues{},
+			Error: hcl.Diagnostics{
+				&hcl.Diagnostic{
+					Severity: hcl.DiagWarning,
+					Summary:  "summary",
+					Detail:   "detail",
+					Subject: &hcl.Range{
+						Filename: "main.tf",
+						Start:    hcl.Pos{Line: 3, Column: 1},
+						End:      hcl.Pos{Line: 3, Column: 4},
+					},
+				},
+			},
+			Stdout: "### TFLint\n\n0 issue(s) found.\n\n" +
+				"| Error | Warning | Notice |\n| ---: | ---: | ---: |\n| 0 | 0 | 0 |\n\n" +
+				"#### Errors\n\n- **warning**: `main.tf:3`: summary detail\n",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.Name, func(t *testing.T) {
+			stdout := &bytes.Buffer{}
+			stderr := &bytes.Buffer{}
+			formatter := &Formatter{Stdout: stdout, Stderr: stderr, Format: "markdown", Fix: tc.Fix}
+
+			formatter.Print(tc.Issues, tc.Error, map[string][]byte{})
+
+			if diff := cmp.Diff(tc.Stdout, stdout.String()); diff != "" {
+				t.Fatal(diff)
+			}
+		})
+	}
+}
+
+func Test_markdownPrint_omitted(t *testing.T) {
+	issues := tflint.Issues{}
+	for i := range 2000 {
+		issues = append(issues, &tflint.Issue{
+			Rule:    &testRule{},
+			Message: strings.Repeat("x", 100),
+			Range: hcl.Range{
+				Filename: strings.Repeat("a", i%200+1) + ".tf",
+				Start:    hcl.Pos{Line: 1, Column: 1},
+				End:      hcl.Pos{Line: 1, Column: 5},
+			},
+		})
+	}
+
+	stdout := &bytes.Buffer{}
+	formatter := &Formatter{Stdout: stdout, Stderr: &bytes.Buffer{}, Format: "markdown"}
+	formatter.Print(issues, nil, map[string][]byte{})
+
+	if stdout.Len() > markdownMaxLength+100 {
+		t.Errorf("the report is too long: %d", stdout.Len())
+	}
+	if !strings.Contains(stdout.String(), "file(s) are omitted because the report is too long.") {
+		t.Errorf("the report does not contain the omitted message")
+	}
+	if !strings.HasPrefix(stdout.String(), "### TFLint\n\n2000 issue(s) found.\n") {
+		t.Errorf("the report does not contain the total count")
+	}
+}
@@ -0,0 +1,53 @@
+package formatter
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/terraform-linters/tflint/tflint"
+)
+
+// NDJSONRecord is a temporary structure for converting an issue or an error to a line of NDJSON.
+// Exactly one of Issue and Error is set, as indicated by Type.
+type NDJSONRecord struct {
+	Type  string     `json:"type"`
+	Dir   string     `json:"dir"`
+	Issue *JSONIssue `json:"issue,omitempty"`
+	Error *JSONError `json:"error,omitempty"`
+}
+
+const (
+	ndjsonTypeIssue = "issue"
+	ndjsonTypeError = "error"
+)
+
+// ndjsonFormat prints one JSON object per line. In recursive inspection,
+// records are streamed as soon as each worker finishes.
+type ndjsonFormat struct{ bufferedFormat }
+
+func (ndjsonFormat) print(f *Formatter, issues tflint.Issues, appErr error, _ map[string][]byte) {
+	f.ndjsonPrint(".", issues.Sort(), appErr)
+}
+
+func (ndjsonFormat) printWorker(f *Formatter, dir string, issues tflint.Issues, err error) {
+	f.ndjsonPrint(dir, issues.Sort(), err)
+}
+
+func (f *Formatter) ndjsonPrint(dir string, issues tflint.Issues, appErr error) {
+	for _, issue := range issues {
+		jsonIssue := f.jsonIssue(issue)
+		f.ndjsonPrintRecord(NDJSONRecord{Type: ndjsonTypeIssue, Dir: dir, Issue: &jsonIssue})
+	}
+	for _, jsonErr := range f.jsonErrors(appErr) {
+		f.ndjsonPrintRecord(NDJSONRecord{Type: ndjsonTypeError, Dir: dir, Error: &jsonErr})
+	}
+}
+
+func (f *Formatter) ndjsonPrintRecord(record NDJSONRecord) {
+	out, err := json.Marshal(record)
+	if err != nil {
+		fmt.Fprint(f.Stderr, err)
+		return
+	}
+	fmt.Fprintln(f.Stdout, string(out))
+}
@@ -0,0 +1,126 @@
+package formatter
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	hcl "github.com/hashicorp/hcl/v2"
+	"github.com/terraform-linters/tflint/tflint"
+)
+
+func Test_ndjsonPrint(t *testing.T) {
+	cases := []struct {
+		Name   string
+		Issues tflint.Issues
+		Error  error
+		Stdout string
+	}{
+		{
+			Name:   "no issues",
+			Issues: tflint.Issues{},
+			Stdout: "",
+		},
+		{
+			Name: "issues",
+			Issues: tflint.Issues{
+				{
+					Rule:    &testRule{},
+					Message: "test message",
+					Range: hcl.Range{
+						Filename: "test.tf",
+						Start:    hcl.Pos{Line: 1, Column: 1},
+						End:      hcl.Pos{Line: 1, Column: 5},
+					},
+				},
+				{
+					Rule:    &testRule{},
+					Message: "test message",
+					Range: hcl.Range{
+						Filename: "main.tf",
+						Start:    hcl.Pos{Line: 1, Column: 1},
+						End:      hcl.Pos{Line: 1, Column: 5},
+					},
+				},
+			},
+			Stdout: `{"type":"issue","dir":".","issue":{"rule":{"name":"test_rule","severity":"error","link":"https://github.com"},"message":"test message","range":{"filename":"main.tf","start":{"line":1,"column":1},"end":{"line":1,"column":5}},"callers":[],"fixable":false,"fixed":false}}
+{"type":"issue","dir":".","issue":{"rule":{"name":"test_rule","severity":"error","link":"https://github.com"},"message":"test message","range":{"filename":"test.tf","start":{"line":1,"column":1},"end":{"line":1,"column":5}},"callers":[],"fixable":false,"fixed":false}}
+`,
+		},
+		{
+			Name:  "error",
+			Error: errors.New("failed"),
+			Stdout: `{"type":"error","dir":".","error":{"message":"failed","severity":"error"}}
+`,
+		},
+		{
+			Name: "diagnostics",
+			Error: hcl.Diagnostics{
+				&hcl.Diagnostic{
+					Severity: hcl.DiagError,
+					Summary:  "summary",
+					Detail:   "detail",
+					Subject: &hcl.Range{
+						Filename: "main.tf",
+						Start:    hcl.Pos{Line: 1, Column: 1},
+						End:      hcl.Pos{Line: 1, Column: 4},
+					},
+				},
+			},
+			Stdout: `{"type":"error","dir":".","error":{"summary":"summary","message":"detail","severity":"error","range":{"filename":"main.tf","start":{"line":1,"column":1},"end":{"line":1,"column":4}}}}
+`,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.Name, func(t *testing.T) {
+			stdout := &bytes.Buffer{}
+			stderr := &bytes.Buffer{}
+			formatter := &Formatter{Stdout: stdout, Stderr: stderr, Format: "ndjson"}
+
+			formatter.Print(tc.Issues, tc.Error, map[string][]byte{})
+
+			if stdout.String() != tc.Stdout {
+				t.Fatalf("expected=%s, stdout=%s", tc.Stdout, stdout.String())
+			}
+			if stderr.String() != "" {
+				t.Fatalf("unexpected stderr=%s", stderr.String())
+			}
+		})
+	}
+}
+
+func Test_ndjsonPrintParallel(t *testing.T) {
+	stdout := &bytes.Buffer{}
+	stderr := &bytes.Buffer{}
+	formatter := &Formatter{Stdout: stdout, Stderr: stderr, Format: "ndjson"}
+
+	issues := tflint.Issues{
+		{
+			Rule:    &testRule{},
+			Message: "test message",
+			Range: hcl.Range{
+				Filename: "subdir1/main.tf",
+				Start:    hcl.Pos{Line: 1, Column: 1},
+				End:      hcl.Pos{Line: 1, Column: 5},
+			},
+		},
+	}
+
+	formatter.PrintIssuesParallel("subdir1", issues)
+	formatter.PrintErrorParallel("subdir2", errors.New("failed"), map[string][]byte{})
+	err := formatter.PrintParallel(issues, map[string][]byte{})
+	if err == nil {
+		t.Fatal("expected an error, but got nil")
+	}
+
+	expected := `{"type":"issue","dir":"subdir1","issue":{"rule":{"name":"test_rule","severity":"error","link":"https://github.com"},"message":"test message","range":{"filename":"subdir1/main.tf","start":{"line":1,"column":1},"end":{"line":1,"column":5}},"callers":[],"fixable":false,"fixed":false}}
+{"type":"error","dir":"subdir2","error":{"message":"failed","severity":"error"}}
+`
+	if stdout.String() != expected {
+		t.Fatalf("expected=%s, stdout=%s", expected, stdout.String())
+	}
+	if stderr.String() != "" {
+		t.Fatalf("unexpected stderr=%s", stderr.String())
+	}
+}
@@ -32,6 +32,10 @@ func (prettyFormat) print(f *Formatter, issues tflint.Issues, err error, sources
 		}
 	}
 
+	if f.Summary != nil {
+		f.prettyPrintSummary()
+	}
+
 	if err != nil {
 		f.prettyPrintErrors(err, sources, false)
 	}
@@ -0,0 +1,242 @@
+package formatter
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"unicode/utf8"
+
+	hcl "github.com/hashicorp/hcl/v2"
+	sdk "github.com/terraform-linters/tflint-plugin-sdk/tflint"
+	"github.com/terraform-linters/tflint/tflint"
+)
+
+// https://github.com/reviewdog/reviewdog/tree/master/proto/rdf
+
+// RDJSONResult is a temporary structure for converting to Reviewdog Diagnostic Format.
+type RDJSONResult struct {
+	Source      RDJSONSource       `json:"source"`
+	Diagnostics []RDJSONDiagnostic `json:"diagnostics"`
+}
+
+// RDJSONSource is a temporary structure for converting the tool to Reviewdog Diagnostic Format.
+type RDJSONSource struct {
+	Name string `json:"name"`
+	URL  string `json:"url,omitempty"`
+}
+
+// RDJSONDiagnostic is a temporary structure for converting issues and errors to Reviewdog Diagnostic Format.
+type RDJSONDiagnostic struct {
+	Message     string             `json:"message"`
+	Location    RDJSONLocation     `json:"location"`
+	Severity    string             `json:"severity"`
+	Source      *RDJSONSource      `json:"source,omitempty"`
+	Code        *RDJSONCode        `json:"code,omitempty"`
+	Suggestions []RDJSONSuggestion `json:"suggestions,omitempty"`
+}
+
+// RDJSONLocation is a temporary structure for converting locations to Reviewdog Diagnostic Format.
+type RDJSONLocation struct {
+	Path  string       `json:"path"`
+	Range *RDJSONRange `json:"range,omitempty"`
+}
+
+// RDJSONRange is a temporary structure for converting ranges to Reviewdog Diagnostic Format.
+// The end position is exclusive.
+type RDJSONRange struct {
+	Start RDJSONPosition `json:"start"`
+	End   RDJSONPosition `json:"end"`
+}
+
+// RDJSONPosition is a temporary structure for converting positions to Reviewdog Diagnostic Format.
+// Columns are byte counts in UTF-8, starting at 1.
+type RDJSONPosition struct {
+	Line   int `json:"line"`
+	Column int `json:"column"`
+}
+
+// RDJSONCode is a temporary structure for converting rules to Reviewdog Diagnostic Format.
+type RDJSONCode struct {
+	Value string `json:"value"`
+	URL   string `json:"url,omitempty"`
+}
+
+// RDJSONSuggestion is a temporary structure for converting autofixes to Reviewdog Diagnostic Format.
+type RDJSONSuggestion struct {
+	Range RDJSONRange `json:"range"`
+	Text  string      `json:"text"`
+}
+
+var rdjsonSource = RDJSONSource{Name: "tflint", URL: "https://github.com/terraform-linters/tflint"}
+
+type rdjsonFormat struct{ bufferedFormat }
+
+func (rdjsonFormat) suggestsChanges() bool { return true }
+
+func (rdjsonFormat) print(f *Formatter, issues tflint.Issues, appErr error, sources map[string][]byte) {
+	ret := RDJSONResult{Source: rdjsonSource, Diagnostics: f.rdjsonDiagnostics(issues, appErr, sources)}
+
+	out, err := json.Marshal(ret)
+	if err != nil {
+		fmt.Fprint(f.Stderr, err)
+	}
+	fmt.Fprint(f.Stdout, string(out))
+}
+
+// rdjsonlFormat prints a diagnostic per line.
+type rdjsonlFormat struct{ bufferedFormat }
+
+func (rdjsonlFormat) suggestsChanges() bool { return true }
+
+func (rdjsonlFormat) print(f *Formatter, issues tflint.Issues, appErr error, sources map[string][]byte) {
+	for _, diagnostic := range f.rdjsonDiagnostics(issues, appErr, sources) {
+		diagnostic.Source = &rdjsonSource
+
+		out, err := json.Marshal(diagnostic)
+		if err != nil {
+			fmt.Fprint(f.Stderr, err)
+			continue
+		}
+		fmt.Fprintln(f.Stdout, string(out))
+	}
+}
+
+func (f *Formatter) rdjsonDiagnostics(issues tflint.Issues, appErr error, sources map[string][]byte) []RDJSONDiagnostic {
+	diagnostics := []RDJSONDiagnostic{}
+
+	// Hunks of autofixes are suggested only once for each file,
+	// for the first fixable issue they overlap with.
+	// Hunks are based on the original sources, so they are matched only with issues
+	// found in the original sources, not with issues found after an autofix is applied.
+	suggested := map[string]map[tflint.Hunk]bool{}
+
+	for _, issue := range issues.Sort() {
+		src := issue.Source
+		if src == nil {
+			src = sources[issue.Range.Filename]
+		}
+
+		diagnostic := RDJSONDiagnostic{
+			Message:  issue.Message,
+			Location: RDJSONLocation{Path: issue.Range.Filename},
+			Severity: rdjsonSeverity(issue.Rule.Severity()),
+			Code:     &RDJSONCode{Value: issue.Rule.Name(), URL: issue.Rule.Link()},
+		}
+		if issue.Range.Start.Line > 0 {
+			diagnostic.Location.Range = &RDJSONRange{
+				Start: rdjsonPosition(src, issue.Range.Start),
+				End:   rdjsonPosition(src, issue.Range.End),
+			}
+		}
+
+		original, exists := sources[issue.Range.Filename]
+		fixed := f.fixedSource(issue)
+		if issue.Fixable && exists && fixed != nil && (issue.Source == nil || bytes.Equal(issue.Source, original)) {
+			if suggested[issue.Range.Filename] == nil {
+				suggested[issue.Range.Filename] = map[tflint.Hunk]bool{}
+			}
+			for _, h := range tflint.DiffHunks(original, fixed) {
+				if suggested[issue.Range.Filename][h] || !h.Overlaps(issue.Range) {
+					continue
+				}
+				diagnostic.Suggestions = append(diagnostic.Suggestions, RDJSONSuggestion{
+					Range: RDJSONRange{
+						Start: RDJSONPosition{Line: h.Start + 1, Column: 1},
+						End:   RDJSONPosition{Line: h.End + 1, Column: 1},
+					},
+					Text: h.Text,
+				})
+				suggested[issue.Range.Filename][h] = true
+			}
+		}
+
+		diagnostics = append(diagnostics, diagnostic)
+	}
+
+	return append(diagnostics, f.rdjsonErrors(appErr, sources)...)
+}
+
+// fixedSource returns the source of the file of the issue fixed by autofixes.
+// Without --fix, the source fixed only by the plugin of the issue is returned.
+func (f *Formatter) fixedSource(issue *tflint.Issue) []byte {
+	if f.Fix {
+		return f.Changes[issue.Range.Filename]
+	}
+	return f.Suggestions[issue.Rule.Name()][issue.Range.Filename]
+}
+
+func (f *Formatter) rdjsonErrors(err error, sources map[string][]byte) []RDJSONDiagnostic {
+	return mapErrors(err, errorMapper[RDJSONDiagnostic]{
+		diagnostics: func(_ error, diags hcl.Diagnostics) []RDJSONDiagnostic {
+			diagnostics := make([]RDJSONDiagnostic, len(diags))
+			for i, diag := range diags {
+				diagnostics[i] = RDJSONDiagnostic{
+					Message:  diag.Detail,
+					Severity: rdjsonSeverity(fromHclSeverityToSDK(diag.Severity)),
+					Code:     &RDJSONCode{Value: diag.Summary},
+				}
+				if diag.Subject != nil {
+					src := sources[diag.Subject.Filename]
+					diagnostics[i].Location = RDJSONLocation{
+						Path: diag.Subject.Filename,
+						Range: &RDJSONRange{
+							Start: rdjsonPosition(src, diag.Subject.Start),
+							End:   rdjsonPosition(src, diag.Subject.End),
+						},
+					}
+				}
+			}
+			return diagnostics
+		},
+		error: func(err error) RDJSONDiagnostic {
+			return RDJSONDiagnostic{
+				Message:  err.Error(),
+				Severity: rdjsonSeverity(sdk.ERROR),
+				Code:     &RDJSONCode{Value: applicationErrorSource},
+			}
+		},
+	})
+}
+
+func rdjsonSeverity(severity tflint.Severity) string {
+	switch severity {
+	case sdk.ERROR:
+		return "ERROR"
+	case sdk.WARNING:
+		return "WARNING"
+	case sdk.NOTICE:
+		return "INFO"
+	default:
+		panic(fmt.Errorf("Unexpected lint type: %s", severity))
+	}
+}
+
+func fromHclSeverityToSDK(severity hcl.DiagnosticSeverity) tflint.Severity {
+	switch severity {
+	case hcl.DiagError:
+		return sdk.ERROR
+	case hcl.DiagWarning:
+		return sdk.WARNING
+	default:
+		panic(fmt.Errorf("Unexpected HCL severity: %v", severity))
+	}
+}
+
+// rdjsonPosition converts the position to a position in Reviewdog Diagnostic Format.
+// HCL columns count characters, but Reviewdog expects byte counts in UTF-8,
+// so the column is recalculated from the source if available.
+func rdjsonPosition(src []byte, pos hcl.Pos) RDJSONPosition {
+	if src == nil || pos.Byte > len(src) {
+		return RDJSONPosition{Line: pos.Line, Column: pos.Column}
+	}
+
+	lineStart := pos.Byte
+	for lineStart > 0 && src[lineStart-1] != '\n' {
+		lineStart--
+	}
+	// Positions without byte offsets (e.g. built by hand) cannot be converted.
+	if utf8.RuneCount(src[lineStart:pos.Byte])+1 < pos.Column {
+		return RDJSONPosition{Line: pos.Line, Column: pos.Column}
+	}
+	return RDJSONPosition{Line: pos.Line, Column: pos.Byte - lineStart + 1}
+}
@@ -0,0 +1,212 @@
+package formatter
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	hcl "github.com/hashicorp/hcl/v2"
+	"github.com/terraform-linters/tflint/tflint"
+)
+
+func Test_rdjsonPrint(t *testing.T) {
+	cases := []struct {
+		Name    string
+		Issues  tflint.Issues
+		Error   error
+		Fix     bool
+		Changes map[string][]byte
+		// Suggestions are keyed by rule names and file paths
+		Suggestions map[string]map[string][]byte
+		Sources     map[string][]byte
+		Stdout      string
+	}{
+		{
+			Name:   "no issues",
+			Issues: tflint.Issues{},
+			Stdout: `{"source":{"name":"tflint","url":"https://github.com/terraform-linters/tflint"},"diagnostics":[]}`,
+		},
+		{
+			Name: "issue",
+			Issues: tflint.Issues{
+				{
+					Rule:    &testRule{},
+					Message: "test message",
+					Range: hcl.Range{
+						Filename: "test.tf",
+						Start:    hcl.Pos{Line: 1, Column: 10, Byte: 13},
+						End:      hcl.Pos{Line: 1, Column: 13, Byte: 16},
+					},
+				},
+			},
+			Sources: map[string][]byte{"test.tf": []byte(`foo = "ああbar"`)},
+			Stdout:  `{"source":{"name":"tflint","url":"https://github.com/terraform-linters/tflint"},"diagnostics":[{"message":"test message","location":{"path":"test.tf","range":{"start":{"line":1,"column":14},"end":{"line":1,"column":17}}},"severity":"ERROR","code":{"value":"test_rule","url":"https://github.com"}}]}`,
+		},
+		{
+			Name: "fixable issue without fix",
+			Issues: tflint.Issues{
+				{
+					Rule:    &testRule{},
+					Message: "test message",
+					Range: hcl.Range{
+						Filename: "test.tf",
+						Start:    hcl.Pos{Line: 2, Column: 1, Byte: 4},
+						End:      hcl.Pos{Line: 3, Column: 1, Byte: 17},
+					},
+					Fixable: true,
+				},
+			},
+			Sources: map[string][]byte{"test.tf": []byte("a {\n// autofixed\n}\n")},
+			Stdout:  `{"source":{"name":"tflint","url":"https://github.com/terraform-linters/tflint"},"diagnostics":[{"message":"test message","location":{"path":"test.tf","range":{"start":{"line":2,"column":1},"end":{"line":3,"column":1}}},"severity":"ERROR","code":{"value":"test_rule","url":"https://github.com"}}]}`,
+		},
+		{
+			Name: "fixable issue with fix",
+			Issues: tflint.Issues{
+				{
+					Rule:    &testRule{},
+					Message: "test message",
+					Range: hcl.Range{
+						Filename: "test.tf",
+						Start:    hcl.Pos{Line: 2, Column: 1, Byte: 4},
+						End:      hcl.Pos{Line: 3, Column: 1, Byte: 17},
+					},
+					Fixable: true,
+				},
+				{
+					Rule:    &testRule{},
+					Message: "test message",
+					Range: hcl.Range{
+						Filename: "test.tf",
+						Start:    hcl.Pos{Line: 2, Column: 1, Byte: 4},
+						End:      hcl.Pos{Line: 3, Column: 1, Byte: 17},
+					},
+					Fixable: false,
+				},
+			},
+			Fix:     true,
+			Changes: map[string][]byte{"test.tf": []byte("a {\n# autofixed\n}\n")},
+			Sources: map[string][]byte{"test.tf": []byte("a {\n// autofixed\n}\n")},
+			Stdout:  `{"source":{"name":"tflint","url":"https://github.com/terraform-linters/tflint"},"diagnostics":[{"message":"test message","location":{"path":"test.tf","range":{"start":{"line":2,"column":1},"end":{"line":3,"column":1}}},"severity":"ERROR","code":{"value":"test_rule","url":"https://github.com"},"suggestions":[{"range":{"start":{"line":2,"column":1},"end":{"line":3,"column":1}},"text":"# autofixed\n"}]},{"message":"test message","location":{"path":"test.tf","range":{"start":{"line":2,"column":1},"end":{"line":3,"column":1}}},"severity":"ERROR","code":{"value":"test_rule","url":"https://github.com"}}]}`,
+		},
+		{
+			Name: "fixable issue with suggestions",
+			Issues: tflint.Issues{
+				{
+					Rule:    &testRule{},
+					Message: "test message",
+					Range: hcl.Range{
+						Filename: "test.tf",
+						Start:    hcl.Pos{Line: 2, Column: 1, Byte: 4},
+						End:      hcl.Pos{Line: 3, Column: 1, Byte: 17},
+					},
+					Fixable: true,
+				},
+			},
+			Suggestions: map[string]map[string][]byte{"test_rule": {"test.tf": []byte("a {\n# autofixed\n}\n")}},
+			Sources:     map[string][]byte{"test.tf": []byte("a {\n// autofixed\n}\n")},
+			Stdout:      `{"source":{"name":"tflint","url":"https://github.com/terraform-linters/tflint"},"diagnostics":[{"message":"test message","location":{"path":"test.tf","range":{"start":{"line":2,"column":1},"end":{"line":3,"column":1}}},"severity":"ERROR","code":{"value":"test_rule","url":"https://github.com"},"suggestions":[{"range":{"start":{"line":2,"column":1},"end":{"line":3,"column":1}},"text":"# autofixed\n"}]}]}`,
+		},
+		{
+			Name: "fixable issue with suggestions of other rules",
+			Issues: tflint.Issues{
+				{
+					Rule:    &testRule{},
+					Message: "test message",
+					Range: hcl.Range{
+						Filename: "test.tf",
+						Start:    hcl.Pos{Line: 2, Column: 1, Byte: 4},
+						End:      hcl.Pos{Line: 3, Column: 1, Byte: 17},
+					},
+					Fixable: true,
+				},
+			},
+			Suggestions: map[string]map[string][]byte{"other_rule": {"test.tf": []byte("a {\n# autofixed\n}\n")}},
+			Sources:     map[string][]byte{"test.tf": []byte("a {\n// autofixed\n}\n")},
+			Stdout:      `{"source":{"name":"tflint","url":"https://github.com/terraform-linters/tflint"},"diagnostics":[{"message":"test message","location":{"path":"test.tf","range":{"start":{"line":2,"column":1},"end":{"line":3,"column":1}}},"severity":"ERROR","code":{"value":"test_rule","url":"https://github.com"}}]}`,
+		},
+		{
+			Name: "fixable issue found after autofix",
+			Issues: tflint.Issues{
+				{
+					Rule:    &testRule{},
+					Message: "test message",
+					Range: hcl.Range{
+						Filename: "test.tf",
+						Start:    hcl.Pos{Line: 2, Column: 1, Byte: 4},
+						End:      hcl.Pos{Line: 3, Column: 1, Byte: 15},
+					},
+					Fixable: true,
+					Source:  []byte("a {\n# autofix1\n}\n"),
+				},
+			},
+			Fix:     true,
+			Changes: map[string][]byte{"test.tf": []byte("a {\n# autofix2\n}\n")},
+			Sources: map[string][]byte{"test.tf": []byte("a {\n// original\n}\n")},
+			Stdout:  `{"source":{"name":"tflint","url":"https://github.com/terraform-linters/tflint"},"diagnostics":[{"message":"test message","location":{"path":"test.tf","range":{"start":{"line":2,"column":1},"end":{"line":3,"column":1}}},"severity":"ERROR","code":{"value":"test_rule","url":"https://github.com"}}]}`,
+		},
+		{
+			Name:   "error",
+			Issues: tflint.Issues{},
+			Error:  errors.New("failed"),
+			Stdout: `{"source":{"name":"tflint","url":"https://github.com/terraform-linters/tflint"},"diagnostics":[{"message":"failed","location":{"path":""},"severity":"ERROR","code":{"value":"(application)"}}]}`,
+		},
+		{
+			Name:   "diagnostics",
+			Issues: tflint.Issues{},
+			Error: hcl.Diagnostics{
+				&hcl.Diagnostic{
+					Severity: hcl.DiagWarning,
+					Summary:  "summary",
+					Detail:   "detail",
+					Subject: &hcl.Range{
+						Filename: "main.tf",
+						Start:    hcl.Pos{Line: 1, Column: 1, Byte: 0},
+						End:      hcl.Pos{Line: 1, Column: 4, Byte: 3},
+					},
+				},
+			},
+			Stdout: `{"source":{"name":"tflint","url":"https://github.com/terraform-linters/tflint"},"diagnostics":[{"message":"detail","location":{"path":"main.tf","range":{"start":{"line":1,"column":1},"end":{"line":1,"column":4}}},"severity":"WARNING","code":{"value":"summary"}}]}`,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.Name, func(t *testing.T) {
+			stdout := &bytes.Buffer{}
+			stderr := &bytes.Buffer{}
+			formatter := &Formatter{Stdout: stdout, Stderr: stderr, Format: "rdjson", Fix: tc.Fix, Changes: tc.Changes, Suggestions: tc.Suggestions}
+
+			formatter.Print(tc.Issues, tc.Error, tc.Sources)
+
+			if diff := cmp.Diff(tc.Stdout, stdout.String()); diff != "" {
+				t.Fatal(diff)
+			}
+		})
+	}
+}
+
+func Test_rdjsonlPrint(t *testing.T) {
+	stdout := &bytes.Buffer{}
+	stderr := &bytes.Buffer{}
+	formatter := &Formatter{Stdout: stdout, Stderr: stderr, Format: "rdjsonl"}
+
+	issues := tflint.Issues{
+		{
+			Rule:    &testRule{},
+			Message: "test message",
+			Range: hcl.Range{
+				Filename: "test.tf",
+				Start:    hcl.Pos{Line: 1, Column: 1},
+				End:      hcl.Pos{Line: 1, Column: 5},
+			},
+		},
+	}
+	formatter.Print(issues, errors.New("failed"), map[string][]byte{})
+
+	expected := `{"message":"test message","location":{"path":"test.tf","range":{"start":{"line":1,"column":1},"end":{"line":1,"column":5}}},"severity":"ERROR","source":{"name":"tflint","url":"https://github.com/terraform-linters/tflint"},"code":{"value":"test_rule","url":"https://github.com"}}
+{"message":"failed","location":{"path":""},"severity":"ERROR","source":{"name":"tflint","url":"https://github.com/terraform-linters/tflint"},"code":{"value":"(application)"}}
+`
+	if diff := cmp.Diff(expected, stdout.String()); diff != "" {
+		t.Fatal(diff)
+	}
+}
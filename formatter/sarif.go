@@ -23,6 +23,10 @@ func (sarifFormat) print(f *Formatter, issues tflint.Issues, appErr error, _ map
 	version := tflint.Version.String()
 	run.Tool.Driver.Version = &version
 
+	if summary := f.jsonSummary(); summary != nil {
+		run.Properties = sarif.Properties{"summary": summary}
+	}
+
 	report.AddRun(run)
 
 	for _, issue := range issues {
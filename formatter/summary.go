@@ -0,0 +1,93 @@
+package formatter
+
+import (
+	"bytes"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/terraform-linters/tflint/tflint"
+)
+
+// JSONSummary is a temporary structure for converting summary statistics to JSON.
+// Durations are in seconds.
+type JSONSummary struct {
+	Rules         map[string]int     `json:"rules"`
+	Severities    map[string]int     `json:"severities"`
+	Plugins       map[string]int     `json:"plugins"`
+	Dirs          map[string]int     `json:"dirs"`
+	Fixable       int                `json:"fixable"`
+	Unfixable     int                `json:"unfixable"`
+	Files         int                `json:"files"`
+	ModuleRunners int                `json:"module_runners"`
+	Phases        map[string]float64 `json:"phases"`
+	Elapsed       float64            `json:"elapsed"`
+}
+
+func (f *Formatter) jsonSummary() *JSONSummary {
+	if f.Summary == nil {
+		return nil
+	}
+
+	phases := map[string]float64{}
+	for phase, duration := range f.Summary.Phases {
+		phases[phase] = duration.Seconds()
+	}
+
+	return &JSONSummary{
+		Rules:         f.Summary.Rules,
+		Severities:    f.Summary.Severities,
+		Plugins:       f.Summary.Plugins,
+		Dirs:          f.Summary.Dirs,
+		Fixable:       f.Summary.Fixable,
+		Unfixable:     f.Summary.Unfixable,
+		Files:         f.Summary.Files,
+		ModuleRunners: f.Summary.ModuleRunners,
+		Phases:        phases,
+		Elapsed:       f.Summary.Elapsed.Seconds(),
+	}
+}
+
+func (f *Formatter) prettyPrintSummary() {
+	s := f.Summary
+	fmt.Fprintf(f.Stdout, "%s\n\n", colorBold("Summary:"))
+
+	var buf bytes.Buffer
+	w := tabwriter.NewWriter(&buf, 0, 0, 2, ' ', 0)
+
+	printCounts := func(title string, counts map[string]int) {
+		fmt.Fprintf(w, "  %s\t\n", title)
+		if len(counts) == 0 {
+			fmt.Fprint(w, "    (none)\t\n")
+		}
+		for _, key := range slices.Sorted(maps.Keys(counts)) {
+			fmt.Fprintf(w, "    %s\t%d\n", key, counts[key])
+		}
+	}
+	printCounts("Issues by severity", s.Severities)
+	printCounts("Issues by rule", s.Rules)
+	printCounts("Issues by plugin", s.Plugins)
+	printCounts("Issues by directory", s.Dirs)
+
+	fmt.Fprintf(w, "  Fixable issues\t%d\n", s.Fixable)
+	fmt.Fprintf(w, "  Unfixable issues\t%d\n", s.Unfixable)
+	fmt.Fprintf(w, "  Files inspected\t%d\n", s.Files)
+	fmt.Fprintf(w, "  Module runners built\t%d\n", s.ModuleRunners)
+
+	fmt.Fprint(w, "  Time\t\n")
+	for _, phase := range tflint.Phases {
+		fmt.Fprintf(w, "    %s\t%s\n", phase, s.Phases[phase].Round(time.Millisecond))
+	}
+	fmt.Fprintf(w, "    total\t%s\n", s.Elapsed.Round(time.Millisecond))
+
+	_ = w.Flush()
+
+	// Titles are padded by tabwriter, so trim trailing spaces
+	for line := range strings.Lines(buf.String()) {
+		fmt.Fprintln(f.Stdout, strings.TrimRight(line, " \n"))
+	}
+	fmt.Fprint(f.Stdout, "\n")
+}
@@ -0,0 +1,96 @@
+package formatter
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/google/go-cmp/cmp"
+	"github.com/terraform-linters/tflint/tflint"
+)
+
+func testSummary() *tflint.Summary {
+	return &tflint.Summary{
+		Rules:         map[string]int{"test_rule": 2},
+		Severities:    map[string]int{"error": 2},
+		Plugins:       map[string]int{"testing": 2},
+		Dirs:          map[string]int{".": 2},
+		Fixable:       1,
+		Unfixable:     1,
+		Files:         3,
+		ModuleRunners: 1,
+		Phases: map[string]time.Duration{
+			tflint.PhaseConfig:       10 * time.Millisecond,
+			tflint.PhaseLoading:      20 * time.Millisecond,
+			tflint.PhasePluginLaunch: 30 * time.Millisecond,
+			tflint.PhaseCheck:        1500 * time.Millisecond,
+		},
+		Elapsed: 2 * time.Second,
+	}
+}
+
+func Test_prettyPrintSummary(t *testing.T) {
+	// Disable color
+	color.NoColor = true
+
+	stdout := &bytes.Buffer{}
+	formatter := &Formatter{Stdout: stdout, Stderr: &bytes.Buffer{}, Format: "default", Summary: testSummary()}
+
+	formatter.Print(tflint.Issues{}, nil, map[string][]byte{})
+
+	expected := `Summary:
+
+  Issues by severity
+    error               2
+  Issues by rule
+    test_rule           2
+  Issues by plugin
+    testing             2
+  Issues by directory
+    .                   2
+  Fixable issues        1
+  Unfixable issues      1
+  Files inspected       3
+  Module runners built  1
+  Time
+    config              10ms
+    loading             20ms
+    plugin_launch       30ms
+    check               1.5s
+    total               2s
+
+`
+	if diff := cmp.Diff(expected, stdout.String()); diff != "" {
+		t.Fatal(diff)
+	}
+}
+
+func Test_jsonPrintSummary(t *testing.T) {
+	stdout := &bytes.Buffer{}
+	formatter := &Formatter{Stdout: stdout, Stderr: &bytes.Buffer{}, Format: "json", Summary: testSummary()}
+
+	formatter.Print(tflint.Issues{}, nil, map[string][]byte{})
+
+	expected := `{"issues":[],"errors":[],"summary":{"rules":{"test_rule":2},"severities":{"error":2},"plugins":{"testing":2},"dirs":{".":2},"fixable":1,"unfixable":1,"files":3,"module_runners":1,"phases":{"check":1.5,"config":0.01,"loading":0.02,"plugin_launch":0.03},"elapsed":2}}`
+	if diff := cmp.Diff(expected, stdout.String()); diff != "" {
+		t.Fatal(diff)
+	}
+}
+
+func Test_sarifPrintSummary(t *testing.T) {
+	stdout := &bytes.Buffer{}
+	formatter := &Formatter{Stdout: stdout, Stderr: &bytes.Buffer{}, Format: "sarif", Summary: testSummary()}
+
+	formatter.Print(tflint.Issues{}, nil, map[string][]byte{})
+
+	expected := `      "properties": {
+        "summary": {
+          "rules": {
+            "test_rule": 2
+          },`
+	if !strings.Contains(stdout.String(), expected) {
+		t.Fatalf("stdout does not contain the summary:\n%s", stdout.String())
+	}
+}
@@ -80,6 +80,11 @@ func TestIntegration(t *testing.T) {
 			Command: "./tflint --format json --fix --filter=main.tf",
 			Dir:     "filter",
 		},
+		{
+			Name:    "plugin failing in the second attempt",
+			Command: "./tflint --format json --fix",
+			Dir:     "plugin_error",
+		},
 	}
 
 	// Disable the bundled plugin because the `os.Executable()` is go(1) in the tests
@@ -196,3 +201,95 @@ func IsWindowsResultExist() bool {
 	_, err := os.Stat("result_windows.json")
 	return !os.IsNotExist(err)
 }
+
+func TestIntegration_suggestions(t *testing.T) {
+	cases := []struct {
+		Name    string
+		Command string
+		Dir     string
+		// Want is the suggestions for each rule
+		Want map[string][]formatter.RDJSONSuggestion
+	}{
+		{
+			Name:    "suggestions without --fix",
+			Command: "./tflint --format rdjson",
+			Dir:     "simple",
+			Want: map[string][]formatter.RDJSONSuggestion{
+				"terraform_autofix_comment": {
+					{
+						Range: formatter.RDJSONRange{Start: formatter.RDJSONPosition{Line: 1, Column: 1}, End: formatter.RDJSONPosition{Line: 2, Column: 1}},
+						Text:  "# autofixed\n",
+					},
+				},
+			},
+		},
+		{
+			// The failing plugin fails if it sees the autofixed source, so other plugins must not see suggested changes
+			Name:    "suggestions are not seen by other plugins",
+			Command: "./tflint --format rdjson",
+			Dir:     "plugin_error",
+			Want: map[string][]formatter.RDJSONSuggestion{
+				"terraform_autofix_comment": {
+					{
+						Range: formatter.RDJSONRange{Start: formatter.RDJSONPosition{Line: 1, Column: 1}, End: formatter.RDJSONPosition{Line: 2, Column: 1}},
+						Text:  "# autofixed\n",
+					},
+				},
+				"failing_issue":             nil,
+				"aws_instance_example_type": nil,
+			},
+		},
+	}
+
+	// Disable the bundled plugin because the `os.Executable()` is go(1) in the tests
+	tflint.DisableBundledPlugin = true
+	defer func() {
+		tflint.DisableBundledPlugin = false
+	}()
+
+	dir, _ := os.Getwd()
+	for _, tc := range cases {
+		t.Run(tc.Name, func(t *testing.T) {
+			testDir := filepath.Join(dir, tc.Dir)
+			t.Chdir(testDir)
+
+			original, err := os.ReadFile("main.tf")
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer func() {
+				if err := os.WriteFile("main.tf", original, 0644); err != nil {
+					t.Fatal(err)
+				}
+			}()
+
+			outStream, errStream := new(bytes.Buffer), new(bytes.Buffer)
+			cli, err := cmd.NewCLI(outStream, errStream)
+			if err != nil {
+				t.Fatal(err)
+			}
+			cli.Run(strings.Split(tc.Command, " "))
+
+			var got formatter.RDJSONResult
+			if err := json.Unmarshal(outStream.Bytes(), &got); err != nil {
+				t.Fatalf("%s; stdout=%s, stderr=%s", err, outStream, errStream)
+			}
+			suggestions := map[string][]formatter.RDJSONSuggestion{}
+			for _, diagnostic := range got.Diagnostics {
+				suggestions[diagnostic.Code.Value] = diagnostic.Suggestions
+			}
+			if diff := cmp.Diff(tc.Want, suggestions); diff != "" {
+				t.Fatalf("%s; stdout=%s", diff, outStream)
+			}
+
+			// Files should be unchanged without --fix
+			source, err := os.ReadFile("main.tf")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(string(original), string(source)); diff != "" {
+				t.Fatal(diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,7 @@
+plugin "testing" {
+  enabled = true
+}
+
+plugin "failing" {
+  enabled = true
+}
@@ -0,0 +1,4 @@
+// autofixed
+resource "aws_instance" "main" {
+  instance_type = "t2.micro"
+}
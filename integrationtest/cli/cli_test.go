@@ -2,6 +2,7 @@ package main
 
 import (
 	"bytes"
+	"encoding/json"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -280,7 +281,14 @@ func TestIntegration(t *testing.T) {
 			command: "./tflint",
 			dir:     "check_errors",
 			status:  cmd.ExitCodeError,
-			stderr:  `failed to check "aws_cloudformation_stack_error" rule: an error occurred in Check`,
+			stderr:  `Failed to check ruleset of "testing" plugin; failed to check "aws_cloudformation_stack_error" rule: an error occurred in Check`,
+		},
+		{
+			name:    "checking errors are occurred with --fail-on-plugin-error",
+			command: "./tflint --fail-on-plugin-error",
+			dir:     "check_errors",
+			status:  cmd.ExitCodeError,
+			stderr:  `Failed to check ruleset of "testing" plugin; failed to check "aws_cloudformation_stack_error" rule: an error occurred in Check`,
 		},
 		{
 			name:    "files arguments",
@@ -423,6 +431,20 @@ func TestIntegration(t *testing.T) {
 			status:  cmd.ExitCodeOK,
 			stdout:  "[]",
 		},
+		{
+			name:    "--output-file with --version",
+			command: "./tflint --version --output-file=result.txt",
+			dir:     "no_issues",
+			status:  cmd.ExitCodeError,
+			stderr:  "--output-file cannot be used with --version, --init, or --langserver",
+		},
+		{
+			name:    "--output-file with --init",
+			command: "./tflint --init --output-file=result.txt",
+			dir:     "no_issues",
+			status:  cmd.ExitCodeError,
+			stderr:  "--output-file cannot be used with --version, --init, or --langserver",
+		},
 		{
 			name:    "invalid max workers",
 			command: "./tflint --max-workers=0",
@@ -464,3 +486,184 @@ func TestIntegration(t *testing.T) {
 		})
 	}
 }
+
+func TestIntegration_pluginErrors(t *testing.T) {
+	// Disable the bundled plugin because the `os.Executable()` is go(1) in the tests
+	tflint.DisableBundledPlugin = true
+	defer func() {
+		tflint.DisableBundledPlugin = false
+	}()
+
+	tests := []struct {
+		name      string
+		command   string
+		dir       string
+		stdout    string
+		notStdout []string
+		stderr    string
+	}{
+		{
+			name:      "issues of other plugins are reported",
+			command:   "./tflint",
+			dir:       "plugin_errors",
+			stdout:    "instance type is t2.micro",
+			notStdout: []string{"issue emitted before the failure"},
+			stderr:    `Failed to check ruleset of "failing" plugin; failed to check "failing_check" rule: an error occurred in Check`,
+		},
+		{
+			name:      "issues are not reported with --fail-on-plugin-error",
+			command:   "./tflint --fail-on-plugin-error",
+			dir:       "plugin_errors",
+			notStdout: []string{"instance type is t2.micro", "issue emitted before the failure"},
+			stderr:    `Failed to check ruleset of "failing" plugin; failed to check "failing_check" rule: an error occurred in Check`,
+		},
+		{
+			name:      "hanging plugin is killed after the timeout",
+			command:   "./tflint",
+			dir:       "plugin_timeout",
+			stdout:    "instance type is t2.micro",
+			notStdout: []string{"issue emitted before the failure"},
+			stderr:    `Failed to check ruleset of "failing" plugin; timed out after 1s`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Chdir(test.dir)
+
+			outStream, errStream := new(bytes.Buffer), new(bytes.Buffer)
+			cli, err := cmd.NewCLI(outStream, errStream)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if status := cli.Run(strings.Split(test.command, " ")); status != cmd.ExitCodeError {
+				t.Errorf("expected status is %d, but got %d", cmd.ExitCodeError, status)
+			}
+			if !strings.Contains(outStream.String(), test.stdout) {
+				t.Errorf("stdout did not contain expected\n\texpected: %s\n\tgot: %s", test.stdout, outStream.String())
+			}
+			for _, unexpected := range test.notStdout {
+				if strings.Contains(outStream.String(), unexpected) {
+					t.Errorf("stdout contained unexpected\n\tunexpected: %s\n\tgot: %s", unexpected, outStream.String())
+				}
+			}
+			if !strings.Contains(errStream.String(), test.stderr) {
+				t.Errorf("stderr did not contain expected\n\texpected: %s\n\tgot: %s", test.stderr, errStream.String())
+			}
+		})
+	}
+}
+
+func TestIntegration_profile(t *testing.T) {
+	// Disable the bundled plugin because the `os.Executable()` is go(1) in the tests
+	tflint.DisableBundledPlugin = true
+	defer func() {
+		tflint.DisableBundledPlugin = false
+	}()
+
+	profilePath := filepath.Join(t.TempDir(), "profile.json")
+	t.Chdir("issues_found")
+
+	outStream, errStream := new(bytes.Buffer), new(bytes.Buffer)
+	cli, err := cmd.NewCLI(outStream, errStream)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if status := cli.Run([]string{"./tflint", "--profile=" + profilePath}); status != cmd.ExitCodeIssuesFound {
+		t.Fatalf("unexpected exit status: %d, stderr: %s", status, errStream)
+	}
+
+	src, err := os.ReadFile(profilePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var profile struct {
+		TraceEvents []map[string]any       `json:"traceEvents"`
+		Breakdown   []*tflint.ProfileEntry `json:"breakdown"`
+	}
+	if err := json.Unmarshal(src, &profile); err != nil {
+		t.Fatalf("Failed to parse profile: %s\n%s", err, src)
+	}
+	if len(profile.TraceEvents) == 0 {
+		t.Error("expected trace events, but got no events")
+	}
+
+	got := map[string]bool{}
+	for _, entry := range profile.Breakdown {
+		got[fmt.Sprintf("%s/%s/%s", entry.Category, entry.Name, entry.Plugin)] = true
+	}
+	for _, key := range []string{
+		"tflint/LoadConfig/",
+		"tflint/BuildRunners/",
+		"plugin/ApplyConfig/testing",
+		"plugin/Check/testing",
+		"callback/GetModuleContent/testing",
+		"callback/EmitIssue/testing",
+	} {
+		if !got[key] {
+			t.Errorf("expected %s in the breakdown, but not found: %s", key, src)
+		}
+	}
+}
+
+func TestIntegration_profileOnFailure(t *testing.T) {
+	// Disable the bundled plugin because the `os.Executable()` is go(1) in the tests
+	tflint.DisableBundledPlugin = true
+	defer func() {
+		tflint.DisableBundledPlugin = false
+	}()
+
+	tests := []struct {
+		name    string
+		command string
+		dir     string
+		events  bool
+	}{
+		{
+			name:    "plugin error",
+			command: "./tflint --fail-on-plugin-error",
+			dir:     "check_errors",
+			events:  true,
+		},
+		{
+			name:    "workspace not found in recursive mode",
+			command: "./tflint --recursive --chdir=not_found",
+			dir:     "check_errors",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			profilePath := filepath.Join(t.TempDir(), "profile.json")
+			t.Chdir(test.dir)
+
+			outStream, errStream := new(bytes.Buffer), new(bytes.Buffer)
+			cli, err := cmd.NewCLI(outStream, errStream)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			args := append(strings.Split(test.command, " "), "--profile="+profilePath)
+			if status := cli.Run(args); status != cmd.ExitCodeError {
+				t.Fatalf("unexpected exit status: %d, stderr: %s", status, errStream)
+			}
+
+			// The profile is written even if the inspection fails
+			src, err := os.ReadFile(profilePath)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var profile struct {
+				TraceEvents []map[string]any `json:"traceEvents"`
+			}
+			if err := json.Unmarshal(src, &profile); err != nil {
+				t.Fatalf("Failed to parse profile: %s\n%s", err, src)
+			}
+			if test.events && len(profile.TraceEvents) == 0 {
+				t.Error("expected trace events, but got no events")
+			}
+		})
+	}
+}
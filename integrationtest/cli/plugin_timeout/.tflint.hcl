@@ -0,0 +1,8 @@
+plugin "testing" {
+  enabled = true
+}
+
+plugin "failing" {
+  enabled = true
+  timeout = "1s"
+}
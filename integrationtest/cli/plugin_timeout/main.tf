@@ -0,0 +1,5 @@
+resource "aws_instance" "main" {
+  instance_type = "t2.micro"
+}
+
+resource "failing_check" "hang" {}
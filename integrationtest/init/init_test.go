@@ -21,6 +21,10 @@ func TestIntegration(t *testing.T) {
 	current, _ := os.Getwd()
 	dir := filepath.Join(current, "basic")
 
+	// The lock file is created next to the config file
+	lockFile := filepath.Join(dir, ".tflint.lock.hcl")
+	defer os.Remove(lockFile)
+
 	pluginDir := t.TempDir()
 	os.Setenv("TFLINT_PLUGIN_DIR", pluginDir)
 	defer os.Setenv("TFLINT_PLUGIN_DIR", "")
@@ -47,6 +51,10 @@ func TestIntegration(t *testing.T) {
 		t.Fatalf("Expected to contain an installed log, but did not: stdout=%s, stderr=%s", outStream, errStream)
 	}
 
+	if _, err := os.Stat(lockFile); err != nil {
+		t.Fatalf("Expected to create a lock file, but did not: %s", err)
+	}
+
 	cli.Run([]string{"./tflint", "--init"})
 	if !strings.Contains(outStream.String(), `All plugins are already installed`) {
 		t.Fatalf("Expected to contain an already installed log, but did not: stdout=%s, stderr=%s", outStream, errStream)
@@ -36,5 +36,5 @@ func initializeRequest() string {
 }
 
 func initializeResponse() string {
-	return toJSONRPC2(`{"id":0,"result":{"capabilities":{"textDocumentSync":{"openClose":true,"change":1}}},"jsonrpc":"2.0"}`)
+	return toJSONRPC2(`{"id":0,"result":{"capabilities":{"textDocumentSync":{"openClose":true,"change":1,"save":{"includeText":false}},"hoverProvider":true,"completionProvider":{"triggerCharacters":["\""]},"codeActionProvider":true}},"jsonrpc":"2.0"}`)
 }
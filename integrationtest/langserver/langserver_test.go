@@ -25,6 +25,24 @@ type jsonrpcMessage struct {
 	Params  any    `json:"params"`
 }
 
+// diagnostic is a diagnostic published by the server, including the data field
+type diagnostic struct {
+	Range    lsp.Range              `json:"range"`
+	Severity lsp.DiagnosticSeverity `json:"severity,omitempty"`
+	Code     string                 `json:"code,omitempty"`
+	Message  string                 `json:"message"`
+	Data     *diagnosticData        `json:"data,omitempty"`
+}
+
+type diagnosticData struct {
+	Rule string `json:"rule"`
+}
+
+type publishDiagnosticsParams struct {
+	URI         lsp.DocumentURI `json:"uri"`
+	Diagnostics []diagnostic    `json:"diagnostics"`
+}
+
 func TestMain(m *testing.M) {
 	// Disable the bundled plugin because the `os.Executable()` is go(1) in the tests
 	tflint.DisableBundledPlugin = true
@@ -0,0 +1,4 @@
+// autofixed
+resource "null_resource" "foo" {
+  triggers = {}
+}
@@ -0,0 +1,4 @@
+module "instance" {
+  source        = "./module"
+  instance_type = "t1.2xlarge"
+}
@@ -0,0 +1,244 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	lsp "github.com/sourcegraph/go-lsp"
+)
+
+func Test_textDocumentCodeAction(t *testing.T) {
+	withinFixtureDir(t, "autofix", func(dir string) {
+		src, err := os.ReadFile(dir + "/main.tf")
+		if err != nil {
+			t.Fatal(err)
+		}
+		uri := pathToURI(dir + "/main.tf")
+
+		stdin, stdout, plugin := startServer(t, dir+"/.tflint.hcl")
+		defer plugin.Clean()
+
+		go func() {
+			fmt.Fprint(stdin, initializeRequest())
+			fmt.Fprint(stdin, didOpenRequest(uri, string(src), t))
+			fmt.Fprint(stdin, codeActionRequest(uri, lsp.Range{Start: lsp.Position{Line: 0, Character: 3}, End: lsp.Position{Line: 0, Character: 3}}, []diagnostic{}, t))
+			fmt.Fprint(stdin, shutdownRequest())
+			fmt.Fprint(stdin, exitRequest())
+		}()
+
+		buf := new(bytes.Buffer)
+		if _, err := buf.ReadFrom(stdout); err != nil {
+			t.Fatal(err)
+		}
+
+		diag := diagnostic{
+			Message:  `Use "# autofixed" instead of "// autofixed"`,
+			Severity: lsp.Error,
+			Range: lsp.Range{
+				Start: lsp.Position{Line: 0, Character: 0},
+				End:   lsp.Position{Line: 1, Character: 0},
+			},
+			Code: "terraform_autofix_comment",
+			Data: &diagnosticData{Rule: "terraform_autofix_comment"},
+		}
+		didOpenResponse, err := json.Marshal(jsonrpcMessage{
+			Method: "textDocument/publishDiagnostics",
+			Params: publishDiagnosticsParams{
+				URI:         uri,
+				Diagnostics: []diagnostic{diag},
+			},
+			JSONRPC: "2.0",
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		edit := &lsp.WorkspaceEdit{
+			Changes: map[string][]lsp.TextEdit{
+				string(uri): {
+					{
+						Range: lsp.Range{
+							Start: lsp.Position{Line: 0, Character: 0},
+							End:   lsp.Position{Line: 1, Character: 0},
+						},
+						NewText: "# autofixed\n",
+					},
+				},
+			},
+		}
+		codeActionResponse, err := json.Marshal(jsonrpcResponse{
+			ID: 1,
+			Result: []codeAction{
+				{
+					Title:       "Fix terraform_autofix_comment",
+					Kind:        lsp.CAKQuickFix,
+					Diagnostics: []diagnostic{diag},
+					IsPreferred: true,
+					Edit:        edit,
+				},
+				{
+					Title: "Fix all auto-fixable issues",
+					Kind:  "source.fixAll",
+					Edit:  edit,
+				},
+			},
+			JSONRPC: "2.0",
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		expected := initializeResponse() + toJSONRPC2(string(didOpenResponse)) + toJSONRPC2(string(codeActionResponse)) + emptyResponse()
+		if !cmp.Equal(expected, buf.String()) {
+			t.Fatalf("Diff: %s", cmp.Diff(expected, buf.String()))
+		}
+	})
+}
+
+func Test_textDocumentCodeAction_outOfRange(t *testing.T) {
+	withinFixtureDir(t, "autofix", func(dir string) {
+		src, err := os.ReadFile(dir + "/main.tf")
+		if err != nil {
+			t.Fatal(err)
+		}
+		uri := pathToURI(dir + "/main.tf")
+
+		stdin, stdout, plugin := startServer(t, dir+"/.tflint.hcl")
+		defer plugin.Clean()
+
+		go func() {
+			fmt.Fprint(stdin, initializeRequest())
+			fmt.Fprint(stdin, didOpenRequest(uri, string(src), t))
+			fmt.Fprint(stdin, codeActionRequest(uri, lsp.Range{Start: lsp.Position{Line: 2, Character: 0}, End: lsp.Position{Line: 2, Character: 0}}, []diagnostic{}, t))
+			fmt.Fprint(stdin, shutdownRequest())
+			fmt.Fprint(stdin, exitRequest())
+		}()
+
+		buf := new(bytes.Buffer)
+		if _, err := buf.ReadFrom(stdout); err != nil {
+			t.Fatal(err)
+		}
+
+		// Only the source action is returned because the quick fix is out of range
+		expected := `{"id":1,"result":[{"title":"Fix all auto-fixable issues","kind":"source.fixAll","edit":{"changes":{"` + string(uri) + `":[{"range":{"start":{"line":0,"character":0},"end":{"line":1,"character":0}},"newText":"# autofixed\n"}]}}}],"jsonrpc":"2.0"}`
+		if !bytes.Contains(buf.Bytes(), []byte(toJSONRPC2(expected))) {
+			t.Fatalf("response not found: %s", buf.String())
+		}
+	})
+}
+
+func Test_textDocumentCodeAction_ignore(t *testing.T) {
+	withinFixtureDir(t, "workdir", func(dir string) {
+		src, err := os.ReadFile(dir + "/main.tf")
+		if err != nil {
+			t.Fatal(err)
+		}
+		uri := pathToURI(dir + "/main.tf")
+
+		diag := diagnostic{
+			Message:  `instance type is t1.2xlarge`,
+			Severity: lsp.Error,
+			Range: lsp.Range{
+				Start: lsp.Position{Line: 1, Character: 20},
+				End:   lsp.Position{Line: 1, Character: 32},
+			},
+			Code: "aws_instance_example_type",
+			Data: &diagnosticData{Rule: "aws_instance_example_type"},
+		}
+
+		stdin, stdout, plugin := startServer(t, dir+"/.tflint.hcl")
+		defer plugin.Clean()
+
+		go func() {
+			fmt.Fprint(stdin, initializeRequest())
+			fmt.Fprint(stdin, didOpenRequest(uri, string(src), t))
+			fmt.Fprint(stdin, codeActionRequest(uri, diag.Range, []diagnostic{diag}, t))
+			fmt.Fprint(stdin, shutdownRequest())
+			fmt.Fprint(stdin, exitRequest())
+		}()
+
+		buf := new(bytes.Buffer)
+		if _, err := buf.ReadFrom(stdout); err != nil {
+			t.Fatal(err)
+		}
+
+		insertion := func(line int, text string) []lsp.TextEdit {
+			pos := lsp.Position{Line: line, Character: 0}
+			return []lsp.TextEdit{{Range: lsp.Range{Start: pos, End: pos}, NewText: text}}
+		}
+		codeActionResponse, err := json.Marshal(jsonrpcResponse{
+			ID: 1,
+			Result: []codeAction{
+				{
+					Title:       "Ignore aws_instance_example_type for this line",
+					Kind:        lsp.CAKQuickFix,
+					Diagnostics: []diagnostic{diag},
+					Edit: &lsp.WorkspaceEdit{
+						Changes: map[string][]lsp.TextEdit{string(uri): insertion(1, "    # tflint-ignore: aws_instance_example_type\n")},
+					},
+				},
+				{
+					Title:       "Ignore aws_instance_example_type for this file",
+					Kind:        lsp.CAKQuickFix,
+					Diagnostics: []diagnostic{diag},
+					Edit: &lsp.WorkspaceEdit{
+						Changes: map[string][]lsp.TextEdit{string(uri): insertion(0, "# tflint-ignore-file: aws_instance_example_type\n")},
+					},
+				},
+				{
+					Title:       "Disable aws_instance_example_type in .tflint.hcl",
+					Kind:        lsp.CAKQuickFix,
+					Diagnostics: []diagnostic{diag},
+					Edit: &lsp.WorkspaceEdit{
+						Changes: map[string][]lsp.TextEdit{string(pathToURI(dir + "/.tflint.hcl")): insertion(3, "\nrule \"aws_instance_example_type\" {\n  enabled = false\n}\n")},
+					},
+				},
+			},
+			JSONRPC: "2.0",
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		expected := initializeResponse() + didOpenResponse(uri, t) + toJSONRPC2(string(codeActionResponse)) + emptyResponse()
+		if !cmp.Equal(expected, buf.String()) {
+			t.Fatalf("Diff: %s", cmp.Diff(expected, buf.String()))
+		}
+	})
+}
+
+type codeAction struct {
+	Title       string             `json:"title"`
+	Kind        lsp.CodeActionKind `json:"kind,omitempty"`
+	Diagnostics []diagnostic       `json:"diagnostics,omitempty"`
+	IsPreferred bool               `json:"isPreferred,omitempty"`
+	Edit        *lsp.WorkspaceEdit `json:"edit,omitempty"`
+}
+
+type jsonrpcResponse struct {
+	ID      int    `json:"id"`
+	Result  any    `json:"result"`
+	JSONRPC string `json:"jsonrpc"`
+}
+
+func codeActionRequest(uri lsp.DocumentURI, rng lsp.Range, diags []diagnostic, t *testing.T) string {
+	req, err := json.Marshal(jsonrpcMessage{
+		ID:     1,
+		Method: "textDocument/codeAction",
+		Params: map[string]any{
+			"textDocument": lsp.TextDocumentIdentifier{URI: uri},
+			"range":        rng,
+			"context":      map[string]any{"diagnostics": diags},
+		},
+		JSONRPC: "2.0",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return toJSONRPC2(string(req))
+}
@@ -0,0 +1,74 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	lsp "github.com/sourcegraph/go-lsp"
+)
+
+func Test_textDocumentCompletion(t *testing.T) {
+	withinFixtureDir(t, "workdir", func(dir string) {
+		uri := pathToURI(dir + "/main.tf")
+		configURI := pathToURI(dir + "/.tflint.hcl")
+		config := "plugin \"testing\" {\n  enabled = true\n  \n}\n"
+
+		stdin, stdout, plugin := startServer(t, dir+"/.tflint.hcl")
+		defer plugin.Clean()
+
+		go func() {
+			fmt.Fprint(stdin, initializeRequest())
+			fmt.Fprint(stdin, didOpenRequest(uri, `resource "aws_instance" "foo" {}`, t))
+			fmt.Fprint(stdin, didOpenRequest(configURI, config, t))
+			fmt.Fprint(stdin, completionRequest(1, configURI, lsp.Position{Line: 2, Character: 2}, t))
+			fmt.Fprint(stdin, shutdownRequest())
+			fmt.Fprint(stdin, exitRequest())
+		}()
+
+		buf := new(bytes.Buffer)
+		if _, err := buf.ReadFrom(stdout); err != nil {
+			t.Fatal(err)
+		}
+		replace := lsp.Range{Start: lsp.Position{Line: 2, Character: 2}, End: lsp.Position{Line: 2, Character: 2}}
+		items := []lsp.CompletionItem{}
+		for _, attr := range []string{"certificate_identity_regexp", "certificate_oidc_issuer", "enabled", "signature", "signing_key", "source", "timeout", "trusted_root", "version"} {
+			item := lsp.CompletionItem{Label: attr, Kind: lsp.CIKProperty, TextEdit: &lsp.TextEdit{Range: replace, NewText: attr + " = "}}
+			if attr == "enabled" {
+				item.Detail = "required"
+			}
+			items = append(items, item)
+		}
+		completionResponse, err := json.Marshal(jsonrpcResponse{ID: 1, Result: items, JSONRPC: "2.0"})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// The valid config file is published without errors
+		expected := initializeResponse() + noDiagnosticsResponse(configURI, t) + toJSONRPC2(string(completionResponse)) + emptyResponse()
+		if !cmp.Equal(expected, buf.String()) {
+			t.Fatalf("Diff: %s", cmp.Diff(expected, buf.String()))
+		}
+	})
+}
+
+func completionRequest(id int, uri lsp.DocumentURI, pos lsp.Position, t *testing.T) string {
+	req, err := json.Marshal(jsonrpcMessage{
+		ID:     id,
+		Method: "textDocument/completion",
+		Params: lsp.CompletionParams{
+			TextDocumentPositionParams: lsp.TextDocumentPositionParams{
+				TextDocument: lsp.TextDocumentIdentifier{URI: uri},
+				Position:     pos,
+			},
+		},
+		JSONRPC: "2.0",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return toJSONRPC2(string(req))
+}
@@ -0,0 +1,143 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	lsp "github.com/sourcegraph/go-lsp"
+)
+
+type documentDiagnosticReport struct {
+	Kind     string          `json:"kind"`
+	ResultID string          `json:"resultId"`
+	Items    []diagnostic    `json:"items,omitempty"`
+	URI      lsp.DocumentURI `json:"uri,omitempty"`
+}
+
+func Test_textDocumentDiagnostic(t *testing.T) {
+	withinFixtureDir(t, "workdir", func(dir string) {
+		src, err := os.ReadFile(dir + "/main.tf")
+		if err != nil {
+			t.Fatal(err)
+		}
+		uri := pathToURI(dir + "/main.tf")
+
+		stdin, stdout, plugin := startServer(t, dir+"/.tflint.hcl")
+		defer plugin.Clean()
+		reader := bufio.NewReader(stdout)
+
+		fmt.Fprint(stdin, pullInitializeRequest())
+		res := readMessage(t, reader)
+		if !strings.Contains(res, `"diagnosticProvider":{"interFileDependencies":true,"workspaceDiagnostics":true}`) {
+			t.Fatalf("diagnosticProvider is not found: %s", res)
+		}
+
+		// Diagnostics are not published to clients that pull them
+		fmt.Fprint(stdin, didOpenRequest(uri, string(src), t))
+
+		fmt.Fprint(stdin, diagnosticRequest(1, uri, "", t))
+		var full struct {
+			Result documentDiagnosticReport `json:"result"`
+		}
+		if err := json.Unmarshal([]byte(readMessage(t, reader)), &full); err != nil {
+			t.Fatal(err)
+		}
+		expected := []diagnostic{
+			{
+				Message:  `instance type is t1.2xlarge`,
+				Severity: lsp.Error,
+				Range: lsp.Range{
+					Start: lsp.Position{Line: 1, Character: 20},
+					End:   lsp.Position{Line: 1, Character: 32},
+				},
+				Code: "aws_instance_example_type",
+				Data: &diagnosticData{Rule: "aws_instance_example_type"},
+			},
+		}
+		if full.Result.Kind != "full" || full.Result.ResultID == "" {
+			t.Fatalf("unexpected report: %#v", full.Result)
+		}
+		if diff := cmp.Diff(expected, full.Result.Items); diff != "" {
+			t.Fatal(diff)
+		}
+		resultID := full.Result.ResultID
+
+		fmt.Fprint(stdin, diagnosticRequest(2, uri, resultID, t))
+		unchanged := toJSONRPC2(fmt.Sprintf(`{"id":2,"result":{"kind":"unchanged","resultId":"%s"},"jsonrpc":"2.0"}`, resultID))
+		if res := toJSONRPC2(readMessage(t, reader)); res != unchanged {
+			t.Fatalf("Diff: %s", cmp.Diff(unchanged, res))
+		}
+
+		req, err := json.Marshal(jsonrpcMessage{
+			ID:     3,
+			Method: "workspace/diagnostic",
+			Params: map[string]any{
+				"previousResultIds": []map[string]any{{"uri": uri, "value": resultID}},
+			},
+			JSONRPC: "2.0",
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		fmt.Fprint(stdin, toJSONRPC2(string(req)))
+		workspace := toJSONRPC2(fmt.Sprintf(`{"id":3,"result":{"items":[{"kind":"unchanged","resultId":"%s","uri":"%s","version":null}]},"jsonrpc":"2.0"}`, resultID, uri))
+		if res := toJSONRPC2(readMessage(t, reader)); res != workspace {
+			t.Fatalf("Diff: %s", cmp.Diff(workspace, res))
+		}
+
+		fmt.Fprint(stdin, shutdownRequest())
+		if res := toJSONRPC2(readMessage(t, reader)); res != emptyResponse() {
+			t.Fatalf("Diff: %s", cmp.Diff(emptyResponse(), res))
+		}
+		fmt.Fprint(stdin, exitRequest())
+	})
+}
+
+func pullInitializeRequest() string {
+	return toJSONRPC2(`{"id":0,"method":"initialize","params":{"capabilities":{"textDocument":{"diagnostic":{}}}},"jsonrpc":"2.0"}`)
+}
+
+func diagnosticRequest(id int, uri lsp.DocumentURI, previousResultID string, t *testing.T) string {
+	req, err := json.Marshal(jsonrpcMessage{
+		ID:     id,
+		Method: "textDocument/diagnostic",
+		Params: map[string]any{
+			"textDocument":     lsp.TextDocumentIdentifier{URI: uri},
+			"previousResultId": previousResultID,
+		},
+		JSONRPC: "2.0",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return toJSONRPC2(string(req))
+}
+
+// readMessage reads a JSON-RPC message from the server and returns the content
+func readMessage(t *testing.T, r *bufio.Reader) string {
+	header, err := r.ReadString('\n')
+	if err != nil {
+		t.Fatal(err)
+	}
+	length, err := strconv.Atoi(strings.TrimSpace(strings.TrimPrefix(header, "Content-Length:")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := r.ReadString('\n'); err != nil {
+		t.Fatal(err)
+	}
+
+	content := make([]byte, length)
+	if _, err := io.ReadFull(r, content); err != nil {
+		t.Fatal(err)
+	}
+	return string(content)
+}
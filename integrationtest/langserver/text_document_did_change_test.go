@@ -79,9 +79,9 @@ resource "aws_instance" "foo" {
 func noDiagnosticsResponse(uri lsp.DocumentURI, t *testing.T) string {
 	didChangeResponse, err := json.Marshal(jsonrpcMessage{
 		Method: "textDocument/publishDiagnostics",
-		Params: lsp.PublishDiagnosticsParams{
+		Params: publishDiagnosticsParams{
 			URI:         uri,
-			Diagnostics: []lsp.Diagnostic{},
+			Diagnostics: []diagnostic{},
 		},
 		JSONRPC: "2.0",
 	})
@@ -91,3 +91,138 @@ func noDiagnosticsResponse(uri lsp.DocumentURI, t *testing.T) string {
 
 	return toJSONRPC2(string(didChangeResponse))
 }
+
+func Test_textDocumentDidChange_debounce(t *testing.T) {
+	withinFixtureDir(t, "workdir", func(dir string) {
+		src, err := os.ReadFile(dir + "/main.tf")
+		if err != nil {
+			t.Fatal(err)
+		}
+		uri := pathToURI(dir + "/main.tf")
+
+		stdin, stdout, plugin := startServer(t, dir+"/.tflint.hcl")
+		defer plugin.Clean()
+
+		go func() {
+			fmt.Fprint(stdin, initializeRequest())
+			fmt.Fprint(stdin, didOpenRequest(uri, string(src), t))
+			fmt.Fprint(stdin, didChangeRequest(uri, 2, `resource "aws_instance" "foo" {}`, t))
+			fmt.Fprint(stdin, didChangeRequest(uri, 3, string(src), t))
+			fmt.Fprint(stdin, shutdownRequest())
+			fmt.Fprint(stdin, exitRequest())
+		}()
+
+		buf := new(bytes.Buffer)
+		if _, err := buf.ReadFrom(stdout); err != nil {
+			t.Fatal(err)
+		}
+
+		// Only the last edit is inspected
+		expected := initializeResponse() + didOpenResponse(uri, t) + didOpenResponse(uri, t) + emptyResponse()
+		if !cmp.Equal(expected, buf.String()) {
+			t.Fatalf("Diff: %s", cmp.Diff(expected, buf.String()))
+		}
+	})
+}
+
+func didChangeRequest(uri lsp.DocumentURI, version int, text string, t *testing.T) string {
+	req, err := json.Marshal(jsonrpcMessage{
+		Method: "textDocument/didChange",
+		Params: lsp.DidChangeTextDocumentParams{
+			TextDocument: lsp.VersionedTextDocumentIdentifier{
+				TextDocumentIdentifier: lsp.TextDocumentIdentifier{URI: uri},
+				Version:                version,
+			},
+			ContentChanges: []lsp.TextDocumentContentChangeEvent{{Text: text}},
+		},
+		JSONRPC: "2.0",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return toJSONRPC2(string(req))
+}
+
+func Test_textDocumentDidChange_moduleCall(t *testing.T) {
+	withinFixtureDir(t, "module_call", func(dir string) {
+		src, err := os.ReadFile(dir + "/main.tf")
+		if err != nil {
+			t.Fatal(err)
+		}
+		uri := pathToURI(dir + "/main.tf")
+
+		stdin, stdout, plugin := startServer(t, dir+"/.tflint.hcl")
+		defer plugin.Clean()
+
+		go func() {
+			fmt.Fprint(stdin, initializeRequest())
+			fmt.Fprint(stdin, didOpenRequest(uri, string(src), t))
+			fmt.Fprint(stdin, didChangeRequest(uri, 2, `module "instance" {
+  source        = "./module"
+  instance_type = "t2.micro"
+}
+
+resource "aws_instance" "bar" {
+  instance_type = "t3.2xlarge"
+}
+`, t))
+			fmt.Fprint(stdin, shutdownRequest())
+			fmt.Fprint(stdin, exitRequest())
+		}()
+
+		buf := new(bytes.Buffer)
+		if _, err := buf.ReadFrom(stdout); err != nil {
+			t.Fatal(err)
+		}
+
+		moduleCallDiag := func(instanceType string, end int) diagnostic {
+			return diagnostic{
+				Message:  "instance type is " + instanceType,
+				Severity: lsp.Error,
+				Range: lsp.Range{
+					Start: lsp.Position{Line: 2, Character: 18},
+					End:   lsp.Position{Line: 2, Character: end},
+				},
+				Code: "aws_instance_example_type",
+				Data: &diagnosticData{Rule: "aws_instance_example_type"},
+			}
+		}
+		rootDiag := diagnostic{
+			Message:  "instance type is t3.2xlarge",
+			Severity: lsp.Error,
+			Range: lsp.Range{
+				Start: lsp.Position{Line: 6, Character: 18},
+				End:   lsp.Position{Line: 6, Character: 30},
+			},
+			Code: "aws_instance_example_type",
+			Data: &diagnosticData{Rule: "aws_instance_example_type"},
+		}
+
+		// Issues in the called module are reported at the module call argument in the root module,
+		// so the edit in the root module also re-checks the module call with the new argument.
+		expected := initializeResponse() +
+			publishDiagnosticsResponse(uri, []diagnostic{moduleCallDiag("t1.2xlarge", 30)}, t) +
+			publishDiagnosticsResponse(uri, []diagnostic{moduleCallDiag("t2.micro", 28), rootDiag}, t) +
+			emptyResponse()
+		if !cmp.Equal(expected, buf.String()) {
+			t.Fatalf("Diff: %s", cmp.Diff(expected, buf.String()))
+		}
+	})
+}
+
+func publishDiagnosticsResponse(uri lsp.DocumentURI, diags []diagnostic, t *testing.T) string {
+	res, err := json.Marshal(jsonrpcMessage{
+		Method: "textDocument/publishDiagnostics",
+		Params: publishDiagnosticsParams{
+			URI:         uri,
+			Diagnostics: diags,
+		},
+		JSONRPC: "2.0",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return toJSONRPC2(string(res))
+}
@@ -0,0 +1,55 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	lsp "github.com/sourcegraph/go-lsp"
+)
+
+func Test_textDocumentDidClose(t *testing.T) {
+	withinFixtureDir(t, "workdir", func(dir string) {
+		uri := pathToURI(dir + "/main.tf")
+
+		stdin, stdout, plugin := startServer(t, dir+"/.tflint.hcl")
+		defer plugin.Clean()
+
+		go func() {
+			fmt.Fprint(stdin, initializeRequest())
+			// Open with unsaved changes that fix the issue
+			fmt.Fprint(stdin, didOpenRequest(uri, `resource "aws_instance" "foo" {}`, t))
+			// Discard the changes, so the file on disk is inspected
+			fmt.Fprint(stdin, didCloseRequest(uri, t))
+			fmt.Fprint(stdin, shutdownRequest())
+			fmt.Fprint(stdin, exitRequest())
+		}()
+
+		buf := new(bytes.Buffer)
+		if _, err := buf.ReadFrom(stdout); err != nil {
+			t.Fatal(err)
+		}
+
+		expected := initializeResponse() + didOpenResponse(uri, t) + emptyResponse()
+		if !cmp.Equal(expected, buf.String()) {
+			t.Fatalf("Diff: %s", cmp.Diff(expected, buf.String()))
+		}
+	})
+}
+
+func didCloseRequest(uri lsp.DocumentURI, t *testing.T) string {
+	req, err := json.Marshal(jsonrpcMessage{
+		Method: "textDocument/didClose",
+		Params: lsp.DidCloseTextDocumentParams{
+			TextDocument: lsp.TextDocumentIdentifier{URI: uri},
+		},
+		JSONRPC: "2.0",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return toJSONRPC2(string(req))
+}
@@ -65,9 +65,9 @@ func didOpenRequest(uri lsp.DocumentURI, src string, t *testing.T) string {
 func didOpenResponse(uri lsp.DocumentURI, t *testing.T) string {
 	res, err := json.Marshal(jsonrpcMessage{
 		Method: "textDocument/publishDiagnostics",
-		Params: lsp.PublishDiagnosticsParams{
+		Params: publishDiagnosticsParams{
 			URI: uri,
-			Diagnostics: []lsp.Diagnostic{
+			Diagnostics: []diagnostic{
 				{
 					Message:  `instance type is t1.2xlarge`,
 					Severity: lsp.Error,
@@ -75,6 +75,8 @@ func didOpenResponse(uri lsp.DocumentURI, t *testing.T) string {
 						Start: lsp.Position{Line: 1, Character: 20},
 						End:   lsp.Position{Line: 1, Character: 32},
 					},
+					Code: "aws_instance_example_type",
+					Data: &diagnosticData{Rule: "aws_instance_example_type"},
 				},
 			},
 		},
@@ -112,9 +114,9 @@ func Test_textDocumentDidOpen_pathFunctions(t *testing.T) {
 
 		didOpenResponse, err := json.Marshal(jsonrpcMessage{
 			Method: "textDocument/publishDiagnostics",
-			Params: lsp.PublishDiagnosticsParams{
+			Params: publishDiagnosticsParams{
 				URI: uri,
-				Diagnostics: []lsp.Diagnostic{
+				Diagnostics: []diagnostic{
 					{
 						Message:  `instance type is t1.2xlarge`,
 						Severity: lsp.Error,
@@ -122,6 +124,8 @@ func Test_textDocumentDidOpen_pathFunctions(t *testing.T) {
 							Start: lsp.Position{Line: 1, Character: 20},
 							End:   lsp.Position{Line: 1, Character: 53},
 						},
+						Code: "aws_instance_example_type",
+						Data: &diagnosticData{Rule: "aws_instance_example_type"},
 					},
 				},
 			},
@@ -137,3 +141,51 @@ func Test_textDocumentDidOpen_pathFunctions(t *testing.T) {
 		}
 	})
 }
+
+func Test_textDocumentDidOpen_configFile(t *testing.T) {
+	withinFixtureDir(t, "workdir", func(dir string) {
+		uri := pathToURI(dir + "/.tflint.hcl")
+
+		stdin, stdout, plugin := startServer(t, dir+"/.tflint.hcl")
+		defer plugin.Clean()
+
+		go func() {
+			fmt.Fprint(stdin, initializeRequest())
+			fmt.Fprint(stdin, didOpenRequest(uri, "plugin \"testing\" {\n  enabled = true\n}\n\nrule \"aws_instance_exmple_type\" {\n  enabled = false\n}\n", t))
+			fmt.Fprint(stdin, shutdownRequest())
+			fmt.Fprint(stdin, exitRequest())
+		}()
+
+		buf := new(bytes.Buffer)
+		if _, err := buf.ReadFrom(stdout); err != nil {
+			t.Fatal(err)
+		}
+
+		configResponse, err := json.Marshal(jsonrpcMessage{
+			Method: "textDocument/publishDiagnostics",
+			Params: publishDiagnosticsParams{
+				URI: uri,
+				Diagnostics: []diagnostic{
+					{
+						Message:  `Rule not found: aws_instance_exmple_type. Did you mean "aws_instance_example_type"?`,
+						Severity: lsp.Error,
+						Range: lsp.Range{
+							Start: lsp.Position{Line: 4, Character: 5},
+							End:   lsp.Position{Line: 4, Character: 31},
+						},
+					},
+				},
+			},
+			JSONRPC: "2.0",
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// The root module loading the config file is also inspected
+		expected := initializeResponse() + toJSONRPC2(string(configResponse)) + didOpenResponse(pathToURI(dir+"/main.tf"), t) + emptyResponse()
+		if !cmp.Equal(expected, buf.String()) {
+			t.Fatalf("Diff: %s", cmp.Diff(expected, buf.String()))
+		}
+	})
+}
@@ -0,0 +1,172 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	lsp "github.com/sourcegraph/go-lsp"
+)
+
+func Test_textDocumentHover(t *testing.T) {
+	withinFixtureDir(t, "workdir", func(dir string) {
+		src, err := os.ReadFile(dir + "/main.tf")
+		if err != nil {
+			t.Fatal(err)
+		}
+		uri := pathToURI(dir + "/main.tf")
+
+		stdin, stdout, plugin := startServer(t, dir+"/.tflint.hcl")
+		defer plugin.Clean()
+
+		go func() {
+			fmt.Fprint(stdin, initializeRequest())
+			fmt.Fprint(stdin, didOpenRequest(uri, string(src), t))
+			fmt.Fprint(stdin, hoverRequest(1, uri, lsp.Position{Line: 1, Character: 25}, t))
+			fmt.Fprint(stdin, hoverRequest(2, uri, lsp.Position{Line: 0, Character: 0}, t))
+			fmt.Fprint(stdin, shutdownRequest())
+			fmt.Fprint(stdin, exitRequest())
+		}()
+
+		buf := new(bytes.Buffer)
+		if _, err := buf.ReadFrom(stdout); err != nil {
+			t.Fatal(err)
+		}
+
+		hoverResponse, err := json.Marshal(jsonrpcResponse{
+			ID: 1,
+			Result: map[string]any{
+				"contents": map[string]any{
+					"kind":  "markdown",
+					"value": "**aws_instance_example_type**\n\ninstance type is t1.2xlarge\n\n- Plugin: testing\n- Severity: Error\n- Fixable: no",
+				},
+				"range": lsp.Range{
+					Start: lsp.Position{Line: 1, Character: 20},
+					End:   lsp.Position{Line: 1, Character: 32},
+				},
+			},
+			JSONRPC: "2.0",
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		// No issues at the position
+		noHoverResponse := `{"id":2,"result":null,"jsonrpc":"2.0"}`
+
+		expected := initializeResponse() + didOpenResponse(uri, t) + toJSONRPC2(string(hoverResponse)) + toJSONRPC2(noHoverResponse) + emptyResponse()
+		if !cmp.Equal(expected, buf.String()) {
+			t.Fatalf("Diff: %s", cmp.Diff(expected, buf.String()))
+		}
+	})
+}
+
+func hoverRequest(id int, uri lsp.DocumentURI, pos lsp.Position, t *testing.T) string {
+	req, err := json.Marshal(jsonrpcMessage{
+		ID:     id,
+		Method: "textDocument/hover",
+		Params: lsp.TextDocumentPositionParams{
+			TextDocument: lsp.TextDocumentIdentifier{URI: uri},
+			Position:     pos,
+		},
+		JSONRPC: "2.0",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return toJSONRPC2(string(req))
+}
+
+func Test_textDocumentHover_value(t *testing.T) {
+	withinTempDir(t, func(dir string) {
+		content := `variable "instance_type" {}
+
+resource "aws_instance" "foo" {
+  instance_type = var.instance_type
+}`
+
+		config := `
+plugin "testing" {
+    enabled = true
+}`
+
+		if err := os.WriteFile(dir+"/main.tf", []byte(content), os.ModePerm); err != nil {
+			t.Fatal(err)
+		}
+		// The value is resolved from tfvars as in inspections
+		if err := os.WriteFile(dir+"/terraform.tfvars", []byte(`instance_type = "t2.micro"`), os.ModePerm); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(dir+"/.tflint.hcl", []byte(config), os.ModePerm); err != nil {
+			t.Fatal(err)
+		}
+		uri := pathToURI(dir + "/main.tf")
+
+		stdin, stdout, plugin := startServer(t, dir+"/.tflint.hcl")
+		defer plugin.Clean()
+
+		go func() {
+			fmt.Fprint(stdin, initializeRequest())
+			fmt.Fprint(stdin, didOpenRequest(uri, content, t))
+			fmt.Fprint(stdin, hoverRequest(1, uri, lsp.Position{Line: 3, Character: 25}, t))
+			fmt.Fprint(stdin, shutdownRequest())
+			fmt.Fprint(stdin, exitRequest())
+		}()
+
+		buf := new(bytes.Buffer)
+		if _, err := buf.ReadFrom(stdout); err != nil {
+			t.Fatal(err)
+		}
+
+		hoverResponse, err := json.Marshal(jsonrpcResponse{
+			ID: 1,
+			Result: map[string]any{
+				"contents": map[string]any{
+					"kind": "markdown",
+					"value": "**aws_instance_example_type**\n\ninstance type is t2.micro\n\n- Plugin: testing\n- Severity: Error\n- Fixable: no\n\n---\n\n" +
+						"**`var.instance_type`**\n\n```hcl\n\"t2.micro\"\n```\n\n- Type: string",
+				},
+				"range": lsp.Range{
+					Start: lsp.Position{Line: 3, Character: 18},
+					End:   lsp.Position{Line: 3, Character: 35},
+				},
+			},
+			JSONRPC: "2.0",
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		didOpenResponse, err := json.Marshal(jsonrpcMessage{
+			Method: "textDocument/publishDiagnostics",
+			Params: publishDiagnosticsParams{
+				URI: uri,
+				Diagnostics: []diagnostic{
+					{
+						Message:  "instance type is t2.micro",
+						Severity: lsp.Error,
+						Range: lsp.Range{
+							Start: lsp.Position{Line: 3, Character: 18},
+							End:   lsp.Position{Line: 3, Character: 35},
+						},
+						Code: "aws_instance_example_type",
+						Data: &diagnosticData{Rule: "aws_instance_example_type"},
+					},
+				},
+			},
+			JSONRPC: "2.0",
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// The issue and the value of the expression are shown together
+		expected := initializeResponse() + toJSONRPC2(string(didOpenResponse)) + toJSONRPC2(string(hoverResponse)) + emptyResponse()
+		if !cmp.Equal(expected, buf.String()) {
+			t.Fatalf("Diff: %s", cmp.Diff(expected, buf.String()))
+		}
+	})
+}
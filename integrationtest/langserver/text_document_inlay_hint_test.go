@@ -0,0 +1,97 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	lsp "github.com/sourcegraph/go-lsp"
+)
+
+func Test_textDocumentInlayHint(t *testing.T) {
+	withinTempDir(t, func(dir string) {
+		content := `variable "zones" {
+  default = ["a", "b"]
+}
+
+resource "null_resource" "foo" {
+  count = length(var.zones)
+}`
+
+		config := `
+plugin "testing" {
+    enabled = true
+}`
+
+		if err := os.WriteFile(dir+"/main.tf", []byte(content), os.ModePerm); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(dir+"/.tflint.hcl", []byte(config), os.ModePerm); err != nil {
+			t.Fatal(err)
+		}
+		uri := pathToURI(dir + "/main.tf")
+
+		stdin, stdout, plugin := startServer(t, dir+"/.tflint.hcl")
+		defer plugin.Clean()
+
+		req, err := json.Marshal(jsonrpcMessage{
+			ID:     1,
+			Method: "textDocument/inlayHint",
+			Params: map[string]any{
+				"textDocument": lsp.TextDocumentIdentifier{URI: uri},
+				"range": lsp.Range{
+					Start: lsp.Position{Line: 0, Character: 0},
+					End:   lsp.Position{Line: 7, Character: 0},
+				},
+			},
+			JSONRPC: "2.0",
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		go func() {
+			// Inlay hints are provided only to clients that declare support
+			fmt.Fprint(stdin, toJSONRPC2(`{"id":0,"method":"initialize","params":{"capabilities":{"textDocument":{"inlayHint":{}}}},"jsonrpc":"2.0"}`))
+			fmt.Fprint(stdin, didOpenRequest(uri, content, t))
+			fmt.Fprint(stdin, toJSONRPC2(string(req)))
+			fmt.Fprint(stdin, shutdownRequest())
+			fmt.Fprint(stdin, exitRequest())
+		}()
+
+		buf := new(bytes.Buffer)
+		if _, err := buf.ReadFrom(stdout); err != nil {
+			t.Fatal(err)
+		}
+
+		res, err := json.Marshal(jsonrpcResponse{
+			ID: 1,
+			Result: []inlayHint{
+				{
+					Position:    lsp.Position{Line: 5, Character: 27},
+					Label:       "2 instances",
+					PaddingLeft: true,
+				},
+			},
+			JSONRPC: "2.0",
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		expected := toJSONRPC2(`{"id":0,"result":{"capabilities":{"textDocumentSync":{"openClose":true,"change":1,"save":{"includeText":false}},"hoverProvider":true,"completionProvider":{"triggerCharacters":["\""]},"codeActionProvider":true,"inlayHintProvider":true}},"jsonrpc":"2.0"}`) +
+			toJSONRPC2(string(res)) + emptyResponse()
+		if !cmp.Equal(expected, buf.String()) {
+			t.Fatalf("Diff: %s", cmp.Diff(expected, buf.String()))
+		}
+	})
+}
+
+type inlayHint struct {
+	Position    lsp.Position `json:"position"`
+	Label       string       `json:"label"`
+	PaddingLeft bool         `json:"paddingLeft,omitempty"`
+}
@@ -0,0 +1,60 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func Test_windowWorkDoneProgress(t *testing.T) {
+	withinTempDir(t, func(dir string) {
+		content := `resource "null_resource" "foo" {}`
+
+		config := `
+plugin "testing" {
+    enabled = true
+}`
+
+		if err := os.WriteFile(dir+"/main.tf", []byte(content), os.ModePerm); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(dir+"/.tflint.hcl", []byte(config), os.ModePerm); err != nil {
+			t.Fatal(err)
+		}
+		uri := pathToURI(dir + "/main.tf")
+
+		stdin, stdout, plugin := startServer(t, dir+"/.tflint.hcl")
+		defer plugin.Clean()
+
+		go func() {
+			// Progress is reported only to clients that declare support
+			fmt.Fprint(stdin, toJSONRPC2(`{"id":0,"method":"initialize","params":{"capabilities":{"window":{"workDoneProgress":true}}},"jsonrpc":"2.0"}`))
+			fmt.Fprint(stdin, didOpenRequest(uri, content, t))
+			fmt.Fprint(stdin, shutdownRequest())
+			fmt.Fprint(stdin, exitRequest())
+		}()
+
+		buf := new(bytes.Buffer)
+		if _, err := buf.ReadFrom(stdout); err != nil {
+			t.Fatal(err)
+		}
+
+		expected := toJSONRPC2(`{"id":0,"result":{"capabilities":{"textDocumentSync":{"openClose":true,"change":1,"save":{"includeText":false}},"hoverProvider":true,"completionProvider":{"triggerCharacters":["\""]},"codeActionProvider":true}},"jsonrpc":"2.0"}`) +
+			// Launching plugins for the root module of the opened document
+			toJSONRPC2(`{"id":0,"jsonrpc":"2.0","method":"window/workDoneProgress/create","params":{"token":"tflint-1"}}`) +
+			toJSONRPC2(`{"jsonrpc":"2.0","method":"$/progress","params":{"token":"tflint-1","value":{"kind":"begin","title":"TFLint","cancellable":false,"message":"Launching plugins","percentage":0}}}`) +
+			toJSONRPC2(`{"jsonrpc":"2.0","method":"$/progress","params":{"token":"tflint-1","value":{"kind":"end"}}}`) +
+			// Inspecting the root module
+			toJSONRPC2(`{"id":1,"jsonrpc":"2.0","method":"window/workDoneProgress/create","params":{"token":"tflint-2"}}`) +
+			toJSONRPC2(`{"jsonrpc":"2.0","method":"$/progress","params":{"token":"tflint-2","value":{"kind":"begin","title":"TFLint","cancellable":true,"message":"Loading modules","percentage":0}}}`) +
+			toJSONRPC2(`{"jsonrpc":"2.0","method":"$/progress","params":{"token":"tflint-2","value":{"kind":"report","message":"Running \"testing\" ruleset","percentage":0}}}`) +
+			toJSONRPC2(`{"jsonrpc":"2.0","method":"$/progress","params":{"token":"tflint-2","value":{"kind":"end"}}}`) +
+			emptyResponse()
+		if !cmp.Equal(expected, buf.String()) {
+			t.Fatalf("Diff: %s", cmp.Diff(expected, buf.String()))
+		}
+	})
+}
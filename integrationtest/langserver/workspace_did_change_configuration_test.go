@@ -0,0 +1,138 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	lsp "github.com/sourcegraph/go-lsp"
+)
+
+func Test_workspaceDidChangeConfiguration(t *testing.T) {
+	withinTempDir(t, func(dir string) {
+		content := `resource "aws_instance" "foo" {
+    instance_type = "t1.2xlarge"
+}`
+
+		config := `
+plugin "testing" {
+    enabled = true
+}`
+
+		invalidConfig := `
+plugin "testing" {
+    enabled = true
+`
+
+		changedConfig := `
+plugin "testing" {
+    enabled = true
+}
+
+rule "aws_instance_example_type" {
+    enabled = false
+}`
+
+		if err := os.WriteFile(dir+"/main.tf", []byte(content), os.ModePerm); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(dir+"/.tflint.hcl", []byte(config), os.ModePerm); err != nil {
+			t.Fatal(err)
+		}
+		uri := pathToURI(dir + "/main.tf")
+		configURI := pathToURI(dir + "/.tflint.hcl")
+
+		stdin, stdout, plugin := startServer(t, dir+"/.tflint.hcl")
+		defer plugin.Clean()
+		reader := bufio.NewReader(stdout)
+
+		configErrorResponse, err := json.Marshal(jsonrpcMessage{
+			Method: "textDocument/publishDiagnostics",
+			Params: publishDiagnosticsParams{
+				URI: configURI,
+				Diagnostics: []diagnostic{
+					{
+						Message:  "Unclosed configuration block; There is no closing brace for this block before the end of the file. This may be caused by incorrect brace nesting elsewhere in this file.",
+						Severity: lsp.Error,
+						Range: lsp.Range{
+							Start: lsp.Position{Line: 1, Character: 17},
+							End:   lsp.Position{Line: 1, Character: 18},
+						},
+					},
+				},
+			},
+			JSONRPC: "2.0",
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// Messages are read one by one because the config file must be changed after the previous request is handled
+		steps := []struct {
+			config   string
+			request  string
+			expected string
+		}{
+			{
+				request:  initializeRequest(),
+				expected: initializeResponse(),
+			},
+			{
+				request:  didOpenRequest(uri, content, t),
+				expected: didOpenResponse(uri, t),
+			},
+			{
+				// The config error is shown, and the previous config is kept
+				config:   invalidConfig,
+				request:  didChangeConfigurationRequest(t),
+				expected: toJSONRPC2(string(configErrorResponse)),
+			},
+			{
+				// The config error is cleared, and the new config is applied
+				config:   changedConfig,
+				request:  didChangeConfigurationRequest(t),
+				expected: noDiagnosticsResponse(configURI, t) + noDiagnosticsResponse(uri, t),
+			},
+			{
+				request:  shutdownRequest(),
+				expected: emptyResponse(),
+			},
+		}
+
+		for _, step := range steps {
+			if step.config != "" {
+				if err := os.WriteFile(dir+"/.tflint.hcl", []byte(step.config), os.ModePerm); err != nil {
+					t.Fatal(err)
+				}
+			}
+			fmt.Fprint(stdin, step.request)
+
+			got := ""
+			for len(got) < len(step.expected) {
+				got += toJSONRPC2(readMessage(t, reader))
+			}
+			if !cmp.Equal(step.expected, got) {
+				t.Fatalf("Diff: %s", cmp.Diff(step.expected, got))
+			}
+		}
+		fmt.Fprint(stdin, exitRequest())
+	})
+}
+
+func didChangeConfigurationRequest(t *testing.T) string {
+	req, err := json.Marshal(jsonrpcMessage{
+		Method: "workspace/didChangeConfiguration",
+		Params: lsp.DidChangeConfigurationParams{
+			Settings: map[string]any{},
+		},
+		JSONRPC: "2.0",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return toJSONRPC2(string(req))
+}
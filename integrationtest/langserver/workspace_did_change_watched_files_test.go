@@ -1,6 +1,7 @@
 package main
 
 import (
+	"bufio"
 	"bytes"
 	"encoding/json"
 	"fmt"
@@ -63,6 +64,20 @@ rule "aws_instance_example_type" {
 		go func() {
 			fmt.Fprint(stdin, initializeRequest())
 			fmt.Fprint(stdin, didOpenRequest(uri, content, t))
+		}()
+
+		// The config file must be changed after the root module is loaded on opening the document
+		reader := bufio.NewReader(stdout)
+		expected := initializeResponse() + didOpenResponse(uri, t)
+		got := ""
+		for len(got) < len(expected) {
+			got += toJSONRPC2(readMessage(t, reader))
+		}
+		if !cmp.Equal(expected, got) {
+			t.Fatalf("Diff: %s", cmp.Diff(expected, got))
+		}
+
+		go func() {
 			// Change config file from outside of LSP
 			_ = os.WriteFile(dir+"/.tflint.hcl", []byte(changedConfig), os.ModePerm)
 			fmt.Fprint(stdin, toJSONRPC2(string(req)))
@@ -71,11 +86,11 @@ rule "aws_instance_example_type" {
 		}()
 
 		buf := new(bytes.Buffer)
-		if _, err := buf.ReadFrom(stdout); err != nil {
+		if _, err := buf.ReadFrom(reader); err != nil {
 			t.Fatal(err)
 		}
 
-		expected := initializeResponse() + didOpenResponse(uri, t) + noDiagnosticsResponse(uri, t) + emptyResponse()
+		expected = noDiagnosticsResponse(uri, t) + emptyResponse()
 		if !cmp.Equal(expected, buf.String()) {
 			t.Fatalf("Diff: %s", cmp.Diff(expected, buf.String()))
 		}
@@ -150,9 +165,9 @@ plugin "testing" {
 
 		didOpenResponse, err := json.Marshal(jsonrpcMessage{
 			Method: "textDocument/publishDiagnostics",
-			Params: lsp.PublishDiagnosticsParams{
+			Params: publishDiagnosticsParams{
 				URI: uri,
-				Diagnostics: []lsp.Diagnostic{
+				Diagnostics: []diagnostic{
 					{
 						Message:  `instance type is t1.2xlarge`,
 						Severity: lsp.Error,
@@ -160,6 +175,8 @@ plugin "testing" {
 							Start: lsp.Position{Line: 2, Character: 20},
 							End:   lsp.Position{Line: 2, Character: 37},
 						},
+						Code: "aws_instance_example_type",
+						Data: &diagnosticData{Rule: "aws_instance_example_type"},
 					},
 				},
 			},
@@ -0,0 +1,100 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func Test_workspaceDidChangeWorkspaceFolders(t *testing.T) {
+	withinTempDir(t, func(dir string) {
+		content := `resource "aws_instance" "foo" {
+    instance_type = "t1.2xlarge"
+}`
+
+		// The root module "a" uses the config file of the workspace folder,
+		// and the root module "b" uses its own config file.
+		config := `
+plugin "testing" {
+    enabled = true
+}`
+
+		ownConfig := `
+plugin "testing" {
+    enabled = true
+}
+
+rule "aws_instance_example_type" {
+    enabled = false
+}`
+
+		if err := os.MkdirAll(dir+"/a", os.ModePerm); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.MkdirAll(dir+"/b", os.ModePerm); err != nil {
+			t.Fatal(err)
+		}
+		for path, src := range map[string]string{
+			"/.tflint.hcl":   config,
+			"/a/main.tf":     content,
+			"/b/main.tf":     content,
+			"/b/.tflint.hcl": ownConfig,
+		} {
+			if err := os.WriteFile(dir+path, []byte(src), os.ModePerm); err != nil {
+				t.Fatal(err)
+			}
+		}
+		uriA := pathToURI(dir + "/a/main.tf")
+		uriB := pathToURI(dir + "/b/main.tf")
+
+		stdin, stdout, plugin := startServer(t, "")
+		defer plugin.Clean()
+
+		// Requests with ID 0 cannot be built from jsonrpcMessage because the ID is omitted
+		initialize := fmt.Sprintf(
+			`{"id":0,"method":"initialize","params":{"workspaceFolders":[{"uri":"%s","name":"workspace"}],"capabilities":{"workspace":{"workspaceFolders":true}}},"jsonrpc":"2.0"}`,
+			pathToURI(dir),
+		)
+		removeFolder, err := json.Marshal(jsonrpcMessage{
+			Method: "workspace/didChangeWorkspaceFolders",
+			Params: map[string]any{
+				"event": map[string]any{
+					"added":   []any{},
+					"removed": []map[string]any{{"uri": pathToURI(dir), "name": "workspace"}},
+				},
+			},
+			JSONRPC: "2.0",
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		go func() {
+			fmt.Fprint(stdin, toJSONRPC2(initialize))
+			fmt.Fprint(stdin, didOpenRequest(uriA, content, t))
+			fmt.Fprint(stdin, didOpenRequest(uriB, content, t))
+			// Diagnostics of root modules in the removed folder are cleared
+			fmt.Fprint(stdin, toJSONRPC2(string(removeFolder)))
+			fmt.Fprint(stdin, shutdownRequest())
+			fmt.Fprint(stdin, exitRequest())
+		}()
+
+		buf := new(bytes.Buffer)
+		if _, err := buf.ReadFrom(stdout); err != nil {
+			t.Fatal(err)
+		}
+
+		expected := workspaceFoldersInitializeResponse() + didOpenResponse(uriA, t) + noDiagnosticsResponse(uriA, t) + emptyResponse()
+		if !cmp.Equal(expected, buf.String()) {
+			t.Fatalf("Diff: %s", cmp.Diff(expected, buf.String()))
+		}
+	})
+}
+
+func workspaceFoldersInitializeResponse() string {
+	return toJSONRPC2(`{"id":0,"result":{"capabilities":{"textDocumentSync":{"openClose":true,"change":1,"save":{"includeText":false}},"hoverProvider":true,"completionProvider":{"triggerCharacters":["\""]},"codeActionProvider":true,"workspace":{"workspaceFolders":{"supported":true,"changeNotifications":true}}}},"jsonrpc":"2.0"}`)
+}
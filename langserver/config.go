@@ -0,0 +1,362 @@
+package langserver
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"path/filepath"
+	"strings"
+
+	"github.com/hashicorp/go-version"
+	"github.com/hashicorp/hcl/v2"
+	lsp "github.com/sourcegraph/go-lsp"
+	"github.com/sourcegraph/jsonrpc2"
+	"github.com/spf13/afero"
+	"github.com/terraform-linters/tflint/plugin"
+	"github.com/terraform-linters/tflint/tflint"
+)
+
+// loadConfig loads the config file and merges the CLI config.
+func loadConfig(fs afero.Afero, configPath string, cliConfig *tflint.Config) (*tflint.Config, error) {
+	cfg, err := tflint.LoadConfig(fs, configPath)
+	if err != nil {
+		return nil, err
+	}
+	if cliConfig.DisabledByDefault {
+		for _, rule := range cfg.Rules {
+			rule.Enabled = false
+		}
+	}
+	cfg.Merge(cliConfig)
+
+	return cfg, nil
+}
+
+// launchPlugins launches plugins enabled in the config and checks their compatibility.
+// Returns the SDK versions of the plugins, keyed by plugin names.
+// If the plugins are incompatible, they are killed before returning the error.
+func launchPlugins(cfg *tflint.Config, cliConfig *tflint.Config) (*plugin.Plugin, map[string]*version.Version, error) {
+	rulsetPlugin, err := plugin.Discovery(cfg)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	clientSDKVersions, err := checkPlugins(rulsetPlugin, cfg, cliConfig)
+	if err != nil {
+		rulsetPlugin.Clean()
+		return nil, nil, err
+	}
+	return rulsetPlugin, clientSDKVersions, nil
+}
+
+func checkPlugins(rulsetPlugin *plugin.Plugin, cfg *tflint.Config, cliConfig *tflint.Config) (map[string]*version.Version, error) {
+	rulesets := []tflint.RuleSet{}
+	clientSDKVersions := map[string]*version.Version{}
+	for name, ruleset := range rulsetPlugin.RuleSets {
+		constraints, err := ruleset.VersionConstraints()
+		if err != nil {
+			if plugin.IsVersionConstraintsUnimplemented(err) {
+				// VersionConstraints endpoint is available in tflint-plugin-sdk v0.14+.
+				return nil, fmt.Errorf(`Plugin "%s" SDK version is incompatible. Compatible versions: %s`, name, plugin.DefaultSDKVersionConstraints)
+			} else {
+				return nil, fmt.Errorf(`Failed to get TFLint version constraints to "%s" plugin; %w`, name, err)
+			}
+		}
+		if err := plugin.CheckTFLintVersionConstraints(name, constraints); err != nil {
+			return nil, err
+		}
+
+		sdkVersion, err := ruleset.SDKVersion()
+		if err != nil {
+			if plugin.IsSDKVersionUnimplemented(err) {
+				// SDKVersion endpoint is available in tflint-plugin-sdk v0.14+.
+				// Plugin is too old, treat as nil
+				sdkVersion = nil
+			} else {
+				return nil, fmt.Errorf(`Failed to get plugin "%s" SDK version; %w`, name, err)
+			}
+		}
+
+		// Check if plugin SDK version meets minimum requirements for the config type
+		if err := plugin.CheckSDKVersionSatisfiesConstraints(name, sdkVersion, cfg.IsJSONConfig()); err != nil {
+			return nil, err
+		}
+
+		clientSDKVersions[name] = sdkVersion
+
+		rulesets = append(rulesets, ruleset)
+	}
+	if err := cliConfig.ValidateRules(rulesets...); err != nil {
+		return nil, err
+	}
+
+	return clientSDKVersions, nil
+}
+
+// reloadConfig reloads the config files and relaunches plugins of all root modules.
+func (h *handler) reloadConfig(ctx context.Context, conn *jsonrpc2.Conn) error {
+	return h.reloadRoots(ctx, conn, h.sortedRoots())
+}
+
+// reloadRoots reloads the config files and relaunches plugins of the root modules, then inspects them again.
+// Config errors are published after all root modules are reloaded, since root modules can share a config file.
+func (h *handler) reloadRoots(ctx context.Context, conn *jsonrpc2.Conn, roots []*root) error {
+	previous := h.configDiagnostics()
+
+	var errs []error
+	reloaded := []*root{}
+	for _, r := range roots {
+		r.mu.Lock()
+		ok, err := r.reload(conn)
+		r.mu.Unlock()
+		if err != nil {
+			errs = append(errs, err)
+		}
+		if ok {
+			reloaded = append(reloaded, r)
+		}
+	}
+	if err := h.revalidateConfigDocuments(); err != nil {
+		errs = append(errs, err)
+	}
+	if err := h.publishConfigDiagnostics(conn, previous); err != nil {
+		return err
+	}
+
+	for _, r := range reloaded {
+		r.mu.Lock()
+		errs = append(errs, r.reinspect(ctx, conn))
+		r.mu.Unlock()
+	}
+	return errors.Join(errs...)
+}
+
+// reload reloads the config file and relaunches plugins. Returns whether the config is reloaded.
+// If the config is invalid, the errors are kept as config diagnostics,
+// and the previous config and plugins are kept.
+func (r *root) reload(conn *jsonrpc2.Conn) (bool, error) {
+	log.Printf("Reloading config and plugins of %s...", r.dir)
+
+	cfg, configFile, rulsetPlugin, clientSDKVersions, err := r.load(conn)
+	if err != nil {
+		if configFile == "" {
+			configFile = r.configFile
+		}
+		return false, r.setConfigError(configFile, err)
+	}
+
+	// Replace plugins in place, so that the caller of NewHandler can clean up the current plugins
+	r.plugin.Clean()
+	*r.plugin = *rulsetPlugin
+
+	r.config = cfg
+	r.configFile = configFile
+	r.clientSDKVersions = clientSDKVersions
+	r.pluginFix = nil
+	r.rulePlugins = nil
+	r.runners = nil
+	r.configDiags = map[string][]diagnostic{}
+
+	return true, nil
+}
+
+// setConfigError replaces the config diagnostics with the error.
+// HCL diagnostics are placed at their locations, and other errors are placed at the beginning of the config file.
+// If there is no config file to place diagnostics, the error is returned as is.
+func (r *root) setConfigError(configFile string, err error) error {
+	log.Printf("Failed to load config of %s: %s", r.dir, err)
+
+	diags := map[string][]diagnostic{}
+
+	var hclDiags hcl.Diagnostics
+	if errors.As(err, &hclDiags) {
+		for _, hclDiag := range hclDiags {
+			if hclDiag.Subject == nil {
+				continue
+			}
+			path := hclDiag.Subject.Filename
+			if !filepath.IsAbs(path) {
+				path = filepath.Join(r.dir, path)
+			}
+			diags[path] = append(diags[path], hclToLSPDiagnostic(hclDiag))
+		}
+	}
+
+	if len(diags) == 0 {
+		if configFile == "" {
+			return err
+		}
+		diags[configFile] = []diagnostic{
+			{
+				Diagnostic: lsp.Diagnostic{
+					Severity: lsp.Error,
+					Message:  err.Error(),
+				},
+			},
+		}
+	}
+
+	r.configDiags = diags
+	return nil
+}
+
+// configDiagnostics returns the errors of config files of all root modules, keyed by absolute paths.
+// Root modules sharing a config file have the same errors, so those of the first root module are used.
+// The errors of open config files are used instead of those of the saved files.
+func (h *handler) configDiagnostics() map[string][]diagnostic {
+	ret := map[string][]diagnostic{}
+	for _, r := range h.sortedRoots() {
+		for path, diags := range r.configDiags {
+			if _, exists := ret[path]; !exists {
+				ret[path] = diags
+			}
+		}
+	}
+	for path, diags := range h.documentConfigDiags {
+		ret[path] = diags
+	}
+	return ret
+}
+
+// publishConfigDiagnostics publishes the errors of config files.
+// Files where the previous errors were published are also notified in order to clear them.
+func (h *handler) publishConfigDiagnostics(conn *jsonrpc2.Conn, previous map[string][]diagnostic) error {
+	ret := map[string][]diagnostic{}
+	for path := range previous {
+		ret[path] = []diagnostic{}
+	}
+	for path, diags := range h.configDiagnostics() {
+		ret[path] = diags
+	}
+	if len(ret) == 0 {
+		return nil
+	}
+
+	if h.pullDiagnostics {
+		return h.refreshDiagnostics(context.Background(), conn)
+	}
+	return h.publishDiagnostics(context.Background(), conn, ret)
+}
+
+func hclToLSPDiagnostic(diag *hcl.Diagnostic) diagnostic {
+	severity := lsp.Error
+	if diag.Severity == hcl.DiagWarning {
+		severity = lsp.Warning
+	}
+	message := diag.Summary
+	if diag.Detail != "" {
+		message = fmt.Sprintf("%s; %s", diag.Summary, diag.Detail)
+	}
+
+	return diagnostic{
+		Diagnostic: lsp.Diagnostic{
+			Severity: severity,
+			Message:  message,
+			Range:    toLSPRange(*diag.Subject),
+		},
+	}
+}
+
+// registrationParams is the params of client/registerCapability.
+// go-lsp does not define dynamic registration.
+type registrationParams struct {
+	Registrations []registration `json:"registrations"`
+}
+
+type registration struct {
+	ID              string `json:"id"`
+	Method          string `json:"method"`
+	RegisterOptions any    `json:"registerOptions,omitempty"`
+}
+
+type didChangeWatchedFilesRegistrationOptions struct {
+	Watchers []fileSystemWatcher `json:"watchers"`
+}
+
+type fileSystemWatcher struct {
+	GlobPattern string `json:"globPattern"`
+}
+
+// registerWatchers asks the client to watch config files and plugins,
+// so that they are reloaded on changes via workspace/didChangeWatchedFiles.
+// Config files and plugin directories specific to root modules are watched in addition to the default ones.
+func (h *handler) registerWatchers(conn *jsonrpc2.Conn) error {
+	watchers := []fileSystemWatcher{
+		{GlobPattern: "**/.tflint.hcl"},
+		{GlobPattern: "**/.tflint.json"},
+		{GlobPattern: "**/" + plugin.LockFileName},
+		{GlobPattern: "**/.tflint.d/plugins/**"},
+	}
+	if err := requestRegistration(conn, "tflint-watched-files", watchers); err != nil {
+		return err
+	}
+
+	for _, r := range h.sortedRoots() {
+		if err := h.registerRootWatchers(conn, r); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// registerRootWatchers asks the client to watch the config file and the plugin directory of the root module.
+func (h *handler) registerRootWatchers(conn *jsonrpc2.Conn, r *root) error {
+	watchers := []fileSystemWatcher{}
+	if r.configFile != "" {
+		watchers = append(watchers, fileSystemWatcher{GlobPattern: filepath.ToSlash(r.configFile)})
+	}
+	if r.config != nil {
+		if pluginDir, err := plugin.PluginDir(r.config); err == nil {
+			watchers = append(watchers, fileSystemWatcher{GlobPattern: filepath.ToSlash(pluginDir) + "/**"})
+		}
+	}
+
+	if len(watchers) == 0 {
+		return nil
+	}
+	return requestRegistration(conn, "tflint-watched-files:"+r.dir, watchers)
+}
+
+func requestRegistration(conn *jsonrpc2.Conn, id string, watchers []fileSystemWatcher) error {
+	log.Printf("Request client/registerCapability with %#v", watchers)
+	// Do not wait for the response, so that handling messages is not blocked by the client
+	_, err := conn.DispatchCall(context.Background(), "client/registerCapability", registrationParams{
+		Registrations: []registration{
+			{
+				ID:              id,
+				Method:          "workspace/didChangeWatchedFiles",
+				RegisterOptions: didChangeWatchedFilesRegistrationOptions{Watchers: watchers},
+			},
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("Failed to request client/registerCapability: %s", err)
+	}
+	return nil
+}
+
+// isConfigOrPlugin returns whether the path is a config file, a plugin lock file, or in a plugin directory of the root module.
+// Changes to these files require reloading the config and plugins.
+func (r *root) isConfigOrPlugin(path string) bool {
+	if path == r.configFile {
+		return true
+	}
+	switch filepath.Base(path) {
+	case ".tflint.hcl", ".tflint.json", plugin.LockFileName:
+		return true
+	}
+	if strings.Contains(filepath.ToSlash(path), "/.tflint.d/plugins/") {
+		return true
+	}
+	if r.config == nil {
+		return false
+	}
+
+	pluginDir, err := plugin.PluginDir(r.config)
+	if err != nil {
+		return false
+	}
+	_, ok := relPath(pluginDir, path)
+	return ok
+}
@@ -0,0 +1,240 @@
+package langserver
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/agext/levenshtein"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclparse"
+	lsp "github.com/sourcegraph/go-lsp"
+	"github.com/sourcegraph/jsonrpc2"
+	"github.com/spf13/afero"
+	"github.com/terraform-linters/tflint/tflint"
+)
+
+// isConfigDocument returns whether the path is a config file.
+// The default config files and the config files loaded by root modules are regarded as config files.
+func (h *handler) isConfigDocument(path string) bool {
+	switch filepath.Base(path) {
+	case ".tflint.hcl", ".tflint.json":
+		return true
+	}
+	return h.configRoot(path) != nil
+}
+
+// configRoot returns the first root module that loads the config file.
+// Returns nil if no root module loads it.
+func (h *handler) configRoot(path string) *root {
+	for _, r := range h.sortedRoots() {
+		if r.configFile == path {
+			return r
+		}
+	}
+	return nil
+}
+
+// validateConfigDocument validates the open config file and publishes the errors.
+// Unlike the errors of the saved config file, the errors are updated on every edit.
+func (h *handler) validateConfigDocument(conn *jsonrpc2.Conn, path string) error {
+	previous := h.configDiagnostics()
+
+	diags, err := validateConfig(path, h.documents[path], h.configRoot(path))
+	if err != nil {
+		return err
+	}
+	h.documentConfigDiags[path] = diags
+
+	return h.publishConfigDiagnostics(conn, previous)
+}
+
+// revalidateConfigDocuments validates the open config files again without publishing the errors.
+// This is used after plugins are reloaded, since rule names and plugin configs depend on them.
+func (h *handler) revalidateConfigDocuments() error {
+	for path := range h.documentConfigDiags {
+		diags, err := validateConfig(path, h.documents[path], h.configRoot(path))
+		if err != nil {
+			return err
+		}
+		h.documentConfigDiags[path] = diags
+	}
+	return nil
+}
+
+// closeConfigDocument discards the errors of the closed config file.
+// The errors of the saved config file are published instead, if any.
+func (h *handler) closeConfigDocument(conn *jsonrpc2.Conn, path string) error {
+	if _, exists := h.documentConfigDiags[path]; !exists {
+		return nil
+	}
+	previous := h.configDiagnostics()
+	delete(h.documentConfigDiags, path)
+
+	return h.publishConfigDiagnostics(conn, previous)
+}
+
+// validateConfig returns the errors of the config file with the same logic as the CLI.
+// The config file is loaded by tflint.LoadConfig, and then plugin configs and rule names are validated
+// against the plugins of the root module that loads it, if any.
+func validateConfig(path string, src []byte, r *root) ([]diagnostic, error) {
+	fs := afero.NewMemMapFs()
+	if err := afero.WriteFile(fs, path, src, os.ModePerm); err != nil {
+		return nil, fmt.Errorf("Failed to write %s: %s", path, err)
+	}
+	cfg, err := tflint.LoadConfig(afero.Afero{Fs: fs}, path)
+	if err != nil {
+		return configErrorDiagnostics(path, src, err), nil
+	}
+	if r == nil {
+		return []diagnostic{}, nil
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.validatePluginConfig(cfg, path, src)
+}
+
+// validatePluginConfig validates plugin configs against the schemas of plugins, and rule names against rules of plugins.
+// Rule names are not validated if the config enables plugins that are not running,
+// since the rules of these plugins are not known until the config is saved and reloaded.
+func (r *root) validatePluginConfig(cfg *tflint.Config, path string, src []byte) ([]diagnostic, error) {
+	diags := []diagnostic{}
+
+	names := make([]string, 0, len(r.plugin.RuleSets))
+	rulesets := make([]tflint.RuleSet, 0, len(r.plugin.RuleSets))
+	for name := range r.plugin.RuleSets {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		ruleset := r.plugin.RuleSets[name]
+		rulesets = append(rulesets, ruleset)
+
+		pluginConfig, exists := cfg.Plugins[name]
+		if !exists {
+			continue
+		}
+		schema, err := ruleset.ConfigSchema()
+		if err != nil {
+			return nil, fmt.Errorf(`Failed to fetch config schema from "%s" plugin: %w`, name, err)
+		}
+		if _, hclDiags := pluginConfig.Content(schema); hclDiags.HasErrors() {
+			diags = append(diags, configErrorDiagnostics(path, src, hclDiags)...)
+		}
+	}
+
+	for name, pluginConfig := range cfg.Plugins {
+		if _, running := r.plugin.RuleSets[name]; pluginConfig.Enabled && !running {
+			return diags, nil
+		}
+	}
+
+	rulePlugins, err := r.loadRulePlugins()
+	if err != nil {
+		return nil, err
+	}
+	candidates := make([]string, 0, len(rulePlugins))
+	for rule := range rulePlugins {
+		candidates = append(candidates, rule)
+	}
+
+	unknown := false
+	for _, block := range configBlocks(path, src) {
+		if block.Type != "rule" {
+			continue
+		}
+		if _, exists := rulePlugins[block.Labels[0]]; exists {
+			continue
+		}
+		unknown = true
+
+		message := fmt.Sprintf("Rule not found: %s", block.Labels[0])
+		if suggestion := nameSuggestion(block.Labels[0], candidates); suggestion != "" {
+			message += fmt.Sprintf(`. Did you mean "%s"?`, suggestion)
+		}
+		diags = append(diags, diagnostic{
+			Diagnostic: lsp.Diagnostic{
+				Severity: lsp.Error,
+				Message:  message,
+				Range:    toLSPRange(block.LabelRanges[0]),
+			},
+		})
+	}
+
+	// Other errors, such as duplicate rules among plugins, are placed at the beginning of the config file
+	if !unknown {
+		if err := cfg.ValidateRules(rulesets...); err != nil {
+			diags = append(diags, diagnostic{Diagnostic: lsp.Diagnostic{Severity: lsp.Error, Message: err.Error()}})
+		}
+	}
+
+	return diags, nil
+}
+
+// configErrorDiagnostics converts the error of loading the config file to diagnostics.
+// HCL diagnostics are placed at their locations, and errors of plugin blocks are placed at their labels.
+// Other errors are placed at the beginning of the config file.
+func configErrorDiagnostics(path string, src []byte, err error) []diagnostic {
+	var hclDiags hcl.Diagnostics
+	if errors.As(err, &hclDiags) {
+		diags := []diagnostic{}
+		for _, hclDiag := range hclDiags {
+			if hclDiag.Subject == nil || hclDiag.Subject.Filename != path {
+				diags = append(diags, diagnostic{Diagnostic: lsp.Diagnostic{Severity: lsp.Error, Message: hclDiag.Error()}})
+				continue
+			}
+			diags = append(diags, hclToLSPDiagnostic(hclDiag))
+		}
+		return diags
+	}
+
+	diag := diagnostic{Diagnostic: lsp.Diagnostic{Severity: lsp.Error, Message: err.Error()}}
+	for _, block := range configBlocks(path, src) {
+		if block.Type == "plugin" && strings.HasPrefix(err.Error(), fmt.Sprintf(`plugin "%s":`, block.Labels[0])) {
+			diag.Range = toLSPRange(block.LabelRanges[0])
+			break
+		}
+	}
+	return []diagnostic{diag}
+}
+
+// configBlocks returns the "rule" and "plugin" blocks in the config file.
+// Blocks are extracted as much as possible even if the config file is invalid.
+func configBlocks(path string, src []byte) hcl.Blocks {
+	parser := hclparse.NewParser()
+	var file *hcl.File
+	if filepath.Ext(path) == ".json" {
+		file, _ = parser.ParseJSON(src, path)
+	} else {
+		file, _ = parser.ParseHCL(src, path)
+	}
+	if file == nil || file.Body == nil {
+		return hcl.Blocks{}
+	}
+
+	content, _, _ := file.Body.PartialContent(&hcl.BodySchema{
+		Blocks: []hcl.BlockHeaderSchema{
+			{Type: "rule", LabelNames: []string{"name"}},
+			{Type: "plugin", LabelNames: []string{"name"}},
+		},
+	})
+	return content.Blocks
+}
+
+// nameSuggestion returns the candidate closest to the given name, if any is close enough.
+func nameSuggestion(given string, candidates []string) string {
+	sort.Strings(candidates)
+
+	ret, min := "", 3
+	for _, candidate := range candidates {
+		if dist := levenshtein.Distance(given, candidate, nil); dist < min {
+			ret, min = candidate, dist
+		}
+	}
+	return ret
+}
@@ -0,0 +1,35 @@
+package langserver
+
+import (
+	"testing"
+
+	"github.com/terraform-linters/tflint/tflint"
+)
+
+func Test_isConfigOrPlugin(t *testing.T) {
+	config := tflint.EmptyConfig()
+	config.PluginDir = "/plugins"
+	r := &root{config: config, configFile: "/work/custom.hcl"}
+
+	tests := []struct {
+		path string
+		want bool
+	}{
+		{path: "/work/custom.hcl", want: true},
+		{path: "/work/.tflint.hcl", want: true},
+		{path: "/work/.tflint.json", want: true},
+		{path: "/work/.tflint.lock.hcl", want: true},
+		{path: "/work/.tflint.d/plugins/tflint-ruleset-aws", want: true},
+		{path: "/plugins/github.com/terraform-linters/tflint-ruleset-aws/0.1.0/tflint-ruleset-aws", want: true},
+		{path: "/work/main.tf", want: false},
+		{path: "/plugins-backup/tflint-ruleset-aws", want: false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.path, func(t *testing.T) {
+			if got := r.isConfigOrPlugin(test.path); got != test.want {
+				t.Errorf("expected %t, but got %t", test.want, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,83 @@
+package langserver
+
+import (
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/spf13/afero"
+)
+
+// dirFs is a filesystem that resolves relative paths against the directory.
+// Unlike afero.BasePathFs, absolute paths are passed through as they are.
+// This allows loading a root module without changing the current directory,
+// since the loader and config files refer to paths relative to the root module.
+type dirFs struct {
+	afero.Fs
+	dir string
+}
+
+var _ afero.Fs = (*dirFs)(nil)
+
+func newDirFs(base afero.Fs, dir string) afero.Fs {
+	return &dirFs{Fs: base, dir: dir}
+}
+
+func (fs *dirFs) path(name string) string {
+	if filepath.IsAbs(name) {
+		return name
+	}
+	return filepath.Join(fs.dir, name)
+}
+
+func (fs *dirFs) Create(name string) (afero.File, error) {
+	return fs.Fs.Create(fs.path(name))
+}
+
+func (fs *dirFs) Mkdir(name string, perm os.FileMode) error {
+	return fs.Fs.Mkdir(fs.path(name), perm)
+}
+
+func (fs *dirFs) MkdirAll(path string, perm os.FileMode) error {
+	return fs.Fs.MkdirAll(fs.path(path), perm)
+}
+
+func (fs *dirFs) Open(name string) (afero.File, error) {
+	return fs.Fs.Open(fs.path(name))
+}
+
+func (fs *dirFs) OpenFile(name string, flag int, perm os.FileMode) (afero.File, error) {
+	return fs.Fs.OpenFile(fs.path(name), flag, perm)
+}
+
+func (fs *dirFs) Remove(name string) error {
+	return fs.Fs.Remove(fs.path(name))
+}
+
+func (fs *dirFs) RemoveAll(path string) error {
+	return fs.Fs.RemoveAll(fs.path(path))
+}
+
+func (fs *dirFs) Rename(oldname, newname string) error {
+	return fs.Fs.Rename(fs.path(oldname), fs.path(newname))
+}
+
+func (fs *dirFs) Stat(name string) (os.FileInfo, error) {
+	return fs.Fs.Stat(fs.path(name))
+}
+
+func (fs *dirFs) Name() string {
+	return "dirFs"
+}
+
+func (fs *dirFs) Chmod(name string, mode os.FileMode) error {
+	return fs.Fs.Chmod(fs.path(name), mode)
+}
+
+func (fs *dirFs) Chown(name string, uid, gid int) error {
+	return fs.Fs.Chown(fs.path(name), uid, gid)
+}
+
+func (fs *dirFs) Chtimes(name string, atime time.Time, mtime time.Time) error {
+	return fs.Fs.Chtimes(fs.path(name), atime, mtime)
+}
@@ -0,0 +1,227 @@
+package langserver
+
+import (
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/hashicorp/hcl/v2/hclwrite"
+	lsp "github.com/sourcegraph/go-lsp"
+	"github.com/terraform-linters/tflint-plugin-sdk/terraform/lang/marks"
+	"github.com/terraform-linters/tflint/tflint"
+	"github.com/zclconf/go-cty/cty"
+)
+
+// runnerFor returns the runner of the last inspection that loaded the file, and the path relative to the root directory.
+// The root module takes precedence over module calls. If a module is called multiple times, the first call is used.
+// Returns nil if the file was not loaded by the last inspection.
+func (r *root) runnerFor(path string) (*tflint.Runner, string) {
+	rel, ok := relPath(r.dir, path)
+	if !ok || len(r.runners) == 0 {
+		return nil, ""
+	}
+
+	// The runner of the root module is the last one
+	runners := append([]*tflint.Runner{r.runners[len(r.runners)-1]}, r.runners[:len(r.runners)-1]...)
+	for _, runner := range runners {
+		if runner.File(rel) != nil {
+			return runner, rel
+		}
+	}
+	return nil, ""
+}
+
+// exprAt returns the innermost expression at the position in the file.
+// Literals and object keys are not returned because their values are obvious.
+// Returns nil if there is no expression at the position, or the file is not in the native syntax.
+func exprAt(file *hcl.File, pos lsp.Position) hclsyntax.Expression {
+	body, ok := file.Body.(*hclsyntax.Body)
+	if !ok {
+		return nil
+	}
+	cursor := lsp.Range{Start: pos, End: pos}
+
+	var ret hclsyntax.Expression
+	inKey := false
+	hclsyntax.VisitAll(body, func(node hclsyntax.Node) hcl.Diagnostics {
+		expr, ok := node.(hclsyntax.Expression)
+		if !ok || !rangesOverlap(toLSPRange(expr.Range()), cursor) {
+			return nil
+		}
+
+		switch e := expr.(type) {
+		case *hclsyntax.ObjectConsKeyExpr:
+			// Bare keys are not references, even though they are parsed as traversals
+			if !e.ForceNonLiteral && hcl.ExprAsKeyword(e.Wrapped) != "" {
+				inKey = true
+			}
+			return nil
+		case *hclsyntax.LiteralValueExpr:
+			return nil
+		case *hclsyntax.TemplateExpr:
+			if e.IsStringLiteral() {
+				return nil
+			}
+		case *hclsyntax.TemplateWrapExpr:
+			// "${var.foo}" is the same as the wrapped expression
+			return nil
+		}
+
+		if ret == nil || rangeSize(expr.Range()) < rangeSize(ret.Range()) {
+			ret = expr
+		}
+		return nil
+	})
+	if inKey {
+		return nil
+	}
+	return ret
+}
+
+func rangeSize(rng hcl.Range) int {
+	return rng.End.Byte - rng.Start.Byte
+}
+
+// valueDocument returns a Markdown document describing the value of the expression evaluated statically.
+// Returns an empty string if the expression cannot be evaluated, e.g. references to "each" or symbols of "for" expressions.
+func valueDocument(runner *tflint.Runner, expr hclsyntax.Expression, src []byte) string {
+	val, diags := runner.Ctx.EvaluateExpr(expr, cty.DynamicPseudoType)
+	if diags.HasErrors() {
+		return ""
+	}
+
+	var b strings.Builder
+	source := string(expr.Range().SliceBytes(src))
+	if !strings.Contains(source, "\n") {
+		fmt.Fprintf(&b, "**`%s`**\n\n", source)
+	}
+	fmt.Fprintf(&b, "```hcl\n%s\n```\n\n", formatValue(val, ""))
+
+	unmarked, pvm := val.UnmarkDeepWithPaths()
+	fmt.Fprintf(&b, "- Type: %s\n", unmarked.Type().FriendlyName())
+	if !unmarked.IsKnown() {
+		fmt.Fprint(&b, "- Known: no\n")
+	} else if !unmarked.IsWhollyKnown() {
+		fmt.Fprint(&b, "- Known: partially\n")
+	}
+	if hasMark(pvm, marks.Sensitive) {
+		fmt.Fprint(&b, "- Sensitive: yes\n")
+	}
+	if hasMark(pvm, marks.Ephemeral) {
+		fmt.Fprint(&b, "- Ephemeral: yes\n")
+	}
+	if runner.TFConfig != nil && !runner.TFConfig.Path.IsRoot() {
+		fmt.Fprintf(&b, "- Module: %s\n", runner.TFConfig.Path)
+	}
+
+	return strings.TrimSuffix(b.String(), "\n")
+}
+
+func hasMark(pvm []cty.PathValueMarks, mark any) bool {
+	for _, pv := range pvm {
+		if _, exists := pv.Marks[mark]; exists {
+			return true
+		}
+	}
+	return false
+}
+
+// formatValue returns the value in the native syntax, with the same placeholders as Terraform's plan output
+// for unknown and sensitive values. Nested values are indented with the given indent.
+func formatValue(val cty.Value, indent string) string {
+	val, valMarks := val.Unmark()
+	if _, sensitive := valMarks[marks.Sensitive]; sensitive {
+		return "(sensitive value)"
+	}
+	if !val.IsKnown() {
+		return "(known after apply)"
+	}
+	if val.IsNull() {
+		return "null"
+	}
+
+	ty := val.Type()
+	switch {
+	case ty == cty.String:
+		return strings.TrimSpace(string(hclwrite.TokensForValue(val).Bytes()))
+	case ty == cty.Number:
+		return val.AsBigFloat().Text('f', -1)
+	case ty == cty.Bool:
+		if val.True() {
+			return "true"
+		}
+		return "false"
+	case ty.IsListType() || ty.IsSetType() || ty.IsTupleType():
+		if val.LengthInt() == 0 {
+			return "[]"
+		}
+		var b strings.Builder
+		b.WriteString("[\n")
+		for it := val.ElementIterator(); it.Next(); {
+			_, elem := it.Element()
+			fmt.Fprintf(&b, "%s  %s,\n", indent, formatValue(elem, indent+"  "))
+		}
+		fmt.Fprintf(&b, "%s]", indent)
+		return b.String()
+	case ty.IsMapType() || ty.IsObjectType():
+		if val.LengthInt() == 0 {
+			return "{}"
+		}
+		var b strings.Builder
+		b.WriteString("{\n")
+		for it := val.ElementIterator(); it.Next(); {
+			key, elem := it.Element()
+			fmt.Fprintf(&b, "%s  %s = %s\n", indent, formatKey(key.AsString()), formatValue(elem, indent+"  "))
+		}
+		fmt.Fprintf(&b, "%s}", indent)
+		return b.String()
+	default:
+		// Capsule types cannot be written in the native syntax
+		return ty.FriendlyName()
+	}
+}
+
+// formatKey returns the key as is if it is a valid identifier, otherwise quoted.
+func formatKey(key string) string {
+	if hclsyntax.ValidIdentifier(key) {
+		return key
+	}
+	return strings.TrimSpace(string(hclwrite.TokensForValue(cty.StringVal(key)).Bytes()))
+}
+
+// instancesLabel returns the label describing the number of instances expanded by the "count" or "for_each" value.
+// Returns false if the value is null, since the block is not expanded.
+func instancesLabel(val cty.Value) (string, bool) {
+	val, valMarks := val.Unmark()
+	if _, sensitive := valMarks[marks.Sensitive]; sensitive {
+		return "(sensitive value)", true
+	}
+	if !val.IsKnown() {
+		return "(known after apply)", true
+	}
+	if val.IsNull() {
+		return "", false
+	}
+
+	var n int
+	ty := val.Type()
+	switch {
+	case ty == cty.Number:
+		count, accuracy := val.AsBigFloat().Int64()
+		if accuracy != big.Exact {
+			return "", false
+		}
+		n = int(count)
+	case ty.IsCollectionType() || ty.IsObjectType() || ty.IsTupleType():
+		n = val.LengthInt()
+	default:
+		return "", false
+	}
+
+	if n == 1 {
+		return "1 instance", true
+	}
+	return fmt.Sprintf("%d instances", n), true
+}
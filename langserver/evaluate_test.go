@@ -0,0 +1,193 @@
+package langserver
+
+import (
+	"testing"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	lsp "github.com/sourcegraph/go-lsp"
+	"github.com/terraform-linters/tflint-plugin-sdk/terraform/lang/marks"
+	"github.com/zclconf/go-cty/cty"
+)
+
+func Test_exprAt(t *testing.T) {
+	src := `resource "aws_instance" "foo" {
+  count         = length(var.zones)
+  instance_type = "t2.micro"
+  tags          = { Name = "${var.name}-foo" }
+}`
+	file, diags := hclsyntax.ParseConfig([]byte(src), "main.tf", hcl.InitialPos)
+	if diags.HasErrors() {
+		t.Fatal(diags)
+	}
+
+	tests := []struct {
+		name string
+		pos  lsp.Position
+		want string
+	}{
+		{
+			name: "variable in function call",
+			pos:  lsp.Position{Line: 1, Character: 28},
+			want: "var.zones",
+		},
+		{
+			name: "function name",
+			pos:  lsp.Position{Line: 1, Character: 20},
+			want: "length(var.zones)",
+		},
+		{
+			name: "literal",
+			pos:  lsp.Position{Line: 2, Character: 22},
+			want: "",
+		},
+		{
+			name: "object key",
+			pos:  lsp.Position{Line: 3, Character: 20},
+			want: "",
+		},
+		{
+			name: "interpolation",
+			pos:  lsp.Position{Line: 3, Character: 33},
+			want: "var.name",
+		},
+		{
+			name: "literal part of template",
+			pos:  lsp.Position{Line: 3, Character: 42},
+			want: `"${var.name}-foo"`,
+		},
+		{
+			name: "block header",
+			pos:  lsp.Position{Line: 0, Character: 3},
+			want: "",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got := ""
+			if expr := exprAt(file, test.pos); expr != nil {
+				got = string(expr.Range().SliceBytes(file.Bytes))
+			}
+			if got != test.want {
+				t.Errorf("expected %q, but got %q", test.want, got)
+			}
+		})
+	}
+}
+
+func Test_formatValue(t *testing.T) {
+	tests := []struct {
+		name string
+		val  cty.Value
+		want string
+	}{
+		{
+			name: "string",
+			val:  cty.StringVal("t2.micro"),
+			want: `"t2.micro"`,
+		},
+		{
+			name: "escaped string",
+			val:  cty.StringVal("${foo}\n"),
+			want: `"$${foo}\n"`,
+		},
+		{
+			name: "number",
+			val:  cty.NumberFloatVal(1.5),
+			want: "1.5",
+		},
+		{
+			name: "null",
+			val:  cty.NullVal(cty.String),
+			want: "null",
+		},
+		{
+			name: "unknown",
+			val:  cty.UnknownVal(cty.String),
+			want: "(known after apply)",
+		},
+		{
+			name: "sensitive",
+			val:  cty.StringVal("secret").Mark(marks.Sensitive),
+			want: "(sensitive value)",
+		},
+		{
+			name: "empty list",
+			val:  cty.ListValEmpty(cty.String),
+			want: "[]",
+		},
+		{
+			name: "nested collections",
+			val: cty.ObjectVal(map[string]cty.Value{
+				"zones":    cty.ListVal([]cty.Value{cty.StringVal("a"), cty.UnknownVal(cty.String)}),
+				"password": cty.StringVal("secret").Mark(marks.Sensitive),
+				"app name": cty.True,
+			}),
+			want: `{
+  "app name" = true
+  password = (sensitive value)
+  zones = [
+    "a",
+    (known after apply),
+  ]
+}`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := formatValue(test.val, ""); got != test.want {
+				t.Errorf("expected:\n%s\n\nbut got:\n%s", test.want, got)
+			}
+		})
+	}
+}
+
+func Test_instancesLabel(t *testing.T) {
+	tests := []struct {
+		name   string
+		val    cty.Value
+		want   string
+		wantOk bool
+	}{
+		{
+			name:   "count",
+			val:    cty.NumberIntVal(3),
+			want:   "3 instances",
+			wantOk: true,
+		},
+		{
+			name:   "single instance",
+			val:    cty.NumberIntVal(1),
+			want:   "1 instance",
+			wantOk: true,
+		},
+		{
+			name:   "for_each set",
+			val:    cty.SetVal([]cty.Value{cty.StringVal("a"), cty.StringVal("b")}),
+			want:   "2 instances",
+			wantOk: true,
+		},
+		{
+			name:   "unknown",
+			val:    cty.UnknownVal(cty.Number),
+			want:   "(known after apply)",
+			wantOk: true,
+		},
+		{
+			name:   "null",
+			val:    cty.NullVal(cty.Number),
+			wantOk: false,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, ok := instancesLabel(test.val)
+			if got != test.want || ok != test.wantOk {
+				t.Errorf("expected (%q, %t), but got (%q, %t)", test.want, test.wantOk, got, ok)
+			}
+		})
+	}
+}
@@ -9,99 +9,95 @@ import (
 	"os"
 	"path/filepath"
 	"runtime"
+	"sort"
 	"strings"
+	"sync"
+	"time"
 
-	"github.com/hashicorp/go-version"
 	"github.com/hashicorp/hcl/v2"
 	lsp "github.com/sourcegraph/go-lsp"
 	"github.com/sourcegraph/jsonrpc2"
-	"github.com/spf13/afero"
-	"github.com/terraform-linters/tflint-plugin-sdk/hclext"
 	sdk "github.com/terraform-linters/tflint-plugin-sdk/tflint"
 	"github.com/terraform-linters/tflint/plugin"
-	"github.com/terraform-linters/tflint/terraform"
 	"github.com/terraform-linters/tflint/tflint"
 )
 
 // NewHandler returns a new JSON-RPC handler
 func NewHandler(configPath string, cliConfig *tflint.Config) (jsonrpc2.Handler, *plugin.Plugin, error) {
-	cfg, err := tflint.LoadConfig(afero.Afero{Fs: afero.NewOsFs()}, configPath)
-	if err != nil {
-		return nil, nil, err
-	}
-	if cliConfig.DisabledByDefault {
-		for _, rule := range cfg.Rules {
-			rule.Enabled = false
+	// The config file passed by --config is resolved relative to the current directory,
+	// while the root modules are loaded relative to their own directories.
+	if configPath != "" {
+		var err error
+		configPath, err = filepath.Abs(configPath)
+		if err != nil {
+			return nil, nil, err
 		}
 	}
-	cfg.Merge(cliConfig)
-
-	rulsetPlugin, err := plugin.Discovery(cfg)
+	dir, err := os.Getwd()
 	if err != nil {
-		return nil, nil, err
+		return nil, nil, fmt.Errorf("Failed to get the current directory: %w", err)
 	}
 
-	rulesets := []tflint.RuleSet{}
-	clientSDKVersions := map[string]*version.Version{}
-	for name, ruleset := range rulsetPlugin.RuleSets {
-		constraints, err := ruleset.VersionConstraints()
-		if err != nil {
-			if plugin.IsVersionConstraintsUnimplemented(err) {
-				// VersionConstraints endpoint is available in tflint-plugin-sdk v0.14+.
-				return nil, nil, fmt.Errorf(`Plugin "%s" SDK version is incompatible. Compatible versions: %s`, name, plugin.DefaultSDKVersionConstraints)
-			} else {
-				return nil, nil, fmt.Errorf(`Failed to get TFLint version constraints to "%s" plugin; %w`, name, err)
-			}
-		}
-		if err := plugin.CheckTFLintVersionConstraints(name, constraints); err != nil {
-			return nil, nil, err
-		}
-
-		sdkVersion, err := ruleset.SDKVersion()
-		if err != nil {
-			if plugin.IsSDKVersionUnimplemented(err) {
-				// SDKVersion endpoint is available in tflint-plugin-sdk v0.14+.
-				// Plugin is too old, treat as nil
-				sdkVersion = nil
-			} else {
-				return nil, nil, fmt.Errorf(`Failed to get plugin "%s" SDK version; %w`, name, err)
-			}
-		}
-
-		// Check if plugin SDK version meets minimum requirements for the config type
-		if err := plugin.CheckSDKVersionSatisfiesConstraints(name, sdkVersion, cfg.IsJSONConfig()); err != nil {
-			return nil, nil, err
-		}
-
-		clientSDKVersions[name] = sdkVersion
+	h := &handler{
+		configPath: configPath,
+		cliConfig:  cliConfig,
+		documents:  map[string][]byte{},
+		roots:      map[string]*root{},
+		debounce:   inspectionDelay,
 
-		rulesets = append(rulesets, ruleset)
+		documentConfigDiags: map[string][]diagnostic{},
+		progressCancels:     map[string]func(){},
 	}
-	if err := cliConfig.ValidateRules(rulesets...); err != nil {
+	// The current directory is loaded eagerly, so that the server fails to start if the config is invalid
+	r, err := h.newRoot(nil, dir)
+	if err != nil {
 		return nil, nil, err
 	}
+	h.roots[dir] = r
 
-	return jsonrpc2.HandlerWithError((&handler{
-		configPath:        configPath,
-		cliConfig:         cliConfig,
-		config:            cfg,
-		fs:                afero.NewCopyOnWriteFs(afero.NewOsFs(), afero.NewMemMapFs()),
-		plugin:            rulsetPlugin,
-		clientSDKVersions: clientSDKVersions,
-		diagsPaths:        []string{},
-	}).handle), rulsetPlugin, nil
+	return newQueue(jsonrpc2.HandlerWithError(h.handle), h.cancelProgress), r.plugin, nil
 }
 
+// inspectionDelay is the delay before inspecting after an edit.
+// Edits made within this period are inspected together.
+const inspectionDelay = 300 * time.Millisecond
+
 type handler struct {
-	configPath        string
-	cliConfig         *tflint.Config
-	config            *tflint.Config
-	fs                afero.Fs
-	rootDir           string
-	plugin            *plugin.Plugin
-	clientSDKVersions map[string]*version.Version
-	shutdown          bool
-	diagsPaths        []string
+	configPath string
+	cliConfig  *tflint.Config
+	// documents are the contents of open documents, keyed by absolute paths.
+	// They shadow the files on disk until the documents are closed.
+	documents map[string][]byte
+	// documentConfigDiags are the errors of open config files, keyed by absolute paths.
+	// They are validated on every edit, and take precedence over the errors of the saved config files.
+	documentConfigDiags map[string][]diagnostic
+	// folders are the workspace folders opened by the client.
+	folders []string
+	// roots are the root modules, keyed by their directories.
+	// A root module is loaded when a document in the directory is opened.
+	roots       map[string]*root
+	initialized bool
+	shutdown    bool
+	// pullDiagnostics is whether the client requests diagnostics instead of receiving them.
+	pullDiagnostics bool
+	// refreshSupport is whether the client supports workspace/diagnostic/refresh.
+	refreshSupport bool
+	// watchSupport is whether the client supports registering file watchers dynamically.
+	watchSupport bool
+	// progressSupport is whether the client supports progress reporting via window/workDoneProgress.
+	progressSupport bool
+
+	// mu serializes requests, since they share the open documents and the roots.
+	// Debounced inspections only lock the root they inspect.
+	mu sync.Mutex
+	// debounce is the delay before inspecting after an edit.
+	debounce time.Duration
+
+	// progressMu guards progressSeq and progressCancels, which are called when the client cancels progress.
+	// They are called without holding mu, while another request is being handled.
+	progressMu      sync.Mutex
+	progressSeq     int
+	progressCancels map[string]func()
 }
 
 func (h *handler) handle(ctx context.Context, conn *jsonrpc2.Conn, req *jsonrpc2.Request) (result any, err error) {
@@ -119,6 +115,19 @@ func (h *handler) handle(ctx context.Context, conn *jsonrpc2.Conn, req *jsonrpc2
 		log.Printf(`Received %s`, req.Method)
 	}
 
+	// Cancelled requests are replied with the error code defined by LSP, instead of partial results
+	if !req.Notif && ctx.Err() != nil {
+		return nil, requestCancelled()
+	}
+	defer func() {
+		if !req.Notif && ctx.Err() != nil {
+			result, err = nil, requestCancelled()
+		}
+	}()
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
 	if h.shutdown && req.Method != "exit" {
 		return nil, &jsonrpc2.Error{
 			Code:    jsonrpc2.CodeInvalidRequest,
@@ -128,22 +137,57 @@ func (h *handler) handle(ctx context.Context, conn *jsonrpc2.Conn, req *jsonrpc2
 
 	switch req.Method {
 	case "initialize":
-		return initialize(ctx, conn, req)
+		return h.initialize(ctx, conn, req)
 	case "initialized":
+		h.initialized = true
+		if h.watchSupport {
+			return nil, h.registerWatchers(conn)
+		}
 		return nil, nil
 	case "shutdown":
+		// Publish the results of the last edit before shutting down
+		for _, r := range h.sortedRoots() {
+			r.mu.Lock()
+			if err := r.flushInspection(ctx, conn); err != nil {
+				log.Printf("Failed to inspect: %s", err)
+			}
+			r.mu.Unlock()
+		}
 		h.shutdown = true
 		return nil, nil
 	case "exit":
+		for _, r := range h.roots {
+			r.mu.Lock()
+			r.close()
+			r.mu.Unlock()
+		}
 		return nil, conn.Close()
 	case "textDocument/didOpen":
 		return h.textDocumentDidOpen(ctx, conn, req)
 	case "textDocument/didClose":
-		return nil, nil
+		return h.textDocumentDidClose(ctx, conn, req)
 	case "textDocument/didChange":
 		return h.textDocumentDidChange(ctx, conn, req)
+	case "textDocument/didSave":
+		return h.textDocumentDidSave(ctx, conn, req)
+	case "textDocument/diagnostic":
+		return h.textDocumentDiagnostic(ctx, conn, req)
+	case "workspace/diagnostic":
+		return h.workspaceDiagnostic(ctx, conn, req)
+	case "textDocument/codeAction":
+		return h.textDocumentCodeAction(ctx, conn, req)
+	case "textDocument/completion":
+		return h.textDocumentCompletion(ctx, conn, req)
+	case "textDocument/hover":
+		return h.textDocumentHover(ctx, conn, req)
+	case "textDocument/inlayHint":
+		return h.textDocumentInlayHint(ctx, conn, req)
+	case "workspace/didChangeConfiguration":
+		return nil, h.reloadConfig(ctx, conn)
 	case "workspace/didChangeWatchedFiles":
 		return h.workspaceDidChangeWatchedFiles(ctx, conn, req)
+	case "workspace/didChangeWorkspaceFolders":
+		return h.workspaceDidChangeWorkspaceFolders(ctx, conn, req)
 	}
 
 	return nil, &jsonrpc2.Error{
@@ -152,90 +196,175 @@ func (h *handler) handle(ctx context.Context, conn *jsonrpc2.Conn, req *jsonrpc2
 	}
 }
 
-func (h *handler) chdir(dir string) error {
-	if h.rootDir != dir {
-		log.Printf("Changing directory: %s", dir)
-		if err := os.Chdir(dir); err != nil {
-			return fmt.Errorf("Failed to chdir to %s: %s", dir, err)
-		}
-		h.rootDir = dir
+func requestCancelled() *jsonrpc2.Error {
+	return &jsonrpc2.Error{
+		Code:    codeRequestCancelled,
+		Message: "request cancelled",
 	}
-	return nil
 }
 
-func (h *handler) inspect() (map[string][]lsp.Diagnostic, error) {
-	ret := map[string][]lsp.Diagnostic{}
+// rootFor returns the root module in the directory, loading it if it has not been loaded yet.
+// If the config is invalid, the errors are published as diagnostics in the config file,
+// and the root module is not inspected until the config is fixed.
+func (h *handler) rootFor(conn *jsonrpc2.Conn, dir string) (*root, error) {
+	if r, exists := h.roots[dir]; exists {
+		return r, nil
+	}
 
-	loader, err := terraform.NewLoader(afero.Afero{Fs: h.fs}, h.rootDir)
-	if err != nil {
-		return ret, fmt.Errorf("Failed to prepare loading: %w", err)
+	previous := h.configDiagnostics()
+	r, err := h.newRoot(conn, dir)
+	if r == nil {
+		return nil, err
 	}
+	h.roots[dir] = r
 
-	runner, runners, err := tflint.BuildRunners(loader, h.config, h.rootDir, ".")
 	if err != nil {
-		return ret, err
+		if err := r.setConfigError(r.configFile, err); err != nil {
+			return nil, err
+		}
+		return r, h.publishConfigDiagnostics(conn, previous)
 	}
-	runners = append(runners, runner) // langserver iterates a single slice incl. root
+	if h.initialized && h.watchSupport {
+		if err := h.registerRootWatchers(conn, r); err != nil {
+			return nil, err
+		}
+	}
+	return r, nil
+}
+
+// sortedRoots returns the root modules in the order of their directories.
+func (h *handler) sortedRoots() []*root {
+	roots := make([]*root, 0, len(h.roots))
+	for _, r := range h.roots {
+		roots = append(roots, r)
+	}
+	sort.Slice(roots, func(i, j int) bool { return roots[i].dir < roots[j].dir })
+	return roots
+}
 
-	config := h.config.ToPluginConfig()
-	for name, ruleset := range h.plugin.RuleSets {
-		if err := ruleset.ApplyGlobalConfig(config); err != nil {
-			return ret, fmt.Errorf(`Failed to apply global config to "%s" plugin`, name)
+// rootsContaining returns the root modules whose directories contain the path.
+// Files in a directory can be loaded by parent root modules as module calls.
+func (h *handler) rootsContaining(path string) []*root {
+	roots := []*root{}
+	for _, r := range h.sortedRoots() {
+		if _, ok := relPath(r.dir, path); ok {
+			roots = append(roots, r)
 		}
-		configSchema, err := ruleset.ConfigSchema()
-		if err != nil {
-			return ret, fmt.Errorf(`Failed to fetch config schema from "%s" plugin`, name)
+	}
+	return roots
+}
+
+// configPathFor returns the config file for the root module in the directory.
+// The config file passed by --config or TFLINT_CONFIG_FILE takes precedence.
+// Otherwise, the config file in the directory is used, falling back to the one in the closest workspace folder.
+// Returns an empty string to load the default config file.
+func (h *handler) configPathFor(dir string) string {
+	if h.configPath != "" {
+		return h.configPath
+	}
+	if env := os.Getenv("TFLINT_CONFIG_FILE"); env != "" {
+		if path, err := filepath.Abs(env); err == nil {
+			return path
 		}
-		content := &hclext.BodyContent{}
-		if plugin, exists := h.config.Plugins[name]; exists {
-			var diags hcl.Diagnostics
-			content, diags = plugin.Content(configSchema)
-			if diags.HasErrors() {
-				return ret, fmt.Errorf(`Failed to parse "%s" plugin config`, name)
-			}
+		return env
+	}
+	if configFileIn(dir) != "" {
+		return ""
+	}
+	if folder := h.folderOf(dir); folder != "" {
+		return configFileIn(folder)
+	}
+	return ""
+}
+
+// configFileIn returns the default config file in the directory, if any (prefer .hcl over .json).
+func configFileIn(dir string) string {
+	for _, name := range []string{".tflint.hcl", ".tflint.json"} {
+		path := filepath.Join(dir, name)
+		if _, err := os.Stat(path); err == nil {
+			return path
 		}
-		err = ruleset.ApplyConfig(content, h.config.Sources())
-		if err != nil {
-			return ret, fmt.Errorf(`Failed to apply config to "%s" plugin`, name)
+	}
+	return ""
+}
+
+// folderOf returns the closest workspace folder containing the path.
+// Returns an empty string if the path is outside workspace folders.
+func (h *handler) folderOf(path string) string {
+	ret := ""
+	for _, folder := range h.folders {
+		if _, ok := relPath(folder, path); ok && len(folder) > len(ret) {
+			ret = folder
 		}
-		for _, runner := range runners {
-			err = ruleset.Check(plugin.NewGRPCServer(runner, runners[len(runners)-1], loader.Files(), h.clientSDKVersions[name]))
-			if err != nil {
-				return ret, fmt.Errorf("Failed to check ruleset: %w", err)
-			}
+	}
+	return ret
+}
+
+// relPath returns the path relative to the directory.
+// Returns false if the path is outside the directory.
+func relPath(dir string, path string) (string, bool) {
+	rel, err := filepath.Rel(dir, path)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", false
+	}
+	return rel, true
+}
+
+// updateDocument updates the content of the open document in all root modules containing it.
+func (h *handler) updateDocument(path string, src []byte) error {
+	h.documents[path] = src
+	for _, r := range h.rootsContaining(path) {
+		r.mu.Lock()
+		err := r.writeDocument(path, src)
+		r.mu.Unlock()
+		if err != nil {
+			return err
 		}
 	}
+	return nil
+}
 
-	// In order to publish that the issue has been fixed,
-	// notify also the path where the past diagnostics were published.
-	for _, path := range h.diagsPaths {
-		ret[path] = []lsp.Diagnostic{}
+// publishDiagnostics notifies the client of diagnostics for each file.
+func (h *handler) publishDiagnostics(ctx context.Context, conn *jsonrpc2.Conn, diagnostics map[string][]diagnostic) error {
+	log.Printf("Notify textDocument/publishDiagnostics with %#v", diagnostics)
+	for path, diags := range diagnostics {
+		err := conn.Notify(
+			ctx,
+			"textDocument/publishDiagnostics",
+			publishDiagnosticsParams{
+				URI:         pathToURI(path),
+				Diagnostics: diags,
+			},
+		)
+		if err != nil {
+			return fmt.Errorf("Failed to notify textDocument/publishDiagnostics: %s", err)
+		}
 	}
-	h.diagsPaths = []string{}
 
-	for _, runner := range runners {
-		for _, issue := range runner.LookupIssues() {
-			path := filepath.Join(h.rootDir, issue.Range.Filename)
-			h.diagsPaths = append(h.diagsPaths, path)
+	return nil
+}
 
-			diag := lsp.Diagnostic{
-				Message:  issue.Message,
-				Severity: toLSPSeverity(issue.Rule.Severity()),
-				Range: lsp.Range{
-					Start: lsp.Position{Line: issue.Range.Start.Line - 1, Character: issue.Range.Start.Column - 1},
-					End:   lsp.Position{Line: issue.Range.End.Line - 1, Character: issue.Range.End.Column - 1},
-				},
-			}
+// refreshDiagnostics asks the client to pull diagnostics again, if supported.
+func (h *handler) refreshDiagnostics(ctx context.Context, conn *jsonrpc2.Conn) error {
+	if !h.refreshSupport {
+		return nil
+	}
 
-			if ret[path] == nil {
-				ret[path] = []lsp.Diagnostic{diag}
-			} else {
-				ret[path] = append(ret[path], diag)
-			}
-		}
+	log.Print("Request workspace/diagnostic/refresh")
+	// Do not wait for the response, so that handling messages is not blocked by the client
+	if _, err := conn.DispatchCall(ctx, "workspace/diagnostic/refresh", nil); err != nil {
+		return fmt.Errorf("Failed to request workspace/diagnostic/refresh: %s", err)
 	}
+	return nil
+}
 
-	return ret, nil
+// absConfigPath returns the absolute path of the loaded config file.
+// Config files are resolved relative to the root directory.
+func absConfigPath(cfg *tflint.Config, dir string) string {
+	if cfg.Path() == "" || filepath.IsAbs(cfg.Path()) {
+		return cfg.Path()
+	}
+	return filepath.Join(dir, cfg.Path())
 }
 
 func uriToPath(uri lsp.DocumentURI) (string, error) {
@@ -267,6 +396,52 @@ func pathToURI(path string) lsp.DocumentURI {
 	return lsp.DocumentURI("file://" + head + rest)
 }
 
+// diagnostic is a diagnostic with the codeDescription and data fields added in LSP 3.16.
+// The rule name is passed to the data field so that code actions can be built from diagnostics sent back by clients.
+type diagnostic struct {
+	lsp.Diagnostic
+	CodeDescription *codeDescription `json:"codeDescription,omitempty"`
+	Data            *diagnosticData  `json:"data,omitempty"`
+}
+
+// codeDescription is the link to the rule documentation.
+type codeDescription struct {
+	Href string `json:"href"`
+}
+
+type diagnosticData struct {
+	Rule string `json:"rule"`
+}
+
+// publishDiagnosticsParams is the same as lsp.PublishDiagnosticsParams, but uses diagnostic.
+type publishDiagnosticsParams struct {
+	URI         lsp.DocumentURI `json:"uri"`
+	Diagnostics []diagnostic    `json:"diagnostics"`
+}
+
+func toLSPDiagnostic(issue *tflint.Issue) diagnostic {
+	diag := diagnostic{
+		Diagnostic: lsp.Diagnostic{
+			Message:  issue.Message,
+			Severity: toLSPSeverity(issue.Rule.Severity()),
+			Code:     issue.Rule.Name(),
+			Range:    toLSPRange(issue.Range),
+		},
+		Data: &diagnosticData{Rule: issue.Rule.Name()},
+	}
+	if link := issue.Rule.Link(); link != "" {
+		diag.CodeDescription = &codeDescription{Href: link}
+	}
+	return diag
+}
+
+func toLSPRange(rng hcl.Range) lsp.Range {
+	return lsp.Range{
+		Start: lsp.Position{Line: rng.Start.Line - 1, Character: rng.Start.Column - 1},
+		End:   lsp.Position{Line: rng.End.Line - 1, Character: rng.End.Column - 1},
+	}
+}
+
 func toLSPSeverity(severity tflint.Severity) lsp.DiagnosticSeverity {
 	switch severity {
 	case sdk.ERROR:
@@ -1,11 +1,16 @@
 package langserver
 
 import (
+	"os"
+	"path/filepath"
 	"runtime"
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
+	"github.com/hashicorp/hcl/v2"
 	lsp "github.com/sourcegraph/go-lsp"
+	sdk "github.com/terraform-linters/tflint-plugin-sdk/tflint"
+	"github.com/terraform-linters/tflint/tflint"
 )
 
 func Test_uriToPath_windows(t *testing.T) {
@@ -35,3 +40,144 @@ func Test_uriToPath_others(t *testing.T) {
 		t.Fatalf("Diff: %s", cmp.Diff(expected, value))
 	}
 }
+
+type testRule struct {
+	link string
+}
+
+func (r *testRule) Name() string {
+	return "test_rule"
+}
+
+func (r *testRule) Severity() tflint.Severity {
+	return sdk.WARNING
+}
+
+func (r *testRule) Link() string {
+	return r.link
+}
+
+func Test_toLSPDiagnostic(t *testing.T) {
+	issue := &tflint.Issue{
+		Rule:    &testRule{link: "https://example.com/test_rule.md"},
+		Message: "test message",
+		Range: hcl.Range{
+			Filename: "main.tf",
+			Start:    hcl.Pos{Line: 1, Column: 1},
+			End:      hcl.Pos{Line: 1, Column: 5},
+		},
+	}
+
+	got := toLSPDiagnostic(issue)
+	expected := diagnostic{
+		Diagnostic: lsp.Diagnostic{
+			Range: lsp.Range{
+				Start: lsp.Position{Line: 0, Character: 0},
+				End:   lsp.Position{Line: 0, Character: 4},
+			},
+			Severity: lsp.Warning,
+			Code:     "test_rule",
+			Message:  "test message",
+		},
+		CodeDescription: &codeDescription{Href: "https://example.com/test_rule.md"},
+		Data:            &diagnosticData{Rule: "test_rule"},
+	}
+	if diff := cmp.Diff(expected, got); diff != "" {
+		t.Fatal(diff)
+	}
+
+	// Rules without documentation have no code description
+	issue.Rule = &testRule{}
+	if got := toLSPDiagnostic(issue); got.CodeDescription != nil {
+		t.Fatalf("expected no code description, but got %#v", got.CodeDescription)
+	}
+}
+
+func Test_issueDocument(t *testing.T) {
+	r := &root{rulePlugins: map[string]string{"test_rule": "testing"}}
+
+	issue := &tflint.Issue{
+		Rule:    &testRule{link: "https://example.com/test_rule.md"},
+		Message: "test message",
+		Fixable: false,
+		Callers: []hcl.Range{
+			{
+				Filename: "main.tf",
+				Start:    hcl.Pos{Line: 3, Column: 15},
+				End:      hcl.Pos{Line: 3, Column: 20},
+			},
+			{
+				Filename: "module/main.tf",
+				Start:    hcl.Pos{Line: 2, Column: 3},
+				End:      hcl.Pos{Line: 2, Column: 10},
+			},
+		},
+	}
+
+	got, err := r.issueDocument(issue)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := "**test_rule**\n\ntest message\n\n" +
+		"- Plugin: testing\n- Severity: Warning\n- Fixable: no\n\n" +
+		"Callers:\n\n1. `main.tf:3,15-20`\n2. `module/main.tf:2,3-10`\n\n" +
+		"[Documentation](https://example.com/test_rule.md)"
+	if diff := cmp.Diff(expected, got); diff != "" {
+		t.Fatal(diff)
+	}
+}
+
+func Test_configPathFor(t *testing.T) {
+	t.Setenv("TFLINT_CONFIG_FILE", "")
+
+	folder := t.TempDir()
+	for _, dir := range []string{"own", "nested/deep"} {
+		if err := os.MkdirAll(filepath.Join(folder, dir), 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, path := range []string{".tflint.hcl", "own/.tflint.json"} {
+		if err := os.WriteFile(filepath.Join(folder, path), []byte{}, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	outside := t.TempDir()
+
+	tests := []struct {
+		name       string
+		configPath string
+		dir        string
+		want       string
+	}{
+		{
+			name: "config file in the directory",
+			dir:  filepath.Join(folder, "own"),
+			want: "",
+		},
+		{
+			name: "config file in the workspace folder",
+			dir:  filepath.Join(folder, "nested/deep"),
+			want: filepath.Join(folder, ".tflint.hcl"),
+		},
+		{
+			name: "outside workspace folders",
+			dir:  outside,
+			want: "",
+		},
+		{
+			name:       "--config",
+			configPath: "/config/.tflint.hcl",
+			dir:        filepath.Join(folder, "own"),
+			want:       "/config/.tflint.hcl",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			h := &handler{configPath: test.configPath, folders: []string{folder}}
+			if got := h.configPathFor(test.dir); got != test.want {
+				t.Errorf("expected %q, but got %q", test.want, got)
+			}
+		})
+	}
+}
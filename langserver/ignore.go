@@ -0,0 +1,304 @@
+package langserver
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	lsp "github.com/sourcegraph/go-lsp"
+	"github.com/spf13/afero"
+	"github.com/terraform-linters/tflint/tflint"
+)
+
+// ignoreActions returns quick fixes to ignore rules of the diagnostics in the context.
+// Rules can be ignored by a tflint-ignore annotation above the line, a tflint-ignore-file annotation,
+// or by disabling them in the config file. Line annotations are not available in JSON files,
+// so a tflint-ignore-file annotation in the root-level "//" property is offered instead.
+func (r *root) ignoreActions(path string, src []byte, params codeActionParams) ([]codeAction, error) {
+	actions := []codeAction{}
+	titles := map[string]bool{}
+	add := func(action codeAction) {
+		// Actions for the file and config are the same for every diagnostic of the rule
+		if titles[action.Title] {
+			return
+		}
+		titles[action.Title] = true
+		actions = append(actions, action)
+	}
+
+	isJSON := strings.HasSuffix(path, ".json")
+	annotations, diags := tflint.NewAnnotations(path, &hcl.File{Bytes: src})
+	if diags.HasErrors() {
+		// The source cannot be annotated, but the rule can still be disabled in the config file
+		annotations = nil
+	}
+	uri := string(params.TextDocument.URI)
+
+	for _, diag := range params.Context.Diagnostics {
+		if diag.Data == nil || diag.Data.Rule == "" {
+			continue
+		}
+		rule := diag.Data.Rule
+
+		if annotations != nil && r.ruleIsIgnorable(rule) {
+			if !isJSON {
+				add(codeAction{
+					Title:       fmt.Sprintf("Ignore %s for this line", rule),
+					Kind:        lsp.CAKQuickFix,
+					Diagnostics: []diagnostic{diag},
+					Edit: &lsp.WorkspaceEdit{
+						Changes: map[string][]lsp.TextEdit{uri: {ignoreLineEdit(src, annotations, diag.Range.Start.Line, rule)}},
+					},
+				})
+			}
+
+			var edit lsp.TextEdit
+			var err error
+			if isJSON {
+				edit, err = ignoreJSONFileEdit(src, annotations, rule)
+			} else {
+				edit = ignoreFileEdit(src, annotations, rule)
+			}
+			if err == nil {
+				add(codeAction{
+					Title:       fmt.Sprintf("Ignore %s for this file", rule),
+					Kind:        lsp.CAKQuickFix,
+					Diagnostics: []diagnostic{diag},
+					Edit: &lsp.WorkspaceEdit{
+						Changes: map[string][]lsp.TextEdit{uri: {edit}},
+					},
+				})
+			}
+		}
+
+		edit, ok, err := r.disableRuleEdit(rule)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			add(codeAction{
+				Title:       fmt.Sprintf("Disable %s in %s", rule, filepath.Base(r.configFile)),
+				Kind:        lsp.CAKQuickFix,
+				Diagnostics: []diagnostic{diag},
+				Edit: &lsp.WorkspaceEdit{
+					Changes: map[string][]lsp.TextEdit{string(pathToURI(r.configFile)): {edit}},
+				},
+			})
+		}
+	}
+
+	return actions, nil
+}
+
+// ruleIsIgnorable returns whether annotations can suppress issues of the rule.
+func (r *root) ruleIsIgnorable(name string) bool {
+	rule, exists := r.config.Rules[name]
+	return !exists || rule.Ignorable == nil || *rule.Ignorable
+}
+
+// ignoreLineEdit returns an edit to ignore the rule on the line (0-based).
+// If the line is already annotated, the rule is appended to the annotation.
+// Otherwise, a new annotation is inserted above the line with the same indentation.
+func ignoreLineEdit(src []byte, annotations tflint.Annotations, line int, rule string) lsp.TextEdit {
+	for _, annotation := range annotations {
+		if annotation, ok := annotation.(*tflint.LineAnnotation); ok {
+			// Annotations on the line or the line above affect issues on the line
+			annotatedLine := annotation.Token.Range.Start.Line - 1
+			if annotatedLine == line || annotatedLine == line-1 {
+				return appendRuleEdit(src, annotation.Token, "tflint-ignore:", annotation.Content, rule)
+			}
+		}
+	}
+
+	lines := strings.SplitAfter(string(src), "\n")
+	indent := ""
+	if line < len(lines) {
+		indent = lines[line][:len(lines[line])-len(strings.TrimLeft(lines[line], " \t"))]
+	}
+	pos := lsp.Position{Line: line, Character: 0}
+	return lsp.TextEdit{
+		Range:   lsp.Range{Start: pos, End: pos},
+		NewText: fmt.Sprintf("%s# tflint-ignore: %s\n", indent, rule),
+	}
+}
+
+// ignoreFileEdit returns an edit to ignore the rule in the file.
+// If the file already has a tflint-ignore-file annotation, the rule is appended to it.
+// Otherwise, a new annotation is inserted at the top of the file.
+func ignoreFileEdit(src []byte, annotations tflint.Annotations, rule string) lsp.TextEdit {
+	for _, annotation := range annotations {
+		if annotation, ok := annotation.(*tflint.FileAnnotation); ok {
+			return appendRuleEdit(src, annotation.Token, "tflint-ignore-file:", annotation.Content, rule)
+		}
+	}
+
+	pos := lsp.Position{Line: 0, Character: 0}
+	return lsp.TextEdit{
+		Range:   lsp.Range{Start: pos, End: pos},
+		NewText: fmt.Sprintf("# tflint-ignore-file: %s\n", rule),
+	}
+}
+
+// appendRuleEdit returns an edit to append the rule to the content of the annotation comment.
+func appendRuleEdit(src []byte, token hclsyntax.Token, prefix string, content string, rule string) lsp.TextEdit {
+	comment := string(token.Bytes)
+	start := strings.Index(comment, prefix)
+	end := start + strings.Index(comment[start:], content) + len(content)
+
+	pos := offsetToPosition(src, token.Range.Start.Byte+end)
+	return lsp.TextEdit{
+		Range:   lsp.Range{Start: pos, End: pos},
+		NewText: ", " + rule,
+	}
+}
+
+// ignoreJSONFileEdit returns an edit to ignore the rule in the JSON file.
+// The annotation is written in the root-level "//" property. If the property exists,
+// the annotation is added to it. Otherwise, a new property is inserted.
+func ignoreJSONFileEdit(src []byte, annotations tflint.Annotations, rule string) (lsp.TextEdit, error) {
+	dec := json.NewDecoder(bytes.NewReader(src))
+	if token, err := dec.Token(); err != nil || token != json.Delim('{') {
+		return lsp.TextEdit{}, fmt.Errorf("root value is not an object")
+	}
+	open := int(dec.InputOffset())
+
+	empty := true
+	for dec.More() {
+		key, err := dec.Token()
+		if err != nil {
+			return lsp.TextEdit{}, err
+		}
+		var value json.RawMessage
+		if err := dec.Decode(&value); err != nil {
+			return lsp.TextEdit{}, err
+		}
+		empty = false
+		if key != "//" {
+			continue
+		}
+
+		var comment string
+		if err := json.Unmarshal(value, &comment); err != nil {
+			return lsp.TextEdit{}, err
+		}
+		annotated := false
+		for _, annotation := range annotations {
+			if annotation, ok := annotation.(*tflint.FileAnnotation); ok {
+				idx := strings.Index(comment, annotation.Content) + len(annotation.Content)
+				comment = comment[:idx] + ", " + rule + comment[idx:]
+				annotated = true
+			}
+		}
+		if !annotated {
+			// The annotation must be written at the beginning of the comment
+			if comment == "" {
+				comment = "tflint-ignore-file: " + rule
+			} else {
+				comment = "tflint-ignore-file: " + rule + "\n" + comment
+			}
+		}
+		newValue, err := marshalJSONString(comment)
+		if err != nil {
+			return lsp.TextEdit{}, err
+		}
+
+		end := int(dec.InputOffset())
+		return lsp.TextEdit{
+			Range: lsp.Range{
+				Start: offsetToPosition(src, end-len(value)),
+				End:   offsetToPosition(src, end),
+			},
+			NewText: newValue,
+		}, nil
+	}
+
+	newValue, err := marshalJSONString("tflint-ignore-file: " + rule)
+	if err != nil {
+		return lsp.TextEdit{}, err
+	}
+	text := fmt.Sprintf("\n  \"//\": %s", newValue)
+	if empty {
+		text += "\n"
+	} else {
+		text += ","
+	}
+	pos := offsetToPosition(src, open)
+	return lsp.TextEdit{
+		Range:   lsp.Range{Start: pos, End: pos},
+		NewText: text,
+	}, nil
+}
+
+// marshalJSONString encodes the string as JSON without escaping HTML characters.
+func marshalJSONString(s string) (string, error) {
+	buf := new(bytes.Buffer)
+	enc := json.NewEncoder(buf)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(s); err != nil {
+		return "", err
+	}
+	return strings.TrimSuffix(buf.String(), "\n"), nil
+}
+
+// disableRuleEdit returns an edit to disable the rule in the config file.
+// If the rule block exists, its "enabled" attribute is set to false. Otherwise, a new rule block is appended.
+// Returns false if the config file cannot be edited, e.g. no config file is loaded or it is written in JSON.
+func (r *root) disableRuleEdit(rule string) (lsp.TextEdit, bool, error) {
+	if r.configFile == "" || filepath.Ext(r.configFile) != ".hcl" {
+		return lsp.TextEdit{}, false, nil
+	}
+
+	src, err := afero.ReadFile(r.fs, r.configFile)
+	if err != nil {
+		return lsp.TextEdit{}, false, fmt.Errorf("Failed to read %s: %s", r.configFile, err)
+	}
+	file, diags := hclsyntax.ParseConfig(src, r.configFile, hcl.InitialPos)
+	if diags.HasErrors() {
+		return lsp.TextEdit{}, false, nil
+	}
+	body := file.Body.(*hclsyntax.Body)
+
+	for _, block := range body.Blocks {
+		if block.Type != "rule" || len(block.Labels) != 1 || block.Labels[0] != rule {
+			continue
+		}
+
+		if attr, exists := block.Body.Attributes["enabled"]; exists {
+			return lsp.TextEdit{Range: toLSPRange(attr.Expr.Range()), NewText: "false"}, true, nil
+		}
+		pos := toLSPRange(block.OpenBraceRange).End
+		return lsp.TextEdit{
+			Range:   lsp.Range{Start: pos, End: pos},
+			NewText: "\n  enabled = false",
+		}, true, nil
+	}
+
+	text := fmt.Sprintf("rule %q {\n  enabled = false\n}\n", rule)
+	if len(src) > 0 {
+		text = "\n" + text
+		if !bytes.HasSuffix(src, []byte("\n")) {
+			text = "\n" + text
+		}
+	}
+	pos := offsetToPosition(src, len(src))
+	return lsp.TextEdit{
+		Range:   lsp.Range{Start: pos, End: pos},
+		NewText: text,
+	}, true, nil
+}
+
+// offsetToPosition converts the byte offset in the source to a position.
+// As with diagnostics, characters are counted instead of UTF-16 code units.
+func offsetToPosition(src []byte, offset int) lsp.Position {
+	before := src[:offset]
+	lineStart := bytes.LastIndexByte(before, '\n') + 1
+	return lsp.Position{
+		Line:      bytes.Count(before, []byte("\n")),
+		Character: utf8.RuneCount(before[lineStart:]),
+	}
+}
@@ -0,0 +1,245 @@
+package langserver
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/hashicorp/hcl/v2"
+	lsp "github.com/sourcegraph/go-lsp"
+	"github.com/spf13/afero"
+	"github.com/terraform-linters/tflint/tflint"
+)
+
+func insertion(line, character int, text string) lsp.TextEdit {
+	pos := lsp.Position{Line: line, Character: character}
+	return lsp.TextEdit{Range: lsp.Range{Start: pos, End: pos}, NewText: text}
+}
+
+func Test_ignoreLineEdit(t *testing.T) {
+	tests := []struct {
+		name string
+		src  string
+		line int
+		want lsp.TextEdit
+	}{
+		{
+			name: "no annotations",
+			src: `resource "aws_instance" "foo" {
+  instance_type = "t1.2xlarge"
+}`,
+			line: 1,
+			want: insertion(1, 0, "  # tflint-ignore: test_rule\n"),
+		},
+		{
+			name: "annotation above the line",
+			src: `resource "aws_instance" "foo" {
+  # tflint-ignore: other_rule
+  instance_type = "t1.2xlarge"
+}`,
+			line: 2,
+			want: insertion(1, 29, ", test_rule"),
+		},
+		{
+			name: "annotation on the line",
+			src: `resource "aws_instance" "foo" {
+  instance_type = "t1.2xlarge" /* tflint-ignore: other_rule */
+}`,
+			line: 1,
+			want: insertion(1, 59, ", test_rule"),
+		},
+		{
+			name: "unrelated annotation",
+			src: `# tflint-ignore: other_rule
+resource "aws_instance" "foo" {
+  instance_type = "t1.2xlarge"
+}`,
+			line: 2,
+			want: insertion(2, 0, "  # tflint-ignore: test_rule\n"),
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			annotations, diags := tflint.NewAnnotations("main.tf", &hcl.File{Bytes: []byte(test.src)})
+			if diags.HasErrors() {
+				t.Fatal(diags)
+			}
+
+			got := ignoreLineEdit([]byte(test.src), annotations, test.line, "test_rule")
+			if diff := cmp.Diff(test.want, got); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
+
+func Test_ignoreFileEdit(t *testing.T) {
+	tests := []struct {
+		name string
+		src  string
+		want lsp.TextEdit
+	}{
+		{
+			name: "no annotations",
+			src:  `resource "aws_instance" "foo" {}`,
+			want: insertion(0, 0, "# tflint-ignore-file: test_rule\n"),
+		},
+		{
+			name: "existing annotation",
+			src: `# tflint-ignore-file: other_rule
+resource "aws_instance" "foo" {}`,
+			want: insertion(0, 32, ", test_rule"),
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			annotations, diags := tflint.NewAnnotations("main.tf", &hcl.File{Bytes: []byte(test.src)})
+			if diags.HasErrors() {
+				t.Fatal(diags)
+			}
+
+			got := ignoreFileEdit([]byte(test.src), annotations, "test_rule")
+			if diff := cmp.Diff(test.want, got); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
+
+func Test_ignoreJSONFileEdit(t *testing.T) {
+	tests := []struct {
+		name string
+		src  string
+		want lsp.TextEdit
+	}{
+		{
+			name: "no comment",
+			src: `{
+  "resource": {}
+}`,
+			want: insertion(0, 1, "\n  \"//\": \"tflint-ignore-file: test_rule\","),
+		},
+		{
+			name: "empty object",
+			src:  `{}`,
+			want: insertion(0, 1, "\n  \"//\": \"tflint-ignore-file: test_rule\"\n"),
+		},
+		{
+			name: "existing comment",
+			src: `{
+  "resource": {},
+  "//": "this is a comment <>"
+}`,
+			want: lsp.TextEdit{
+				Range: lsp.Range{
+					Start: lsp.Position{Line: 2, Character: 8},
+					End:   lsp.Position{Line: 2, Character: 30},
+				},
+				NewText: `"tflint-ignore-file: test_rule\nthis is a comment <>"`,
+			},
+		},
+		{
+			name: "existing annotation",
+			src: `{
+  "//": "tflint-ignore-file: other_rule",
+  "resource": {}
+}`,
+			want: lsp.TextEdit{
+				Range: lsp.Range{
+					Start: lsp.Position{Line: 1, Character: 8},
+					End:   lsp.Position{Line: 1, Character: 40},
+				},
+				NewText: `"tflint-ignore-file: other_rule, test_rule"`,
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			annotations, diags := tflint.NewAnnotations("main.tf.json", &hcl.File{Bytes: []byte(test.src)})
+			if diags.HasErrors() {
+				t.Fatal(diags)
+			}
+
+			got, err := ignoreJSONFileEdit([]byte(test.src), annotations, "test_rule")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(test.want, got); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
+
+func Test_disableRuleEdit(t *testing.T) {
+	tests := []struct {
+		name   string
+		config string
+		want   lsp.TextEdit
+	}{
+		{
+			name: "no rule blocks",
+			config: `plugin "testing" {
+  enabled = true
+}
+`,
+			want: insertion(3, 0, "\nrule \"test_rule\" {\n  enabled = false\n}\n"),
+		},
+		{
+			name: "no trailing newline",
+			config: `plugin "testing" {
+  enabled = true
+}`,
+			want: insertion(2, 1, "\n\nrule \"test_rule\" {\n  enabled = false\n}\n"),
+		},
+		{
+			name: "existing rule block",
+			config: `rule "test_rule" {
+  enabled = true
+}
+`,
+			want: lsp.TextEdit{
+				Range: lsp.Range{
+					Start: lsp.Position{Line: 1, Character: 12},
+					End:   lsp.Position{Line: 1, Character: 16},
+				},
+				NewText: "false",
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			fs := afero.NewMemMapFs()
+			if err := afero.WriteFile(fs, "/config/.tflint.hcl", []byte(test.config), 0o644); err != nil {
+				t.Fatal(err)
+			}
+			r := &root{configFile: "/config/.tflint.hcl", fs: fs}
+
+			got, ok, err := r.disableRuleEdit("test_rule")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !ok {
+				t.Fatal("expected an edit, but got none")
+			}
+			if diff := cmp.Diff(test.want, got); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
+
+func Test_disableRuleEdit_json(t *testing.T) {
+	r := &root{configFile: "/config/.tflint.json", fs: afero.NewMemMapFs()}
+
+	_, ok, err := r.disableRuleEdit("test_rule")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ok {
+		t.Fatal("expected no edits for JSON config")
+	}
+}
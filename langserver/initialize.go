@@ -2,20 +2,133 @@ package langserver
 
 import (
 	"context"
+	"encoding/json"
 
 	lsp "github.com/sourcegraph/go-lsp"
 	"github.com/sourcegraph/jsonrpc2"
 )
 
-func initialize(ctx context.Context, conn *jsonrpc2.Conn, req *jsonrpc2.Request) (result any, err error) {
-	return lsp.InitializeResult{
-		Capabilities: lsp.ServerCapabilities{
+// initializeParams is a subset of the initialize params.
+// go-lsp does not define the client capabilities for pull diagnostics and inlay hints added in LSP 3.17,
+// for dynamic registration of file watchers, workspace folders added in LSP 3.6, and work done progress added in LSP 3.15.
+type initializeParams struct {
+	RootURI          lsp.DocumentURI   `json:"rootUri"`
+	WorkspaceFolders []workspaceFolder `json:"workspaceFolders"`
+	Capabilities     struct {
+		TextDocument struct {
+			Diagnostic *struct{} `json:"diagnostic"`
+			InlayHint  *struct{} `json:"inlayHint"`
+		} `json:"textDocument"`
+		Workspace struct {
+			Diagnostics struct {
+				RefreshSupport bool `json:"refreshSupport"`
+			} `json:"diagnostics"`
+			DidChangeWatchedFiles struct {
+				DynamicRegistration bool `json:"dynamicRegistration"`
+			} `json:"didChangeWatchedFiles"`
+			WorkspaceFolders bool `json:"workspaceFolders"`
+		} `json:"workspace"`
+		Window struct {
+			WorkDoneProgress bool `json:"workDoneProgress"`
+		} `json:"window"`
+	} `json:"capabilities"`
+}
+
+type initializeResult struct {
+	Capabilities serverCapabilities `json:"capabilities"`
+}
+
+type workspaceFolder struct {
+	URI  lsp.DocumentURI `json:"uri"`
+	Name string          `json:"name"`
+}
+
+// serverCapabilities is lsp.ServerCapabilities with the diagnostic and inlay hint providers added in LSP 3.17,
+// and the workspace folders capability added in LSP 3.6.
+type serverCapabilities struct {
+	lsp.ServerCapabilities
+	DiagnosticProvider *diagnosticOptions           `json:"diagnosticProvider,omitempty"`
+	InlayHintProvider  bool                         `json:"inlayHintProvider,omitempty"`
+	Workspace          *workspaceServerCapabilities `json:"workspace,omitempty"`
+}
+
+type workspaceServerCapabilities struct {
+	WorkspaceFolders workspaceFoldersServerCapabilities `json:"workspaceFolders"`
+}
+
+type workspaceFoldersServerCapabilities struct {
+	Supported           bool `json:"supported"`
+	ChangeNotifications bool `json:"changeNotifications"`
+}
+
+type diagnosticOptions struct {
+	InterFileDependencies bool `json:"interFileDependencies"`
+	WorkspaceDiagnostics  bool `json:"workspaceDiagnostics"`
+}
+
+func (h *handler) initialize(ctx context.Context, conn *jsonrpc2.Conn, req *jsonrpc2.Request) (result any, err error) {
+	var params initializeParams
+	if req.Params != nil {
+		if err := json.Unmarshal(*req.Params, &params); err != nil {
+			return nil, &jsonrpc2.Error{
+				Code:    jsonrpc2.CodeParseError,
+				Message: err.Error(),
+				Data:    req.Params,
+			}
+		}
+	}
+
+	capabilities := serverCapabilities{
+		ServerCapabilities: lsp.ServerCapabilities{
 			TextDocumentSync: &lsp.TextDocumentSyncOptionsOrKind{
 				Options: &lsp.TextDocumentSyncOptions{
 					OpenClose: true,
 					Change:    lsp.TDSKFull,
+					Save:      &lsp.SaveOptions{},
 				},
 			},
+			HoverProvider:      true,
+			CodeActionProvider: true,
+			// Rule names are completed in labels of "rule" blocks
+			CompletionProvider: &lsp.CompletionOptions{TriggerCharacters: []string{`"`}},
 		},
-	}, nil
+	}
+
+	h.watchSupport = params.Capabilities.Workspace.DidChangeWatchedFiles.DynamicRegistration
+	h.progressSupport = params.Capabilities.Window.WorkDoneProgress
+
+	// Clients that do not support workspace folders open a single folder as the root URI
+	folders := params.WorkspaceFolders
+	if len(folders) == 0 && params.RootURI != "" {
+		folders = []workspaceFolder{{URI: params.RootURI}}
+	}
+	h.folders = []string{}
+	for _, folder := range folders {
+		path, err := uriToPath(folder.URI)
+		if err != nil {
+			return nil, err
+		}
+		h.folders = append(h.folders, path)
+	}
+	if params.Capabilities.Workspace.WorkspaceFolders {
+		capabilities.Workspace = &workspaceServerCapabilities{
+			WorkspaceFolders: workspaceFoldersServerCapabilities{Supported: true, ChangeNotifications: true},
+		}
+	}
+
+	// Inlay hints are optional, so they are only provided to clients that declare support
+	capabilities.InlayHintProvider = params.Capabilities.TextDocument.InlayHint != nil
+
+	// Diagnostics are pushed to clients that do not support pull diagnostics
+	if params.Capabilities.TextDocument.Diagnostic != nil {
+		h.pullDiagnostics = true
+		h.refreshSupport = params.Capabilities.Workspace.Diagnostics.RefreshSupport
+		// Issues depend on other files in the module, such as variables
+		capabilities.DiagnosticProvider = &diagnosticOptions{
+			InterFileDependencies: true,
+			WorkspaceDiagnostics:  true,
+		}
+	}
+
+	return initializeResult{Capabilities: capabilities}, nil
 }
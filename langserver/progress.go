@@ -0,0 +1,139 @@
+package langserver
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+
+	lsp "github.com/sourcegraph/go-lsp"
+	"github.com/sourcegraph/jsonrpc2"
+)
+
+// go-lsp does not define work done progress added in LSP 3.15.
+type workDoneProgressCreateParams struct {
+	Token string `json:"token"`
+}
+
+type workDoneProgressCancelParams struct {
+	Token string `json:"token"`
+}
+
+type progressParams struct {
+	Token string `json:"token"`
+	Value any    `json:"value"`
+}
+
+type workDoneProgressBegin struct {
+	Kind        string `json:"kind"`
+	Title       string `json:"title"`
+	Cancellable bool   `json:"cancellable"`
+	Message     string `json:"message"`
+	Percentage  int    `json:"percentage"`
+}
+
+type workDoneProgressReport struct {
+	Kind       string `json:"kind"`
+	Message    string `json:"message"`
+	Percentage int    `json:"percentage"`
+}
+
+type workDoneProgressEnd struct {
+	Kind    string `json:"kind"`
+	Message string `json:"message,omitempty"`
+}
+
+// progress reports the progress of a long-running operation to the client via $/progress.
+// A nil progress is valid and reports nothing, so callers do not need to check whether the client supports it.
+type progress struct {
+	h     *handler
+	conn  *jsonrpc2.Conn
+	token string
+}
+
+// beginProgress starts reporting progress if the client supports window/workDoneProgress.
+// If cancel is not nil, the progress is cancellable, and cancel is called when the client cancels it.
+// Failures to report progress are only logged, since they do not affect the operation.
+func (h *handler) beginProgress(conn *jsonrpc2.Conn, message string, cancel func()) *progress {
+	if conn == nil || !h.progressSupport {
+		return nil
+	}
+
+	h.progressMu.Lock()
+	h.progressSeq++
+	p := &progress{h: h, conn: conn, token: fmt.Sprintf("tflint-%d", h.progressSeq)}
+	h.progressMu.Unlock()
+
+	// Do not wait for the response, so that handling messages is not blocked by the client
+	if _, err := conn.DispatchCall(context.Background(), "window/workDoneProgress/create", workDoneProgressCreateParams{Token: p.token}); err != nil {
+		log.Printf("Failed to request window/workDoneProgress/create: %s", err)
+		return nil
+	}
+	if cancel != nil {
+		h.progressMu.Lock()
+		h.progressCancels[p.token] = cancel
+		h.progressMu.Unlock()
+	}
+
+	p.notify(workDoneProgressBegin{Kind: "begin", Title: "TFLint", Cancellable: cancel != nil, Message: message})
+	return p
+}
+
+// report updates the message and the percentage of the progress.
+func (p *progress) report(message string, percentage int) {
+	if p == nil {
+		return
+	}
+	p.notify(workDoneProgressReport{Kind: "report", Message: message, Percentage: percentage})
+}
+
+// end finishes the progress. The progress can no longer be cancelled.
+func (p *progress) end() {
+	if p == nil {
+		return
+	}
+	p.h.progressMu.Lock()
+	delete(p.h.progressCancels, p.token)
+	p.h.progressMu.Unlock()
+
+	p.notify(workDoneProgressEnd{Kind: "end"})
+}
+
+func (p *progress) notify(value any) {
+	if err := p.conn.Notify(context.Background(), "$/progress", progressParams{Token: p.token, Value: value}); err != nil {
+		log.Printf("Failed to notify $/progress: %s", err)
+	}
+}
+
+// cancelProgress handles window/workDoneProgress/cancel.
+// This is called as soon as the notification is received, even while another request is being handled.
+func (h *handler) cancelProgress(req *jsonrpc2.Request) {
+	if req.Params == nil {
+		return
+	}
+	var params workDoneProgressCancelParams
+	if err := json.Unmarshal(*req.Params, &params); err != nil {
+		log.Printf("Failed to parse window/workDoneProgress/cancel: %s", err)
+		return
+	}
+
+	h.progressMu.Lock()
+	cancel, exists := h.progressCancels[params.Token]
+	h.progressMu.Unlock()
+	if exists {
+		log.Printf("Cancel progress %s", params.Token)
+		cancel()
+	}
+}
+
+// showMessage shows the message to the user via window/showMessage.
+// This is used for errors that cannot be placed in source files, such as plugin failures.
+func (h *handler) showMessage(conn *jsonrpc2.Conn, typ lsp.MessageType, message string) {
+	if conn == nil {
+		return
+	}
+	log.Printf("Notify window/showMessage with %s", message)
+	if err := conn.Notify(context.Background(), "window/showMessage", lsp.ShowMessageParams{Type: typ, Message: message}); err != nil {
+		log.Printf("Failed to notify window/showMessage: %s", err)
+	}
+}
@@ -0,0 +1,131 @@
+package langserver
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"sync"
+
+	"github.com/sourcegraph/jsonrpc2"
+)
+
+// codeRequestCancelled is the error code for cancelled requests defined by LSP.
+const codeRequestCancelled = -32800
+
+// queue is a JSON-RPC handler that handles messages one by one in the order they are received,
+// but on a goroutine other than the one reading messages.
+// This allows $/cancelRequest and window/workDoneProgress/cancel to be handled immediately,
+// even while a slow inspection blocks the handling of other messages.
+type queue struct {
+	handler jsonrpc2.Handler
+	// cancelProgress handles window/workDoneProgress/cancel.
+	cancelProgress func(req *jsonrpc2.Request)
+
+	mu       sync.Mutex
+	messages []*queuedMessage
+	// requests are the requests that are queued or being handled, keyed by their IDs.
+	requests map[jsonrpc2.ID]*queuedMessage
+	ready    chan struct{}
+	start    sync.Once
+}
+
+type queuedMessage struct {
+	ctx    context.Context
+	cancel context.CancelFunc
+	req    *jsonrpc2.Request
+}
+
+type cancelParams struct {
+	ID jsonrpc2.ID `json:"id"`
+}
+
+func newQueue(handler jsonrpc2.Handler, cancelProgress func(req *jsonrpc2.Request)) *queue {
+	return &queue{
+		handler:        handler,
+		cancelProgress: cancelProgress,
+		requests:       map[jsonrpc2.ID]*queuedMessage{},
+		ready:          make(chan struct{}, 1),
+	}
+}
+
+// Handle enqueues the message, or cancels the request or the progress immediately.
+func (q *queue) Handle(ctx context.Context, conn *jsonrpc2.Conn, req *jsonrpc2.Request) {
+	switch req.Method {
+	case "$/cancelRequest":
+		q.cancelRequest(req)
+		return
+	case "window/workDoneProgress/cancel":
+		q.cancelProgress(req)
+		return
+	}
+
+	msg := &queuedMessage{ctx: ctx, req: req}
+	q.mu.Lock()
+	// Notifications cannot be cancelled
+	if !req.Notif {
+		msg.ctx, msg.cancel = context.WithCancel(ctx)
+		q.requests[req.ID] = msg
+	}
+	q.messages = append(q.messages, msg)
+	q.mu.Unlock()
+
+	q.start.Do(func() { go q.run(conn) })
+	select {
+	case q.ready <- struct{}{}:
+	default:
+	}
+}
+
+// run handles the queued messages until the connection is closed.
+func (q *queue) run(conn *jsonrpc2.Conn) {
+	for {
+		select {
+		case <-q.ready:
+		case <-conn.DisconnectNotify():
+			return
+		}
+
+		for {
+			q.mu.Lock()
+			if len(q.messages) == 0 {
+				q.mu.Unlock()
+				break
+			}
+			msg := q.messages[0]
+			q.messages = q.messages[1:]
+			q.mu.Unlock()
+
+			q.handler.Handle(msg.ctx, conn, msg.req)
+
+			if !msg.req.Notif {
+				msg.cancel()
+				q.mu.Lock()
+				// The ID may have been reused by a later request
+				if q.requests[msg.req.ID] == msg {
+					delete(q.requests, msg.req.ID)
+				}
+				q.mu.Unlock()
+			}
+		}
+	}
+}
+
+// cancelRequest cancels the context of the request.
+// Requests that have already been handled are ignored.
+func (q *queue) cancelRequest(req *jsonrpc2.Request) {
+	if req.Params == nil {
+		return
+	}
+	var params cancelParams
+	if err := json.Unmarshal(*req.Params, &params); err != nil {
+		log.Printf("Failed to parse $/cancelRequest: %s", err)
+		return
+	}
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if msg, exists := q.requests[params.ID]; exists {
+		log.Printf("Cancel request %s", params.ID)
+		msg.cancel()
+	}
+}
@@ -0,0 +1,70 @@
+package langserver
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/sourcegraph/jsonrpc2"
+)
+
+func Test_queue_cancelRequest(t *testing.T) {
+	started := make(chan struct{})
+	handled := []string{}
+	q := newQueue(jsonrpc2.HandlerWithError(func(ctx context.Context, conn *jsonrpc2.Conn, req *jsonrpc2.Request) (any, error) {
+		handled = append(handled, req.Method)
+		if req.Method != "slow" {
+			return "ok", nil
+		}
+		// Blocks until cancelled, like an inspection with a slow plugin
+		close(started)
+		select {
+		case <-ctx.Done():
+			return nil, requestCancelled()
+		case <-time.After(10 * time.Second):
+			return "timeout", nil
+		}
+	}), func(req *jsonrpc2.Request) {})
+
+	server, client := net.Pipe()
+	serverConn := jsonrpc2.NewConn(context.Background(), jsonrpc2.NewBufferedStream(server, jsonrpc2.VSCodeObjectCodec{}), q)
+	defer serverConn.Close()
+	clientConn := jsonrpc2.NewConn(context.Background(), jsonrpc2.NewBufferedStream(client, jsonrpc2.VSCodeObjectCodec{}), jsonrpc2.HandlerWithError(func(context.Context, *jsonrpc2.Conn, *jsonrpc2.Request) (any, error) {
+		return nil, nil
+	}))
+	defer clientConn.Close()
+
+	slow := make(chan error)
+	go func() {
+		var result string
+		slow <- clientConn.Call(context.Background(), "slow", nil, &result, jsonrpc2.PickID(jsonrpc2.ID{Num: 1}))
+	}()
+	<-started
+
+	// The next request waits until the slow request is cancelled
+	fast := make(chan string)
+	go func() {
+		var result string
+		if err := clientConn.Call(context.Background(), "fast", nil, &result, jsonrpc2.PickID(jsonrpc2.ID{Num: 2})); err != nil {
+			t.Error(err)
+		}
+		fast <- result
+	}()
+
+	if err := clientConn.Notify(context.Background(), "$/cancelRequest", cancelParams{ID: jsonrpc2.ID{Num: 1}}); err != nil {
+		t.Fatal(err)
+	}
+
+	var rpcErr *jsonrpc2.Error
+	if err := <-slow; !errors.As(err, &rpcErr) || rpcErr.Code != codeRequestCancelled {
+		t.Fatalf("expected the request to be cancelled, but got %v", err)
+	}
+	if got := <-fast; got != "ok" {
+		t.Fatalf("expected ok, but got %s", got)
+	}
+	if len(handled) != 2 || handled[0] != "slow" || handled[1] != "fast" {
+		t.Fatalf("expected requests to be handled in order, but got %v", handled)
+	}
+}
@@ -0,0 +1,539 @@
+package langserver
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/go-version"
+	"github.com/hashicorp/hcl/v2"
+	lsp "github.com/sourcegraph/go-lsp"
+	"github.com/sourcegraph/jsonrpc2"
+	"github.com/spf13/afero"
+	"github.com/terraform-linters/tflint-plugin-sdk/hclext"
+	"github.com/terraform-linters/tflint/plugin"
+	"github.com/terraform-linters/tflint/terraform"
+	"github.com/terraform-linters/tflint/tflint"
+)
+
+// root is a root module in the workspace. Each root has its own config, plugins, and loader state,
+// and resolves paths relative to its directory instead of the current directory.
+// Inspections of different roots can run concurrently.
+type root struct {
+	h   *handler
+	dir string
+
+	configFile        string
+	config            *tflint.Config
+	plugin            *plugin.Plugin
+	clientSDKVersions map[string]*version.Version
+	// fs is the filesystem of the root directory, overlaid by open documents.
+	// Relative paths are resolved against the root directory.
+	fs afero.Fs
+	// diagsPaths are the paths where diagnostics were published.
+	diagsPaths []string
+	// issues are the issues found by the last inspection, keyed by absolute paths.
+	issues map[string]tflint.Issues
+	// runners are the runners of the last inspection. They are used to evaluate expressions in hovers and inlay hints.
+	runners []*tflint.Runner
+	// runnerIssues are the issues found by each runner in the last inspection, keyed by module paths.
+	// They are kept for runners that are not affected by edits. nil means that all runners need to be checked.
+	runnerIssues map[string]tflint.Issues
+	// editedPaths are the paths edited since the last inspection.
+	// Inspections after edits check only the runners of the modules containing them.
+	editedPaths map[string]bool
+	// changes are the sources fixed by autofixes, keyed by absolute paths.
+	// They are computed on demand and discarded on every inspection.
+	changes map[string][]byte
+	// cache retains parsed files and called modules across inspections.
+	cache *terraform.ParseCache
+	// pluginFix is whether the config was applied to plugins with autofix enabled.
+	// nil means that the config needs to be applied.
+	pluginFix *bool
+	// configDiags are the errors of the config file that failed to be loaded, keyed by absolute paths.
+	configDiags map[string][]diagnostic
+	// rulePlugins are the names of the plugins that provide rules, keyed by rule names.
+	// They are fetched from plugins on demand.
+	rulePlugins map[string]string
+
+	// mu serializes requests and debounced inspections of the root, since they share
+	// the root state and the overlay filesystem.
+	mu sync.Mutex
+	// pending is the timer of the inspection scheduled by the last edit, if it has not run yet.
+	pending *time.Timer
+
+	// cancelMu guards cancel, which is called without holding mu to abort the running inspection.
+	cancelMu sync.Mutex
+	cancel   context.CancelFunc
+}
+
+// newRoot loads the config and launches plugins for the root module in the directory.
+// If the config is invalid, the root is returned with the error, and it is not inspected until the config is fixed.
+// conn is nil while the server is starting.
+func (h *handler) newRoot(conn *jsonrpc2.Conn, dir string) (*root, error) {
+	log.Printf("Loading root module: %s", dir)
+
+	r := &root{
+		h:           h,
+		dir:         dir,
+		plugin:      &plugin.Plugin{},
+		issues:      map[string]tflint.Issues{},
+		cache:       terraform.NewParseCache(),
+		configDiags: map[string][]diagnostic{},
+	}
+	if err := r.resetOverlay(); err != nil {
+		return nil, err
+	}
+
+	cfg, configFile, rulsetPlugin, clientSDKVersions, err := r.load(conn)
+	r.configFile = configFile
+	if err != nil {
+		return r, err
+	}
+	r.config = cfg
+	r.plugin = rulsetPlugin
+	r.clientSDKVersions = clientSDKVersions
+
+	return r, nil
+}
+
+// load loads the config and launches plugins.
+// The config file is resolved relative to the root directory.
+// Failures to launch plugins are also shown to the user, since they are often not placed in the config file.
+func (r *root) load(conn *jsonrpc2.Conn) (*tflint.Config, string, *plugin.Plugin, map[string]*version.Version, error) {
+	fs := afero.Afero{Fs: newDirFs(afero.NewOsFs(), r.dir)}
+	cfg, err := loadConfig(fs, r.h.configPathFor(r.dir), r.h.cliConfig)
+	if err != nil {
+		return nil, "", nil, nil, err
+	}
+	resolvePluginDir(cfg, r.dir)
+
+	configFile := absConfigPath(cfg, r.dir)
+	progress := r.h.beginProgress(conn, "Launching plugins", nil)
+	rulsetPlugin, clientSDKVersions, err := launchPlugins(cfg, r.h.cliConfig)
+	progress.end()
+	if err != nil {
+		r.h.showMessage(conn, lsp.MTError, fmt.Sprintf("Failed to launch plugins for %s: %s", r.dir, err))
+		return nil, configFile, nil, nil, err
+	}
+	return cfg, configFile, rulsetPlugin, clientSDKVersions, nil
+}
+
+// resolvePluginDir resolves the plugin directory relative to the root directory.
+// Otherwise, the local plugin directory (./.tflint.d/plugins) is looked up in the current directory.
+func resolvePluginDir(cfg *tflint.Config, dir string) {
+	if cfg.PluginDir != "" {
+		if !filepath.IsAbs(cfg.PluginDir) && cfg.PluginDir[0] != '~' {
+			cfg.PluginDir = filepath.Join(dir, cfg.PluginDir)
+		}
+		return
+	}
+	if os.Getenv("TFLINT_PLUGIN_DIR") != "" {
+		return
+	}
+
+	local := filepath.Join(dir, ".tflint.d", "plugins")
+	if _, err := os.Stat(local); err == nil {
+		cfg.PluginDir = local
+	} else {
+		cfg.PluginDir = plugin.PluginRoot
+	}
+}
+
+// close kills plugins and discards the pending inspection.
+func (r *root) close() {
+	r.cancelInspection()
+	r.discardInspection()
+	r.plugin.Clean()
+}
+
+// resetOverlay rebuilds the overlay filesystem from the open documents under the root directory.
+// Relative paths are resolved against the root directory, so documents are written with absolute paths.
+func (r *root) resetOverlay() error {
+	r.fs = newDirFs(afero.NewCopyOnWriteFs(afero.NewOsFs(), afero.NewMemMapFs()), r.dir)
+	for path, src := range r.h.documents {
+		if err := r.writeDocument(path, src); err != nil {
+			return fmt.Errorf("Failed to synchronize %s: %s", path, err)
+		}
+	}
+	return nil
+}
+
+// writeDocument writes the open document to the overlay if it is under the root directory.
+func (r *root) writeDocument(path string, src []byte) error {
+	if _, ok := relPath(r.dir, path); !ok {
+		return nil
+	}
+	return afero.WriteFile(r.fs, path, src, os.ModePerm)
+}
+
+// reinspect inspects the root module again with the files changed on disk.
+// Root modules are not inspected until a document in the directory is opened.
+func (r *root) reinspect(ctx context.Context, conn *jsonrpc2.Conn) error {
+	if !r.active() {
+		return nil
+	}
+	// Files changed on disk are read again unless they are open
+	if err := r.resetOverlay(); err != nil {
+		return err
+	}
+	// The pending inspection is superseded by the following inspection
+	r.discardInspection()
+
+	return r.runInspection(ctx, conn)
+}
+
+// markEdited records the edited path, so that the module containing it is checked in the next inspection.
+func (r *root) markEdited(path string) {
+	if r.editedPaths == nil {
+		r.editedPaths = map[string]bool{}
+	}
+	r.editedPaths[path] = true
+}
+
+// scheduleInspection schedules an inspection of the edited paths after the debounce delay.
+// The inspection scheduled by the previous edit is superseded.
+func (r *root) scheduleInspection(conn *jsonrpc2.Conn) {
+	if r.pending != nil {
+		r.pending.Stop()
+	}
+
+	var timer *time.Timer
+	timer = time.AfterFunc(r.h.debounce, func() {
+		r.mu.Lock()
+		defer r.mu.Unlock()
+
+		// The inspection has already been flushed or superseded
+		if r.pending != timer {
+			return
+		}
+		if err := r.flushInspection(context.Background(), conn); err != nil {
+			log.Printf("Failed to inspect: %s", err)
+		}
+	})
+	r.pending = timer
+}
+
+// flushInspection runs the pending inspection immediately, if any.
+// This is used before handling requests that depend on the results of the last edit.
+// If the request is cancelled, the inspection is scheduled again, since the edit has not been inspected yet.
+func (r *root) flushInspection(ctx context.Context, conn *jsonrpc2.Conn) error {
+	if r.pending == nil {
+		return nil
+	}
+	r.pending.Stop()
+	r.pending = nil
+
+	err := r.runEditedInspection(ctx, conn)
+	if ctx.Err() != nil {
+		r.scheduleInspection(conn)
+	}
+	return err
+}
+
+// discardInspection discards the pending inspection, if any.
+func (r *root) discardInspection() {
+	if r.pending != nil {
+		r.pending.Stop()
+		r.pending = nil
+	}
+}
+
+// runInspection inspects all modules of the root module and publishes diagnostics.
+// The inspection can be cancelled by the context or cancelInspection, and then the results are discarded.
+func (r *root) runInspection(ctx context.Context, conn *jsonrpc2.Conn) error {
+	// Issues kept from the last inspection may be outdated by changes other than edits
+	r.runnerIssues = nil
+	return r.runEditedInspection(ctx, conn)
+}
+
+// runEditedInspection inspects the modules containing the edited paths and publishes diagnostics,
+// together with the issues of other modules found by the last inspection.
+// If the edited paths are not inspected due to cancellation, they remain for the next inspection.
+func (r *root) runEditedInspection(ctx context.Context, conn *jsonrpc2.Conn) error {
+	return r.cancellable(ctx, func(ctx context.Context) error {
+		return r.inspectAndPublish(ctx, conn)
+	})
+}
+
+// cancellable runs the inspection with a context that can also be cancelled by cancelInspection.
+// Cancellation is not regarded as an error because the results are superseded by the next inspection.
+func (r *root) cancellable(ctx context.Context, inspect func(ctx context.Context) error) error {
+	ctx, cancel := context.WithCancel(ctx)
+	r.cancelMu.Lock()
+	r.cancel = cancel
+	r.cancelMu.Unlock()
+
+	defer func() {
+		r.cancelMu.Lock()
+		r.cancel = nil
+		r.cancelMu.Unlock()
+		cancel()
+	}()
+
+	err := inspect(ctx)
+	if errors.Is(err, context.Canceled) {
+		log.Print("Inspection cancelled")
+		return nil
+	}
+	return err
+}
+
+// cancelInspection cancels the running inspection, if any.
+// This can be called without holding mu.
+func (r *root) cancelInspection() {
+	r.cancelMu.Lock()
+	defer r.cancelMu.Unlock()
+
+	if r.cancel != nil {
+		r.cancel()
+	}
+}
+
+// inspectAndPublish inspects the root module and publishes diagnostics.
+// If the client pulls diagnostics, it is asked to refresh them instead.
+func (r *root) inspectAndPublish(ctx context.Context, conn *jsonrpc2.Conn) error {
+	diagnostics, err := r.inspect(ctx, conn)
+	if err != nil {
+		return err
+	}
+
+	if r.h.pullDiagnostics {
+		return r.h.refreshDiagnostics(ctx, conn)
+	}
+	return r.h.publishDiagnostics(ctx, conn, diagnostics)
+}
+
+// inspected returns whether the last edit has already been inspected.
+func (r *root) inspected() bool {
+	return r.diagsPaths != nil && r.pending == nil
+}
+
+// active returns whether the root module has been inspected or is about to be inspected.
+// Root modules are not inspected until a document in the directory is opened.
+func (r *root) active() bool {
+	return r.diagsPaths != nil || r.pending != nil
+}
+
+// inspect checks the root module and returns diagnostics for each file.
+// If the config failed to be loaded, the root module is not inspected.
+// If the context is cancelled, the results are discarded.
+func (r *root) inspect(ctx context.Context, conn *jsonrpc2.Conn) (map[string][]diagnostic, error) {
+	ret := map[string][]diagnostic{}
+	if r.config == nil {
+		// Mark as inspected, so that the root module is inspected once the config is fixed
+		if r.diagsPaths == nil {
+			r.diagsPaths = []string{}
+		}
+		return ret, nil
+	}
+
+	// Fixes computed from the previous sources are no longer valid
+	r.changes = nil
+
+	runners, err := r.check(ctx, conn, false, r.affected)
+	if err != nil {
+		return ret, err
+	}
+	if err := ctx.Err(); err != nil {
+		return ret, err
+	}
+
+	runnerIssues := map[string]tflint.Issues{}
+	for _, runner := range runners {
+		if r.affected(runner) {
+			runnerIssues[runner.ModulePath()] = append(runnerIssues[runner.ModulePath()], runner.LookupIssues()...)
+		} else {
+			runnerIssues[runner.ModulePath()] = r.runnerIssues[runner.ModulePath()]
+		}
+	}
+	r.runnerIssues = runnerIssues
+	r.editedPaths = nil
+
+	// In order to publish that the issue has been fixed,
+	// notify also the path where the past diagnostics were published.
+	for _, path := range r.diagsPaths {
+		ret[path] = []diagnostic{}
+	}
+	r.issues = map[string]tflint.Issues{}
+	r.runners = runners
+	diagsPaths := []string{}
+
+	for _, modulePath := range slices.Sorted(maps.Keys(runnerIssues)) {
+		for _, issue := range runnerIssues[modulePath] {
+			path := filepath.Join(r.dir, issue.Range.Filename)
+			diagsPaths = append(diagsPaths, path)
+			r.issues[path] = append(r.issues[path], issue)
+
+			diag := toLSPDiagnostic(issue)
+
+			if ret[path] == nil {
+				ret[path] = []diagnostic{diag}
+			} else {
+				ret[path] = append(ret[path], diag)
+			}
+		}
+	}
+	r.diagsPaths = diagsPaths
+
+	return ret, nil
+}
+
+// fix returns the sources fixed by autofixes, keyed by absolute paths.
+// The result is cached until the next inspection.
+func (r *root) fix(ctx context.Context, conn *jsonrpc2.Conn) (map[string][]byte, error) {
+	if r.changes != nil {
+		return r.changes, nil
+	}
+
+	runners, err := r.check(ctx, conn, true, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	changes := map[string][]byte{}
+	for _, runner := range runners {
+		for path, source := range runner.LookupChanges() {
+			changes[filepath.Join(r.dir, path)] = source
+		}
+	}
+	r.changes = changes
+
+	return changes, nil
+}
+
+// affected returns whether the runner needs to be checked in the inspection.
+// After edits, only the runners of the modules containing the edited files and their descendants are checked,
+// and other runners keep the issues found by the last inspection until a full inspection (e.g. on save).
+// Edits of files other than Terraform configuration files (e.g. values files) may affect all modules.
+func (r *root) affected(runner *tflint.Runner) bool {
+	if r.runnerIssues == nil || r.editedPaths == nil {
+		return true
+	}
+	if _, exists := r.runnerIssues[runner.ModulePath()]; !exists {
+		// Module calls added by the edits have never been checked
+		return true
+	}
+
+	// Issues in called modules are reported at the module call arguments in the parent modules,
+	// so edits of the ancestor modules also affect the runner.
+	cfg := runner.TFConfig.Root
+	dirs := map[string]bool{filepath.Join(r.dir, cfg.Module.SourceDir): true}
+	for _, name := range runner.TFConfig.Path {
+		if cfg = cfg.Children[name]; cfg == nil {
+			break
+		}
+		dirs[filepath.Join(r.dir, cfg.Module.SourceDir)] = true
+	}
+	for path := range r.editedPaths {
+		if !strings.HasSuffix(path, ".tf") && !strings.HasSuffix(path, ".tf.json") {
+			return true
+		}
+		if dirs[filepath.Dir(path)] {
+			return true
+		}
+	}
+	return false
+}
+
+// check runs all rulesets against the root module and returns runners with the results.
+// If target is given, only runners for which it returns true are checked. Otherwise, all runners are checked.
+// If fix is true, autofixes are applied to the in-memory modules of the runners.
+// If the context is cancelled, the check is aborted and the context error is returned.
+// The progress is reported to the client, which can cancel the check via the progress.
+func (r *root) check(ctx context.Context, conn *jsonrpc2.Conn, fix bool, target func(*tflint.Runner) bool) ([]*tflint.Runner, error) {
+	progress := r.h.beginProgress(conn, "Loading modules", r.cancelInspection)
+	defer progress.end()
+
+	loader, err := terraform.NewLoaderWithCache(afero.Afero{Fs: r.fs}, r.dir, r.cache)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to prepare loading: %w", err)
+	}
+
+	runner, runners, err := tflint.BuildRunners(ctx, loader, r.config, r.dir, ".")
+	if err != nil {
+		return nil, err
+	}
+	runners = append(runners, runner) // langserver iterates a single slice incl. root
+
+	targets := runners
+	if target != nil {
+		targets = slices.DeleteFunc(slices.Clone(runners), func(runner *tflint.Runner) bool { return !target(runner) })
+	}
+
+	if err := r.applyPluginConfig(fix); err != nil {
+		return nil, err
+	}
+
+	// Rulesets are run in the order of their names, so that the progress is reported consistently
+	names := make([]string, 0, len(r.plugin.RuleSets))
+	for name := range r.plugin.RuleSets {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for i, name := range names {
+		progress.report(fmt.Sprintf(`Running "%s" ruleset`, name), i*100/len(names))
+
+		for _, runner := range targets {
+			if err := ctx.Err(); err != nil {
+				return nil, err
+			}
+			err = r.plugin.RuleSets[name].Check(plugin.NewGRPCServer(ctx, runner, runners[len(runners)-1], loader.Files(), r.clientSDKVersions[name]))
+			if err := ctx.Err(); err != nil {
+				// Plugins fail to check once the context is cancelled
+				return nil, err
+			}
+			if err != nil {
+				r.h.showMessage(conn, lsp.MTError, fmt.Sprintf(`Failed to check "%s" ruleset: %s`, name, err))
+				return nil, fmt.Errorf("Failed to check ruleset: %w", err)
+			}
+		}
+	}
+
+	return runners, nil
+}
+
+// applyPluginConfig applies the config to plugins.
+// This is skipped if the config has already been applied with the same autofix mode.
+func (r *root) applyPluginConfig(fix bool) error {
+	if r.pluginFix != nil && *r.pluginFix == fix {
+		return nil
+	}
+
+	config := r.config.ToPluginConfig()
+	config.Fix = fix
+	for name, ruleset := range r.plugin.RuleSets {
+		if err := ruleset.ApplyGlobalConfig(config); err != nil {
+			return fmt.Errorf(`Failed to apply global config to "%s" plugin`, name)
+		}
+		configSchema, err := ruleset.ConfigSchema()
+		if err != nil {
+			return fmt.Errorf(`Failed to fetch config schema from "%s" plugin`, name)
+		}
+		content := &hclext.BodyContent{}
+		if plugin, exists := r.config.Plugins[name]; exists {
+			var diags hcl.Diagnostics
+			content, diags = plugin.Content(configSchema)
+			if diags.HasErrors() {
+				return fmt.Errorf(`Failed to parse "%s" plugin config`, name)
+			}
+		}
+		err = ruleset.ApplyConfig(content, r.config.Sources())
+		if err != nil {
+			return fmt.Errorf(`Failed to apply config to "%s" plugin`, name)
+		}
+	}
+	r.pluginFix = &fix
+
+	return nil
+}
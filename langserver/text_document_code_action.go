@@ -0,0 +1,184 @@
+package langserver
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+
+	lsp "github.com/sourcegraph/go-lsp"
+	"github.com/sourcegraph/jsonrpc2"
+	"github.com/spf13/afero"
+	"github.com/terraform-linters/tflint/tflint"
+)
+
+// codeActionKindSourceFixAll is the kind of code actions that fix all issues in a file.
+// This kind is not defined in go-lsp because it was added in LSP 3.15.
+const codeActionKindSourceFixAll lsp.CodeActionKind = "source.fixAll"
+
+// codeAction is a code action that applies a workspace edit.
+// go-lsp only defines the Command type, which requires the client to execute a command on the server.
+type codeAction struct {
+	Title       string             `json:"title"`
+	Kind        lsp.CodeActionKind `json:"kind,omitempty"`
+	Diagnostics []diagnostic       `json:"diagnostics,omitempty"`
+	IsPreferred bool               `json:"isPreferred,omitempty"`
+	Edit        *lsp.WorkspaceEdit `json:"edit,omitempty"`
+}
+
+// codeActionParams is the same as lsp.CodeActionParams, but uses diagnostic.
+type codeActionParams struct {
+	TextDocument lsp.TextDocumentIdentifier `json:"textDocument"`
+	Range        lsp.Range                  `json:"range"`
+	Context      codeActionContext          `json:"context"`
+}
+
+type codeActionContext struct {
+	Diagnostics []diagnostic `json:"diagnostics"`
+}
+
+func (h *handler) textDocumentCodeAction(ctx context.Context, conn *jsonrpc2.Conn, req *jsonrpc2.Request) (result any, err error) {
+	if req.Params == nil {
+		return nil, &jsonrpc2.Error{
+			Code:    jsonrpc2.CodeInvalidRequest,
+			Message: "request params are nil",
+		}
+	}
+
+	var params codeActionParams
+	if err := json.Unmarshal(*req.Params, &params); err != nil {
+		return nil, &jsonrpc2.Error{
+			Code:    jsonrpc2.CodeParseError,
+			Message: err.Error(),
+			Data:    req.Params,
+		}
+	}
+
+	path, err := uriToPath(params.TextDocument.URI)
+	if err != nil {
+		return nil, err
+	}
+	r, exists := h.roots[filepath.Dir(path)]
+	// No actions are available until the root module is loaded with a valid config
+	if !exists || r.config == nil {
+		return []codeAction{}, nil
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	// Code actions are based on the results of the last edit
+	if err := r.flushInspection(ctx, conn); err != nil {
+		return nil, err
+	}
+
+	src, err := afero.ReadFile(r.fs, path)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to read %s: %s", path, err)
+	}
+
+	quickFixes, fixAll, err := r.autofixActions(ctx, conn, path, src, params)
+	if err != nil {
+		return nil, err
+	}
+	ignores, err := r.ignoreActions(path, src, params)
+	if err != nil {
+		return nil, err
+	}
+
+	actions := append(quickFixes, ignores...)
+	if fixAll != nil {
+		actions = append(actions, *fixAll)
+	}
+	return actions, nil
+}
+
+// autofixActions returns quick fixes for fixable issues in the requested range,
+// and a source action to fix all issues in the file.
+func (r *root) autofixActions(ctx context.Context, conn *jsonrpc2.Conn, path string, src []byte, params codeActionParams) ([]codeAction, *codeAction, error) {
+	actions := []codeAction{}
+
+	fixables := tflint.Issues{}
+	for _, issue := range r.issues[path] {
+		if issue.Fixable {
+			fixables = append(fixables, issue)
+		}
+	}
+	if len(fixables) == 0 {
+		return actions, nil, nil
+	}
+
+	changes, err := r.fix(ctx, conn)
+	if err != nil {
+		return nil, nil, err
+	}
+	fixed, exists := changes[path]
+	if !exists {
+		return actions, nil, nil
+	}
+	hunks := tflint.DiffHunks(src, fixed)
+	if len(hunks) == 0 {
+		return actions, nil, nil
+	}
+
+	// Autofixes are applied per file, so changes overlapping with the issue are regarded as its fix.
+	for _, issue := range fixables.Sort() {
+		diag := toLSPDiagnostic(issue)
+		if !rangesOverlap(diag.Range, params.Range) {
+			continue
+		}
+
+		edits := []lsp.TextEdit{}
+		for _, hunk := range hunks {
+			if hunk.Overlaps(issue.Range) {
+				edits = append(edits, toLSPTextEdit(hunk))
+			}
+		}
+		if len(edits) == 0 {
+			continue
+		}
+
+		actions = append(actions, codeAction{
+			Title:       fmt.Sprintf("Fix %s", issue.Rule.Name()),
+			Kind:        lsp.CAKQuickFix,
+			Diagnostics: []diagnostic{diag},
+			IsPreferred: true,
+			Edit: &lsp.WorkspaceEdit{
+				Changes: map[string][]lsp.TextEdit{string(params.TextDocument.URI): edits},
+			},
+		})
+	}
+
+	edits := make([]lsp.TextEdit, len(hunks))
+	for i, hunk := range hunks {
+		edits[i] = toLSPTextEdit(hunk)
+	}
+	fixAll := &codeAction{
+		Title: "Fix all auto-fixable issues",
+		Kind:  codeActionKindSourceFixAll,
+		Edit: &lsp.WorkspaceEdit{
+			Changes: map[string][]lsp.TextEdit{string(params.TextDocument.URI): edits},
+		},
+	}
+
+	return actions, fixAll, nil
+}
+
+func toLSPTextEdit(hunk tflint.Hunk) lsp.TextEdit {
+	return lsp.TextEdit{
+		Range: lsp.Range{
+			Start: lsp.Position{Line: hunk.Start, Character: 0},
+			End:   lsp.Position{Line: hunk.End, Character: 0},
+		},
+		NewText: hunk.Text,
+	}
+}
+
+// rangesOverlap returns whether the ranges overlap. Ranges that touch each other are also regarded as overlapped,
+// so that a cursor at the end of a diagnostic can trigger its quick fix.
+func rangesOverlap(a, b lsp.Range) bool {
+	return !positionBefore(a.End, b.Start) && !positionBefore(b.End, a.Start)
+}
+
+func positionBefore(a, b lsp.Position) bool {
+	return a.Line < b.Line || (a.Line == b.Line && a.Character < b.Character)
+}
@@ -0,0 +1,263 @@
+package langserver
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/gohcl"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	lsp "github.com/sourcegraph/go-lsp"
+	"github.com/sourcegraph/jsonrpc2"
+	"github.com/terraform-linters/tflint/tflint"
+)
+
+func (h *handler) textDocumentCompletion(ctx context.Context, conn *jsonrpc2.Conn, req *jsonrpc2.Request) (result any, err error) {
+	if req.Params == nil {
+		return nil, &jsonrpc2.Error{
+			Code:    jsonrpc2.CodeInvalidRequest,
+			Message: "request params are nil",
+		}
+	}
+
+	var params lsp.CompletionParams
+	if err := json.Unmarshal(*req.Params, &params); err != nil {
+		return nil, &jsonrpc2.Error{
+			Code:    jsonrpc2.CodeParseError,
+			Message: err.Error(),
+			Data:    req.Params,
+		}
+	}
+
+	path, err := uriToPath(params.TextDocument.URI)
+	if err != nil {
+		return nil, err
+	}
+	src, opened := h.documents[path]
+	// Completion is available only for config files in the native syntax
+	if !opened || !h.isConfigDocument(path) || filepath.Ext(path) == ".json" {
+		return []lsp.CompletionItem{}, nil
+	}
+
+	// Rules and plugin configs are completed from the plugins of the root module that loads the config file
+	r := h.configRoot(path)
+	if r != nil {
+		r.mu.Lock()
+		defer r.mu.Unlock()
+	}
+	return configCompletion(r, src, params.Position)
+}
+
+// completionContext is the context of the position in the config file where completion is requested.
+type completionContext struct {
+	// block is the header of the innermost block containing the position, e.g. ["plugin", "aws"].
+	// Empty at the top level.
+	block []string
+	// inBlock is whether the position is directly in the block body, not in an expression.
+	inBlock bool
+	// ruleLabel is whether the position is in the label of a "rule" block.
+	ruleLabel bool
+	// prefix is the text already typed before the position, which is replaced by the completion.
+	prefix string
+}
+
+// configCompletion returns completion items at the position in the config file.
+// Rule names are completed in labels of "rule" blocks, and attributes are completed in "config", "plugin", and "rule" blocks.
+// If the root module is nil, only items that do not depend on plugins are returned.
+func configCompletion(r *root, src []byte, pos lsp.Position) ([]lsp.CompletionItem, error) {
+	cc := completionContextAt(src, positionToOffset(src, pos))
+	replace := lsp.Range{
+		Start: lsp.Position{Line: pos.Line, Character: pos.Character - utf8.RuneCountInString(cc.prefix)},
+		End:   pos,
+	}
+
+	items := []lsp.CompletionItem{}
+	if cc.ruleLabel {
+		if r == nil {
+			return items, nil
+		}
+		rulePlugins, err := r.loadRulePlugins()
+		if err != nil {
+			return nil, err
+		}
+		rules := make([]string, 0, len(rulePlugins))
+		for rule := range rulePlugins {
+			rules = append(rules, rule)
+		}
+		sort.Strings(rules)
+
+		for _, rule := range rules {
+			items = append(items, lsp.CompletionItem{
+				Label:    rule,
+				Kind:     lsp.CIKValue,
+				Detail:   fmt.Sprintf(`"%s" plugin`, rulePlugins[rule]),
+				TextEdit: &lsp.TextEdit{Range: replace, NewText: rule},
+			})
+		}
+		return items, nil
+	}
+
+	if !cc.inBlock || len(cc.block) == 0 {
+		return items, nil
+	}
+
+	var attributes, pluginAttributes []hcl.AttributeSchema
+	switch cc.block[0] {
+	case "config":
+		for _, name := range tflint.ConfigAttributes() {
+			attributes = append(attributes, hcl.AttributeSchema{Name: name})
+		}
+	case "rule":
+		schema, _ := gohcl.ImpliedBodySchema(&tflint.RuleConfig{})
+		attributes = schema.Attributes
+	case "plugin":
+		schema, _ := gohcl.ImpliedBodySchema(&tflint.PluginConfig{})
+		attributes = schema.Attributes
+
+		// Attributes specific to the plugin are available only if the plugin is running
+		if len(cc.block) > 1 && r != nil {
+			if ruleset, exists := r.plugin.RuleSets[cc.block[1]]; exists {
+				configSchema, err := ruleset.ConfigSchema()
+				if err != nil {
+					return nil, fmt.Errorf(`Failed to fetch config schema from "%s" plugin: %w`, cc.block[1], err)
+				}
+				if configSchema != nil {
+					for _, attr := range configSchema.Attributes {
+						pluginAttributes = append(pluginAttributes, hcl.AttributeSchema{Name: attr.Name, Required: attr.Required})
+					}
+				}
+			}
+		}
+	default:
+		return items, nil
+	}
+
+	items = append(items, attributeItems(attributes, "", replace)...)
+	if len(pluginAttributes) > 0 {
+		items = append(items, attributeItems(pluginAttributes, fmt.Sprintf(`"%s" plugin`, cc.block[1]), replace)...)
+	}
+	return items, nil
+}
+
+// attributeItems returns completion items inserting the attribute names.
+// Required attributes are marked in the details.
+func attributeItems(attributes []hcl.AttributeSchema, detail string, replace lsp.Range) []lsp.CompletionItem {
+	items := make([]lsp.CompletionItem, len(attributes))
+	for i, attr := range attributes {
+		items[i] = lsp.CompletionItem{
+			Label:    attr.Name,
+			Kind:     lsp.CIKProperty,
+			Detail:   detail,
+			TextEdit: &lsp.TextEdit{Range: replace, NewText: attr.Name + " = "},
+		}
+		if attr.Required {
+			items[i].Detail = strings.TrimPrefix(detail+", required", ", ")
+		}
+	}
+	return items
+}
+
+// completionContextAt returns the context of the offset in the config file.
+// The config file is tokenized instead of parsed, since it is often incomplete while typing.
+func completionContextAt(src []byte, offset int) completionContext {
+	tokens, _ := hclsyntax.LexConfig(src[:offset], "", hcl.InitialPos)
+
+	// frames are the headers of open blocks and brackets. Brackets in expressions have nil headers.
+	var frames [][]string
+	var line hclsyntax.Tokens
+	for _, token := range tokens {
+		switch token.Type {
+		case hclsyntax.TokenOBrace:
+			frames = append(frames, blockHeader(line))
+			line = nil
+			continue
+		case hclsyntax.TokenOBrack, hclsyntax.TokenOParen, hclsyntax.TokenTemplateInterp, hclsyntax.TokenTemplateControl:
+			frames = append(frames, nil)
+			continue
+		case hclsyntax.TokenCBrace, hclsyntax.TokenCBrack, hclsyntax.TokenCParen, hclsyntax.TokenTemplateSeqEnd:
+			if len(frames) > 0 {
+				frames = frames[:len(frames)-1]
+			}
+			line = nil
+			continue
+		case hclsyntax.TokenNewline:
+			line = nil
+			continue
+		case hclsyntax.TokenEOF:
+			continue
+		}
+		line = append(line, token)
+	}
+
+	cc := completionContext{}
+	if len(frames) > 0 {
+		cc.block = frames[len(frames)-1]
+	}
+	blockBody := len(frames) == 0 || cc.block != nil
+
+	switch {
+	case len(line) == 0:
+		cc.inBlock = blockBody
+	case len(line) == 1 && line[0].Type == hclsyntax.TokenIdent && line[0].Range.End.Byte == offset:
+		cc.inBlock = blockBody
+		cc.prefix = string(line[0].Bytes)
+	case blockBody && len(line) >= 2 && string(line[0].Bytes) == "rule" && line[1].Type == hclsyntax.TokenOQuote:
+		// The label is being typed if the quote is not closed yet
+		switch {
+		case len(line) == 2:
+			cc.ruleLabel = true
+		case len(line) == 3 && line[2].Type == hclsyntax.TokenQuotedLit:
+			cc.ruleLabel = true
+			cc.prefix = string(line[2].Bytes)
+		}
+	}
+	return cc
+}
+
+// blockHeader returns the type and labels of the block if the tokens are a block header.
+// Returns nil if the tokens are not a block header, e.g. an attribute with an object value.
+func blockHeader(tokens hclsyntax.Tokens) []string {
+	if len(tokens) == 0 || tokens[0].Type != hclsyntax.TokenIdent {
+		return nil
+	}
+
+	header := []string{string(tokens[0].Bytes)}
+	for _, token := range tokens[1:] {
+		switch token.Type {
+		case hclsyntax.TokenOQuote, hclsyntax.TokenCQuote:
+		case hclsyntax.TokenQuotedLit, hclsyntax.TokenIdent:
+			header = append(header, string(token.Bytes))
+		default:
+			return nil
+		}
+	}
+	return header
+}
+
+// positionToOffset converts the position to the byte offset in the source.
+// As with diagnostics, characters are counted instead of UTF-16 code units.
+// Positions beyond the end of lines or the source are clamped.
+func positionToOffset(src []byte, pos lsp.Position) int {
+	offset := 0
+	for line := 0; line < pos.Line; line++ {
+		next := offset
+		for next < len(src) && src[next] != '\n' {
+			next++
+		}
+		if next == len(src) {
+			return len(src)
+		}
+		offset = next + 1
+	}
+
+	for char := 0; char < pos.Character && offset < len(src) && src[offset] != '\n'; char++ {
+		_, size := utf8.DecodeRune(src[offset:])
+		offset += size
+	}
+	return offset
+}
@@ -0,0 +1,91 @@
+package langserver
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func Test_completionContextAt(t *testing.T) {
+	tests := []struct {
+		name string
+		src  string
+		want completionContext
+	}{
+		{
+			name: "top level",
+			src:  "",
+			want: completionContext{inBlock: true},
+		},
+		{
+			name: "config block",
+			src:  "config {\n  ",
+			want: completionContext{block: []string{"config"}, inBlock: true},
+		},
+		{
+			name: "attribute name",
+			src:  "plugin \"aws\" {\n  enabled = true\n  ver",
+			want: completionContext{block: []string{"plugin", "aws"}, inBlock: true, prefix: "ver"},
+		},
+		{
+			name: "attribute value",
+			src:  "plugin \"aws\" {\n  enabled = ",
+			want: completionContext{block: []string{"plugin", "aws"}},
+		},
+		{
+			name: "object value",
+			src:  "config {\n  varfile = {\n    ",
+			want: completionContext{},
+		},
+		{
+			name: "closed block",
+			src:  "rule \"foo\" {\n  enabled = true\n}\n",
+			want: completionContext{inBlock: true},
+		},
+		{
+			name: "empty rule label",
+			src:  "rule \"",
+			want: completionContext{ruleLabel: true},
+		},
+		{
+			name: "rule label",
+			src:  "rule \"aws_",
+			want: completionContext{ruleLabel: true, prefix: "aws_"},
+		},
+		{
+			name: "closed rule label",
+			src:  "rule \"aws\" ",
+			want: completionContext{},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got := completionContextAt([]byte(test.src), len(test.src))
+			if diff := cmp.Diff(test.want, got, cmp.AllowUnexported(completionContext{})); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
+
+func Test_nameSuggestion(t *testing.T) {
+	candidates := []string{"aws_instance_invalid_type", "aws_instance_previous_type", "terraform_unused_declarations"}
+
+	tests := []struct {
+		given string
+		want  string
+	}{
+		{given: "aws_instance_invalid_typ", want: "aws_instance_invalid_type"},
+		{given: "terraform_unused_declaration", want: "terraform_unused_declarations"},
+		{given: "aws_db_instance_invalid_type", want: ""},
+	}
+
+	for _, test := range tests {
+		t.Run(test.given, func(t *testing.T) {
+			if got := nameSuggestion(test.given, candidates); got != test.want {
+				t.Errorf("expected %q, but got %q", test.want, got)
+			}
+		})
+	}
+}
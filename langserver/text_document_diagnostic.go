@@ -0,0 +1,111 @@
+package langserver
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+
+	lsp "github.com/sourcegraph/go-lsp"
+	"github.com/sourcegraph/jsonrpc2"
+)
+
+// Kinds of document diagnostic reports
+const (
+	reportKindFull      = "full"
+	reportKindUnchanged = "unchanged"
+)
+
+type documentDiagnosticParams struct {
+	TextDocument     lsp.TextDocumentIdentifier `json:"textDocument"`
+	PreviousResultID string                     `json:"previousResultId,omitempty"`
+}
+
+// documentDiagnosticReport is a full or unchanged report of pull diagnostics.
+// Items is a pointer because full reports must have items even if empty, while unchanged reports must not.
+type documentDiagnosticReport struct {
+	Kind     string        `json:"kind"`
+	ResultID string        `json:"resultId"`
+	Items    *[]diagnostic `json:"items,omitempty"`
+}
+
+func (h *handler) textDocumentDiagnostic(ctx context.Context, conn *jsonrpc2.Conn, req *jsonrpc2.Request) (result any, err error) {
+	if req.Params == nil {
+		return nil, &jsonrpc2.Error{
+			Code:    jsonrpc2.CodeInvalidRequest,
+			Message: "request params are nil",
+		}
+	}
+
+	var params documentDiagnosticParams
+	if err := json.Unmarshal(*req.Params, &params); err != nil {
+		return nil, &jsonrpc2.Error{
+			Code:    jsonrpc2.CodeParseError,
+			Message: err.Error(),
+			Data:    req.Params,
+		}
+	}
+
+	path, err := uriToPath(params.TextDocument.URI)
+	if err != nil {
+		return nil, err
+	}
+
+	r, err := h.rootFor(conn, filepath.Dir(path))
+	if err != nil {
+		return nil, err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if err := r.updateDiagnostics(ctx, conn); err != nil {
+		return nil, err
+	}
+
+	return diagnosticReport(h.diagnostics(r, path), params.PreviousResultID)
+}
+
+// updateDiagnostics inspects the root module unless the last edit has already been inspected.
+// Unlike flushInspection, diagnostics are not published because they are returned to the client.
+func (r *root) updateDiagnostics(ctx context.Context, conn *jsonrpc2.Conn) error {
+	if r.inspected() {
+		return nil
+	}
+	r.discardInspection()
+
+	return r.cancellable(ctx, func(ctx context.Context) error {
+		_, err := r.inspect(ctx, conn)
+		return err
+	})
+}
+
+// diagnostics returns the issues found in the file by the last inspection of the root module,
+// and the errors of the config file if it is a config file.
+func (h *handler) diagnostics(r *root, path string) []diagnostic {
+	items := append([]diagnostic{}, h.configDiagnostics()[path]...)
+	if r != nil {
+		for _, issue := range r.issues[path] {
+			items = append(items, toLSPDiagnostic(issue))
+		}
+	}
+	return items
+}
+
+// diagnosticReport returns a report of the diagnostics.
+// The result ID is derived from the diagnostics, so an unchanged report is returned
+// if the diagnostics are the same as the previous result.
+func diagnosticReport(items []diagnostic, previousResultID string) (documentDiagnosticReport, error) {
+	out, err := json.Marshal(items)
+	if err != nil {
+		return documentDiagnosticReport{}, fmt.Errorf("Failed to marshal diagnostics: %s", err)
+	}
+	sum := sha256.Sum256(out)
+	resultID := hex.EncodeToString(sum[:8])
+
+	if resultID == previousResultID {
+		return documentDiagnosticReport{Kind: reportKindUnchanged, ResultID: resultID}, nil
+	}
+	return documentDiagnosticReport{Kind: reportKindFull, ResultID: resultID, Items: &items}, nil
+}
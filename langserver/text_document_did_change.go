@@ -4,13 +4,10 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-	"log"
-	"os"
 	"path/filepath"
 
 	lsp "github.com/sourcegraph/go-lsp"
 	"github.com/sourcegraph/jsonrpc2"
-	"github.com/spf13/afero"
 )
 
 func (h *handler) textDocumentDidChange(ctx context.Context, conn *jsonrpc2.Conn, req *jsonrpc2.Request) (result any, err error) {
@@ -35,35 +32,31 @@ func (h *handler) textDocumentDidChange(ctx context.Context, conn *jsonrpc2.Conn
 		return nil, err
 	}
 
-	if err := h.chdir(filepath.Dir(changedPath)); err != nil {
+	r, err := h.rootFor(conn, filepath.Dir(changedPath))
+	if err != nil {
 		return nil, err
 	}
+	// Abort the running inspection as early as possible, since it is superseded by the edit
+	r.cancelInspection()
 
 	for idx, contentChange := range params.ContentChanges {
-		if err := afero.WriteFile(h.fs, filepath.Base(changedPath), []byte(contentChange.Text), os.ModePerm); err != nil {
+		if err := h.updateDocument(changedPath, []byte(contentChange.Text)); err != nil {
 			return nil, fmt.Errorf("Failed to synchronize contentChanges[%d].Text: %s", idx, err)
 		}
 	}
 
-	diagnostics, err := h.inspect()
-	if err != nil {
-		return nil, err
-	}
-
-	log.Printf("Notify textDocument/publishDiagnostics with %#v", diagnostics)
-	for path, diags := range diagnostics {
-		err = conn.Notify(
-			ctx,
-			"textDocument/publishDiagnostics",
-			lsp.PublishDiagnosticsParams{
-				URI:         pathToURI(path),
-				Diagnostics: diags,
-			},
-		)
-		if err != nil {
-			return nil, fmt.Errorf("Failed to notify textDocument/publishDiagnostics: %s", err)
+	// Config files are validated on every edit, since the validation does not need inspections
+	if h.isConfigDocument(changedPath) {
+		if err := h.validateConfigDocument(conn, changedPath); err != nil {
+			return nil, err
 		}
 	}
 
+	// Edits are inspected together after the debounce delay
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.markEdited(changedPath)
+	r.scheduleInspection(conn)
+
 	return nil, nil
 }
@@ -0,0 +1,69 @@
+package langserver
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"path/filepath"
+
+	lsp "github.com/sourcegraph/go-lsp"
+	"github.com/sourcegraph/jsonrpc2"
+	"github.com/spf13/afero"
+)
+
+func (h *handler) textDocumentDidClose(ctx context.Context, conn *jsonrpc2.Conn, req *jsonrpc2.Request) (result any, err error) {
+	if req.Params == nil {
+		return nil, &jsonrpc2.Error{
+			Code:    jsonrpc2.CodeInvalidRequest,
+			Message: "request params are nil",
+		}
+	}
+
+	var params lsp.DidCloseTextDocumentParams
+	if err := json.Unmarshal(*req.Params, &params); err != nil {
+		return nil, &jsonrpc2.Error{
+			Code:    jsonrpc2.CodeParseError,
+			Message: err.Error(),
+			Data:    req.Params,
+		}
+	}
+
+	closedPath, err := uriToPath(params.TextDocument.URI)
+	if err != nil {
+		return nil, err
+	}
+
+	src, opened := h.documents[closedPath]
+	if !opened {
+		return nil, nil
+	}
+	delete(h.documents, closedPath)
+	if err := h.closeConfigDocument(conn, closedPath); err != nil {
+		return nil, err
+	}
+
+	for _, r := range h.rootsContaining(closedPath) {
+		r.mu.Lock()
+		err := r.resetOverlay()
+		r.mu.Unlock()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// Unsaved changes are discarded, so the file on disk must be inspected instead
+	onDisk, err := afero.ReadFile(afero.NewOsFs(), closedPath)
+	if err == nil && bytes.Equal(src, onDisk) {
+		return nil, nil
+	}
+	r, exists := h.roots[filepath.Dir(closedPath)]
+	if !exists {
+		return nil, nil
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.discardInspection()
+
+	return nil, r.runInspection(ctx, conn)
+}
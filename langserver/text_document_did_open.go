@@ -4,13 +4,10 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-	"log"
-	"os"
 	"path/filepath"
 
 	lsp "github.com/sourcegraph/go-lsp"
 	"github.com/sourcegraph/jsonrpc2"
-	"github.com/spf13/afero"
 )
 
 func (h *handler) textDocumentDidOpen(ctx context.Context, conn *jsonrpc2.Conn, req *jsonrpc2.Request) (result any, err error) {
@@ -35,33 +32,23 @@ func (h *handler) textDocumentDidOpen(ctx context.Context, conn *jsonrpc2.Conn,
 		return nil, err
 	}
 
-	if err := h.chdir(filepath.Dir(openedPath)); err != nil {
-		return nil, err
-	}
-
-	if err := afero.WriteFile(h.fs, filepath.Base(openedPath), []byte(params.TextDocument.Text), os.ModePerm); err != nil {
+	if err := h.updateDocument(openedPath, []byte(params.TextDocument.Text)); err != nil {
 		return nil, fmt.Errorf("Failed to synchronize TextDocument.Text: %s", err)
 	}
-
-	diagnostics, err := h.inspect()
+	r, err := h.rootFor(conn, filepath.Dir(openedPath))
 	if err != nil {
 		return nil, err
 	}
-
-	log.Printf("Notify textDocument/publishDiagnostics with %#v", diagnostics)
-	for path, diags := range diagnostics {
-		err = conn.Notify(
-			ctx,
-			"textDocument/publishDiagnostics",
-			lsp.PublishDiagnosticsParams{
-				URI:         pathToURI(path),
-				Diagnostics: diags,
-			},
-		)
-		if err != nil {
-			return nil, fmt.Errorf("Failed to notify textDocument/publishDiagnostics: %s", err)
+	if h.isConfigDocument(openedPath) {
+		if err := h.validateConfigDocument(conn, openedPath); err != nil {
+			return nil, err
 		}
 	}
 
-	return nil, nil
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	// The pending inspection is superseded by the following inspection
+	r.discardInspection()
+
+	return nil, r.runInspection(ctx, conn)
 }
@@ -0,0 +1,164 @@
+package langserver
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	lsp "github.com/sourcegraph/go-lsp"
+	"github.com/sourcegraph/jsonrpc2"
+	"github.com/terraform-linters/tflint/tflint"
+)
+
+// hover is a hover with markup content added in LSP 3.0.
+// go-lsp only defines the deprecated MarkedString contents.
+type hover struct {
+	Contents markupContent `json:"contents"`
+	Range    *lsp.Range    `json:"range,omitempty"`
+}
+
+type markupContent struct {
+	Kind  string `json:"kind"`
+	Value string `json:"value"`
+}
+
+func (h *handler) textDocumentHover(ctx context.Context, conn *jsonrpc2.Conn, req *jsonrpc2.Request) (result any, err error) {
+	if req.Params == nil {
+		return nil, &jsonrpc2.Error{
+			Code:    jsonrpc2.CodeInvalidRequest,
+			Message: "request params are nil",
+		}
+	}
+
+	var params lsp.TextDocumentPositionParams
+	if err := json.Unmarshal(*req.Params, &params); err != nil {
+		return nil, &jsonrpc2.Error{
+			Code:    jsonrpc2.CodeParseError,
+			Message: err.Error(),
+			Data:    req.Params,
+		}
+	}
+
+	path, err := uriToPath(params.TextDocument.URI)
+	if err != nil {
+		return nil, err
+	}
+	r, exists := h.roots[filepath.Dir(path)]
+	if !exists {
+		return nil, nil
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	// Hovers are based on the results of the last edit
+	if err := r.flushInspection(ctx, conn); err != nil {
+		return nil, err
+	}
+
+	cursor := lsp.Range{Start: params.Position, End: params.Position}
+	var rng *lsp.Range
+	sections := []string{}
+	for _, issue := range r.issues[path].Sort() {
+		issueRange := toLSPRange(issue.Range)
+		if !rangesOverlap(issueRange, cursor) {
+			continue
+		}
+		if rng == nil {
+			rng = &issueRange
+		}
+
+		section, err := r.issueDocument(issue)
+		if err != nil {
+			return nil, err
+		}
+		sections = append(sections, section)
+	}
+
+	// Show the value of the expression at the position as TFLint evaluates it
+	if runner, rel := r.runnerFor(path); runner != nil {
+		file := runner.File(rel)
+		if expr := exprAt(file, params.Position); expr != nil {
+			if section := valueDocument(runner, expr, file.Bytes); section != "" {
+				if rng == nil {
+					exprRange := toLSPRange(expr.Range())
+					rng = &exprRange
+				}
+				sections = append(sections, section)
+			}
+		}
+	}
+	if len(sections) == 0 {
+		return nil, nil
+	}
+
+	return hover{
+		Contents: markupContent{Kind: "markdown", Value: strings.Join(sections, "\n\n---\n\n")},
+		Range:    rng,
+	}, nil
+}
+
+// issueDocument returns a Markdown document describing the issue and its rule.
+func (r *root) issueDocument(issue *tflint.Issue) (string, error) {
+	rule := issue.Rule
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "**%s**\n\n%s\n\n", rule.Name(), issue.Message)
+
+	pluginName, err := r.rulePlugin(rule.Name())
+	if err != nil {
+		return "", err
+	}
+	if pluginName != "" {
+		fmt.Fprintf(&b, "- Plugin: %s\n", pluginName)
+	}
+	fmt.Fprintf(&b, "- Severity: %s\n", rule.Severity())
+	if issue.Fixable {
+		fmt.Fprint(&b, "- Fixable: yes\n")
+	} else {
+		fmt.Fprint(&b, "- Fixable: no\n")
+	}
+
+	if len(issue.Callers) > 0 {
+		fmt.Fprint(&b, "\nCallers:\n\n")
+		for i, caller := range issue.Callers {
+			fmt.Fprintf(&b, "%d. `%s`\n", i+1, caller)
+		}
+	}
+
+	if link := rule.Link(); link != "" {
+		fmt.Fprintf(&b, "\n[Documentation](%s)\n", link)
+	}
+
+	return strings.TrimSuffix(b.String(), "\n"), nil
+}
+
+// rulePlugin returns the name of the plugin that provides the rule.
+// Returns an empty string if no plugin provides the rule, e.g. rules built into TFLint.
+func (r *root) rulePlugin(rule string) (string, error) {
+	rulePlugins, err := r.loadRulePlugins()
+	if err != nil {
+		return "", err
+	}
+	return rulePlugins[rule], nil
+}
+
+// loadRulePlugins returns the names of the plugins that provide rules, keyed by rule names.
+// They are fetched from plugins on the first call, and cached until the plugins are reloaded.
+func (r *root) loadRulePlugins() (map[string]string, error) {
+	if r.rulePlugins == nil {
+		rulePlugins := map[string]string{}
+		for name, ruleset := range r.plugin.RuleSets {
+			ruleNames, err := ruleset.RuleNames()
+			if err != nil {
+				return nil, fmt.Errorf(`Failed to get rule names from "%s" plugin: %w`, name, err)
+			}
+			for _, ruleName := range ruleNames {
+				rulePlugins[ruleName] = name
+			}
+		}
+		r.rulePlugins = rulePlugins
+	}
+	return r.rulePlugins, nil
+}
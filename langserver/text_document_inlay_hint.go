@@ -0,0 +1,100 @@
+package langserver
+
+import (
+	"context"
+	"encoding/json"
+	"path/filepath"
+
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	lsp "github.com/sourcegraph/go-lsp"
+	"github.com/sourcegraph/jsonrpc2"
+	"github.com/zclconf/go-cty/cty"
+)
+
+// inlayHintParams is the params of textDocument/inlayHint added in LSP 3.17.
+type inlayHintParams struct {
+	TextDocument lsp.TextDocumentIdentifier `json:"textDocument"`
+	Range        lsp.Range                  `json:"range"`
+}
+
+type inlayHint struct {
+	Position    lsp.Position `json:"position"`
+	Label       string       `json:"label"`
+	PaddingLeft bool         `json:"paddingLeft,omitempty"`
+}
+
+func (h *handler) textDocumentInlayHint(ctx context.Context, conn *jsonrpc2.Conn, req *jsonrpc2.Request) (result any, err error) {
+	if req.Params == nil {
+		return nil, &jsonrpc2.Error{
+			Code:    jsonrpc2.CodeInvalidRequest,
+			Message: "request params are nil",
+		}
+	}
+
+	var params inlayHintParams
+	if err := json.Unmarshal(*req.Params, &params); err != nil {
+		return nil, &jsonrpc2.Error{
+			Code:    jsonrpc2.CodeParseError,
+			Message: err.Error(),
+			Data:    req.Params,
+		}
+	}
+
+	path, err := uriToPath(params.TextDocument.URI)
+	if err != nil {
+		return nil, err
+	}
+	hints := []inlayHint{}
+	r, exists := h.roots[filepath.Dir(path)]
+	if !exists {
+		return hints, nil
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	// Hints are based on the results of the last edit
+	if err := r.flushInspection(ctx, conn); err != nil {
+		return nil, err
+	}
+
+	runner, rel := r.runnerFor(path)
+	if runner == nil {
+		return hints, nil
+	}
+	body, ok := runner.File(rel).Body.(*hclsyntax.Body)
+	if !ok {
+		return hints, nil
+	}
+
+	// Show the number of instances next to "count" and "for_each" of resources, data sources, and module calls
+	for _, block := range body.Blocks {
+		switch block.Type {
+		case "resource", "data", "module", "ephemeral":
+		default:
+			continue
+		}
+
+		for _, name := range []string{"count", "for_each"} {
+			attr, exists := block.Body.Attributes[name]
+			if !exists {
+				continue
+			}
+			rng := toLSPRange(attr.Expr.Range())
+			if !rangesOverlap(rng, params.Range) {
+				continue
+			}
+
+			val, diags := runner.Ctx.EvaluateExpr(attr.Expr, cty.DynamicPseudoType)
+			if diags.HasErrors() {
+				continue
+			}
+			label, ok := instancesLabel(val)
+			if !ok {
+				continue
+			}
+			hints = append(hints, inlayHint{Position: rng.End, Label: label, PaddingLeft: true})
+		}
+	}
+
+	return hints, nil
+}
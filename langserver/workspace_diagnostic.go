@@ -0,0 +1,124 @@
+package langserver
+
+import (
+	"context"
+	"encoding/json"
+	"path/filepath"
+	"sort"
+
+	lsp "github.com/sourcegraph/go-lsp"
+	"github.com/sourcegraph/jsonrpc2"
+)
+
+type workspaceDiagnosticParams struct {
+	PreviousResultIDs []previousResultID `json:"previousResultIds"`
+}
+
+type previousResultID struct {
+	URI   lsp.DocumentURI `json:"uri"`
+	Value string          `json:"value"`
+}
+
+type workspaceDiagnosticReport struct {
+	Items []workspaceDocumentDiagnosticReport `json:"items"`
+}
+
+// workspaceDocumentDiagnosticReport is a document diagnostic report with the document URI.
+// Version is always null because diagnostics are not associated with document versions.
+type workspaceDocumentDiagnosticReport struct {
+	documentDiagnosticReport
+	URI     lsp.DocumentURI `json:"uri"`
+	Version *int            `json:"version"`
+}
+
+func (h *handler) workspaceDiagnostic(ctx context.Context, conn *jsonrpc2.Conn, req *jsonrpc2.Request) (result any, err error) {
+	var params workspaceDiagnosticParams
+	if req.Params != nil {
+		if err := json.Unmarshal(*req.Params, &params); err != nil {
+			return nil, &jsonrpc2.Error{
+				Code:    jsonrpc2.CodeParseError,
+				Message: err.Error(),
+				Data:    req.Params,
+			}
+		}
+	}
+
+	ret := workspaceDiagnosticReport{Items: []workspaceDocumentDiagnosticReport{}}
+
+	// No root module is inspected until a document is opened
+	roots := []*root{}
+	for _, r := range h.sortedRoots() {
+		if r.active() {
+			roots = append(roots, r)
+		}
+	}
+	for _, r := range roots {
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		if err := r.updateDiagnostics(ctx, conn); err != nil {
+			return nil, err
+		}
+	}
+	if len(roots) == 0 {
+		return ret, nil
+	}
+
+	// Report files where issues were found, open documents, config files with errors,
+	// and files previously reported to clear the issues
+	previousResultIDs := map[string]string{}
+	for _, r := range roots {
+		for path := range r.issues {
+			previousResultIDs[path] = ""
+		}
+	}
+	for path := range h.configDiagnostics() {
+		previousResultIDs[path] = ""
+	}
+	for path := range h.documents {
+		previousResultIDs[path] = ""
+	}
+	for _, previous := range params.PreviousResultIDs {
+		path, err := uriToPath(previous.URI)
+		if err != nil {
+			return nil, err
+		}
+		previousResultIDs[path] = previous.Value
+	}
+
+	paths := make([]string, 0, len(previousResultIDs))
+	for path := range previousResultIDs {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	for _, path := range paths {
+		report, err := diagnosticReport(h.diagnostics(reportingRoot(roots, path), path), previousResultIDs[path])
+		if err != nil {
+			return nil, err
+		}
+		ret.Items = append(ret.Items, workspaceDocumentDiagnosticReport{
+			documentDiagnosticReport: report,
+			URI:                      pathToURI(path),
+		})
+	}
+
+	return ret, nil
+}
+
+// reportingRoot returns the root module whose diagnostics are reported for the file.
+// A file can be inspected by multiple root modules if it is in a module called by another root module.
+// The root module in the same directory takes precedence, followed by the first root module with issues in the file.
+// Returns nil if no root module has issues in the file.
+func reportingRoot(roots []*root, path string) *root {
+	for _, r := range roots {
+		if r.dir == filepath.Dir(path) {
+			return r
+		}
+	}
+	for _, r := range roots {
+		if len(r.issues[path]) > 0 {
+			return r
+		}
+	}
+	return nil
+}
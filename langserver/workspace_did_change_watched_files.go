@@ -2,48 +2,49 @@ package langserver
 
 import (
 	"context"
-	"fmt"
-	"log"
+	"encoding/json"
+	"errors"
+	"slices"
 
 	lsp "github.com/sourcegraph/go-lsp"
 	"github.com/sourcegraph/jsonrpc2"
-	"github.com/spf13/afero"
-	"github.com/terraform-linters/tflint/tflint"
 )
 
 func (h *handler) workspaceDidChangeWatchedFiles(ctx context.Context, conn *jsonrpc2.Conn, req *jsonrpc2.Request) (result any, err error) {
-	if h.rootDir == "" {
-		return nil, fmt.Errorf("root directory is undefined")
-	}
-
-	newConfig, err := tflint.LoadConfig(afero.Afero{Fs: afero.NewOsFs()}, h.configPath)
-	if err != nil {
-		return nil, err
+	var params lsp.DidChangeWatchedFilesParams
+	if req.Params != nil {
+		if err := json.Unmarshal(*req.Params, &params); err != nil {
+			return nil, &jsonrpc2.Error{
+				Code:    jsonrpc2.CodeParseError,
+				Message: err.Error(),
+				Data:    req.Params,
+			}
+		}
 	}
-	newConfig.Merge(h.cliConfig)
-	h.config = newConfig
-
-	h.fs = afero.NewCopyOnWriteFs(afero.NewOsFs(), afero.NewMemMapFs())
 
-	diagnostics, err := h.inspect()
-	if err != nil {
-		return nil, err
+	paths := make([]string, len(params.Changes))
+	for i, change := range params.Changes {
+		path, err := uriToPath(change.URI)
+		if err != nil {
+			return nil, err
+		}
+		paths[i] = path
 	}
 
-	log.Printf("Notify textDocument/publishDiagnostics with %#v", diagnostics)
-	for path, diags := range diagnostics {
-		err = conn.Notify(
-			ctx,
-			"textDocument/publishDiagnostics",
-			lsp.PublishDiagnosticsParams{
-				URI:         pathToURI(path),
-				Diagnostics: diags,
-			},
-		)
-		if err != nil {
-			return nil, fmt.Errorf("Failed to notify textDocument/publishDiagnostics: %s", err)
+	// Root modules are reloaded if their config files or plugins are changed.
+	// Otherwise, they are inspected again with the files changed on disk.
+	reloads := []*root{}
+	var errs []error
+	for _, r := range h.sortedRoots() {
+		if slices.ContainsFunc(paths, r.isConfigOrPlugin) {
+			reloads = append(reloads, r)
+			continue
 		}
+		r.mu.Lock()
+		errs = append(errs, r.reinspect(ctx, conn))
+		r.mu.Unlock()
 	}
+	errs = append(errs, h.reloadRoots(ctx, conn, reloads))
 
-	return nil, nil
+	return nil, errors.Join(errs...)
 }
@@ -0,0 +1,105 @@
+package langserver
+
+import (
+	"context"
+	"encoding/json"
+	"slices"
+
+	"github.com/sourcegraph/jsonrpc2"
+)
+
+type didChangeWorkspaceFoldersParams struct {
+	Event workspaceFoldersChangeEvent `json:"event"`
+}
+
+type workspaceFoldersChangeEvent struct {
+	Added   []workspaceFolder `json:"added"`
+	Removed []workspaceFolder `json:"removed"`
+}
+
+func (h *handler) workspaceDidChangeWorkspaceFolders(ctx context.Context, conn *jsonrpc2.Conn, req *jsonrpc2.Request) (result any, err error) {
+	if req.Params == nil {
+		return nil, &jsonrpc2.Error{
+			Code:    jsonrpc2.CodeInvalidRequest,
+			Message: "request params are nil",
+		}
+	}
+
+	var params didChangeWorkspaceFoldersParams
+	if err := json.Unmarshal(*req.Params, &params); err != nil {
+		return nil, &jsonrpc2.Error{
+			Code:    jsonrpc2.CodeParseError,
+			Message: err.Error(),
+			Data:    req.Params,
+		}
+	}
+
+	removed := []string{}
+	for _, folder := range params.Event.Removed {
+		path, err := uriToPath(folder.URI)
+		if err != nil {
+			return nil, err
+		}
+		removed = append(removed, path)
+	}
+	h.folders = slices.DeleteFunc(h.folders, func(folder string) bool {
+		return slices.Contains(removed, folder)
+	})
+	added := []string{}
+	for _, folder := range params.Event.Added {
+		path, err := uriToPath(folder.URI)
+		if err != nil {
+			return nil, err
+		}
+		added = append(added, path)
+	}
+	h.folders = append(h.folders, added...)
+
+	// Root modules in removed folders are disposed unless they are still in other folders
+	previous := h.configDiagnostics()
+	reloads := []*root{}
+	for _, r := range h.sortedRoots() {
+		if inAnyFolder(removed, r.dir) && !inAnyFolder(h.folders, r.dir) {
+			if err := h.disposeRoot(conn, r); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		// Root modules in added folders may use the config file of the folder
+		if inAnyFolder(added, r.dir) {
+			reloads = append(reloads, r)
+		}
+	}
+	if err := h.publishConfigDiagnostics(conn, previous); err != nil {
+		return nil, err
+	}
+
+	return nil, h.reloadRoots(ctx, conn, reloads)
+}
+
+// disposeRoot kills plugins of the root module and clears the diagnostics published by it.
+func (h *handler) disposeRoot(conn *jsonrpc2.Conn, r *root) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.close()
+	delete(h.roots, r.dir)
+
+	if h.pullDiagnostics {
+		return h.refreshDiagnostics(context.Background(), conn)
+	}
+	diags := map[string][]diagnostic{}
+	for _, path := range r.diagsPaths {
+		diags[path] = []diagnostic{}
+	}
+	return h.publishDiagnostics(context.Background(), conn, diags)
+}
+
+func inAnyFolder(folders []string, path string) bool {
+	for _, folder := range folders {
+		if _, ok := relPath(folder, path); ok {
+			return true
+		}
+	}
+	return false
+}
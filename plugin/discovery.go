@@ -20,12 +20,25 @@ import (
 // If the plugin is not enabled, skip without starting.
 // The Terraform Language plugin is treated specially. Plugins for which no version
 // is specified will launch the bundled plugin instead of returning an error.
+//
+// If the lock file exists, installed plugins are verified against it before launching.
 func Discovery(config *tflint.Config) (*Plugin, error) {
 	clients := map[string]*plugin.Client{}
 	rulesets := map[string]*host2plugin.Client{}
 
+	lock, err := LoadLockFile(LockFilePath(config))
+	if err != nil {
+		return nil, err
+	}
+
 	for _, pluginCfg := range config.Plugins {
 		installCfg := NewInstallConfig(config, pluginCfg)
+		// Version constraints are resolved to the version recorded in the lock file
+		if resolved := lock.Resolve(installCfg); resolved != nil {
+			installCfg = resolved
+		} else {
+			return nil, lock.unresolvedVersionError(installCfg)
+		}
 		pluginPath, err := FindPluginPath(installCfg)
 		var cmd *exec.Cmd
 		if os.IsNotExist(err) {
@@ -38,7 +51,7 @@ func Discovery(config *tflint.Config) (*Plugin, error) {
 				cmd = exec.Command(self, "--act-as-bundled-plugin")
 			} else {
 				if installCfg.ManuallyInstalled() {
-					pluginDir, err := getPluginDir(config)
+					pluginDir, err := PluginDir(config)
 					if err != nil {
 						return nil, err
 					}
@@ -53,6 +66,12 @@ func Discovery(config *tflint.Config) (*Plugin, error) {
 		if pluginCfg.Enabled {
 			log.Printf(`[INFO] Plugin "%s" found`, pluginCfg.Name)
 
+			if pluginPath != "" {
+				if err := lock.Verify(installCfg, pluginPath); err != nil {
+					return nil, err
+				}
+			}
+
 			client := host2plugin.NewClient(&host2plugin.ClientOpts{
 				Cmd: cmd,
 			})
@@ -78,7 +97,7 @@ func Discovery(config *tflint.Config) (*Plugin, error) {
 
 // FindPluginPath returns the plugin binary path.
 func FindPluginPath(config *InstallConfig) (string, error) {
-	dir, err := getPluginDir(config.globalConfig)
+	dir, err := PluginDir(config.globalConfig)
 	if err != nil {
 		return "", err
 	}
@@ -91,7 +110,7 @@ func FindPluginPath(config *InstallConfig) (string, error) {
 	return path, err
 }
 
-// getPluginDir returns the base plugin directory.
+// PluginDir returns the base plugin directory.
 // Adopted with the following priorities:
 //
 //  1. `plugin_dir` in a global config
@@ -101,7 +120,7 @@ func FindPluginPath(config *InstallConfig) (string, error) {
 //
 // If the environment variable is set, other directories will not be considered,
 // but if the current directory does not exist, it will fallback to the home directory.
-func getPluginDir(cfg *tflint.Config) (string, error) {
+func PluginDir(cfg *tflint.Config) (string, error) {
 	if cfg.PluginDir != "" {
 		return homedir.Expand(cfg.PluginDir)
 	}
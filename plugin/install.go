@@ -27,6 +27,10 @@ import (
 
 const defaultSourceHost = "github.com"
 
+// sigstoreBundleFileName is the name of the sigstore bundle of checksums.txt, which can be stored
+// next to the release assets to verify releases outside GitHub with keyless signing.
+const sigstoreBundleFileName = "checksums.txt.sigstore.json"
+
 type SignatureMode string
 
 const (
@@ -59,7 +63,22 @@ func (c *InstallConfig) ManuallyInstalled() bool {
 
 // InstallPath returns an installation path from the plugin directory.
 func (c *InstallConfig) InstallPath() string {
-	return filepath.Join(c.Source, c.Version, fmt.Sprintf("tflint-ruleset-%s", c.Name))
+	return filepath.Join(c.sourceDir(), c.Version, fmt.Sprintf("tflint-ruleset-%s", c.Name))
+}
+
+// sourceDir returns a directory name for the source.
+// GitHub sources are used as is, while prefixes and URL schemes of other sources are converted
+// so that they are valid as directory names, e.g. "https://example.com/foo" is "https/example.com/foo".
+func (c *InstallConfig) sourceDir() string {
+	switch c.SourceType {
+	case tflint.SourceTypeHTTPS, tflint.SourceTypeFile:
+		scheme, rest, _ := strings.Cut(c.Source, "://")
+		return filepath.Join(scheme, strings.ReplaceAll(rest, ":", "_"))
+	case tflint.SourceTypeGitLab, tflint.SourceTypeGitea:
+		return filepath.Join(c.SourceType, strings.TrimPrefix(c.Source, c.SourceType+"::"))
+	default:
+		return c.Source
+	}
 }
 
 // TagName returns a tag name that the GitHub release should meet.
@@ -72,20 +91,37 @@ func (c *InstallConfig) TagName() string {
 // AssetName returns a name that the asset contained in the release should meet.
 // The name must be in a format similar to `tflint-ruleset-aws_darwin_amd64.zip`.
 func (c *InstallConfig) AssetName() string {
-	return fmt.Sprintf("tflint-ruleset-%s_%s_%s.zip", c.Name, runtime.GOOS, runtime.GOARCH)
+	return c.assetNameFor(currentPlatform())
+}
+
+// assetNameFor returns the asset name for the platform like "linux_amd64".
+func (c *InstallConfig) assetNameFor(platform string) string {
+	return fmt.Sprintf("tflint-ruleset-%s_%s.zip", c.Name, platform)
 }
 
 // CertificateIdentitySANRegex returns a regular expression that matches
 // the Subject Alternative Name in the certificate in keyless signing.
 // Typically the SAN will be a value like https://github.com/terraform-linters/tflint-ruleset-aws/.github/workflows/release.yml@refs/tags/v0.35.0
 // This ensures that the installed plugin was indeed built from that source repository.
+//
+// If certificate_identity_regexp is set, it is used instead, e.g. for plugins built by your own CI.
+// Returns an empty string for non-GitHub sources without it, because there is no default identity.
 func (c *InstallConfig) CertificateIdentitySANRegex() string {
+	if c.CertificateIdentityRegexp != "" {
+		return c.CertificateIdentityRegexp
+	}
+	if c.SourceType != "" {
+		return ""
+	}
 	return fmt.Sprintf("^https://%s/%s/%s/", regexp.QuoteMeta(c.SourceHost), regexp.QuoteMeta(c.SourceOwner), regexp.QuoteMeta(c.SourceRepo))
 }
 
 // CertificateIdentityIssuer returns the iss field of the OIDC token for keyless signing.
-// This ensures that the OIDC token was indeed issued by GitHub.
+// This ensures that the OIDC token was indeed issued by GitHub, or by the issuer in certificate_oidc_issuer if set.
 func (c *InstallConfig) CertificateIdentityIssuer() string {
+	if c.CertificateOIDCIssuer != "" {
+		return c.CertificateOIDCIssuer
+	}
 	// https://docs.github.com/en/actions/security-for-github-actions/security-hardening-your-deployments/about-security-hardening-with-openid-connect#understanding-the-oidc-token
 	return "https://token.actions.githubusercontent.com"
 }
@@ -95,7 +131,9 @@ var (
 	ErrLegacySigningKeyUsed = errors.New("legacy signing key used")
 )
 
-// Install fetches the release from GitHub and puts the binary in the plugin directory.
+// Install fetches the release from the source and puts the binary in the plugin directory.
+// The source is GitHub releases by default, but GitLab and Gitea releases, HTTPS servers,
+// and local directories following the same conventions are also supported.
 // This installation process will automatically check the checksum of the downloaded zip file.
 // The release must always contain a checksum file and meet the following conventions:
 //
@@ -107,97 +145,211 @@ var (
 //
 // If possible, verify the signature to ensure that the checksum file has not been tampered with.
 func (c *InstallConfig) Install() (string, error) {
-	dir, err := getPluginDir(c.globalConfig)
+	path, _, err := c.InstallWithLock(nil, false)
+	return path, err
+}
+
+// InstallWithLock installs the plugin like Install, and returns the entry of the lock file for the installed plugin.
+// The entry contains the zip hashes of all platforms in checksums.txt, and the binary hash of the current platform.
+//
+// If the previously locked entry is passed, checksums.txt must match the locked hashes,
+// and the binary hashes of other platforms are carried over.
+// If allPlatforms is true, the zip files of all platforms are downloaded to record the hashes of their binaries.
+func (c *InstallConfig) InstallWithLock(previous *LockedPlugin, allPlatforms bool) (string, *LockedPlugin, error) {
+	dir, err := PluginDir(c.globalConfig)
 	if err != nil {
-		return "", fmt.Errorf("Failed to get plugin dir: %w", err)
+		return "", nil, fmt.Errorf("Failed to get plugin dir: %w", err)
 	}
 
 	path := filepath.Join(dir, c.InstallPath()+fileExt())
 	log.Printf("[DEBUG] Mkdir plugin dir: %s", filepath.Dir(path))
 	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
-		return "", fmt.Errorf("Failed to mkdir to %s: %w", filepath.Dir(path), err)
+		return "", nil, fmt.Errorf("Failed to mkdir to %s: %w", filepath.Dir(path), err)
 	}
 
 	ctx := context.Background()
-	client, err := newGitHubClient(ctx, c)
+	release, err := c.fetchVerifiedRelease(ctx)
 	if err != nil {
-		return "", fmt.Errorf("Failed to create GitHub client: %s", err)
+		return "", nil, err
 	}
+	source, checksummer := release.source, release.checksummer
 
-	assets, checksum, attestations, repo, err := c.fetchFromGitHub(ctx, client)
+	log.Printf("[DEBUG] Download %s", c.AssetName())
+	zipFile, err := source.download(ctx, c.AssetName())
+	if zipFile != nil {
+		defer os.Remove(zipFile.Name())
+	}
 	if err != nil {
-		return "", err
+		return "", nil, fmt.Errorf("Failed to download %s: %s", c.AssetName(), err)
+	}
+
+	if err = checksummer.Verify(c.AssetName(), zipFile); err != nil {
+		return "", nil, fmt.Errorf("Failed to verify checksums: %s", err)
+	}
+	log.Printf("[DEBUG] Matched checksum successfully")
+
+	locked := c.lockedPluginFromChecksums(checksummer)
+	if previous != nil {
+		if err := verifyLockedZipHashes(previous, locked); err != nil {
+			return "", nil, fmt.Errorf("Failed to verify checksums: %s", err)
+		}
+		// Binaries extracted from the same zip file have the same hashes
+		for platform, hashes := range previous.Platforms {
+			if current, exists := locked.Platforms[platform]; exists && current.Zip == hashes.Zip {
+				current.Binary = hashes.Binary
+			}
+		}
+	}
+
+	if err = extractFileFromZipFile(zipFile, path); err != nil {
+		return "", nil, fmt.Errorf("Failed to extract binary from %s: %s", c.AssetName(), err)
+	}
+	binaryHash, err := fileHash(path)
+	if err != nil {
+		return "", nil, fmt.Errorf("Failed to calculate the hash of %s: %s", path, err)
+	}
+	locked.Platforms[currentPlatform()].Binary = binaryHash
+
+	if allPlatforms {
+		for platform, hashes := range locked.Platforms {
+			if platform == currentPlatform() {
+				continue
+			}
+			if hashes.Binary, err = c.binaryHash(ctx, source, checksummer, platform); err != nil {
+				return "", nil, fmt.Errorf("Failed to calculate the hash of the binary for %s: %s", platform, err)
+			}
+		}
+	}
+
+	log.Printf("[DEBUG] Installed %s successfully", path)
+	return path, locked, release.verificationError()
+}
+
+// verifiedRelease is a release whose checksums.txt has been verified with the signature.
+type verifiedRelease struct {
+	source      releaseSource
+	checksum    []byte
+	checksummer *Checksummer
+
+	sigMode       SignatureMode
+	legacyKeyUsed bool
+	// signature is the content of checksums.txt.sig if verified with a PGP signing key.
+	signature []byte
+	// attestations are the artifact attestations of checksums.txt if verified with them.
+	attestations []*github.Attestation
+}
+
+// fetchVerifiedRelease fetches checksums.txt from the release source and verifies its signature.
+// Signatures are verified with artifact attestations or a PGP signing key depending on the signature mode.
+func (c *InstallConfig) fetchVerifiedRelease(ctx context.Context) (*verifiedRelease, error) {
+	source, err := c.newReleaseSource(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	checksum, attestations, repo, err := c.fetchChecksums(ctx, source)
+	if err != nil {
+		return nil, err
 	}
+	release := &verifiedRelease{source: source, checksum: checksum}
 
-	var legacyKeyUsed bool
 	sigchecker := NewSignatureChecker(c)
-	sigMode := c.signatureMode(repo, attestations, sigchecker)
-	switch sigMode {
+	release.sigMode = c.signatureMode(repo, attestations, sigchecker)
+	switch release.sigMode {
 	case SignatureModeAttestation:
 		log.Printf("[DEBUG] Verifying using artifact attestations")
 		if err := sigchecker.VerifyAttestations(bytes.NewReader(checksum), attestations); err != nil {
-			return "", fmt.Errorf("Failed to check checksums.txt signature: %s", err)
+			return nil, fmt.Errorf("Failed to check checksums.txt signature: %s", err)
 		}
 		log.Printf("[DEBUG] Verified signature successfully")
+		release.attestations = attestations
 
 	case SignatureModePGP:
 		log.Printf("[DEBUG] Download checksums.txt.sig")
-		signatureFile, err := c.downloadToTempFile(ctx, client, assets["checksums.txt.sig"])
+		signatureFile, err := source.download(ctx, "checksums.txt.sig")
 		if signatureFile != nil {
 			defer os.Remove(signatureFile.Name())
 		}
 		if err != nil {
-			return "", fmt.Errorf("Failed to download checksums.txt.sig: %s", err)
+			return nil, fmt.Errorf("Failed to download checksums.txt.sig: %s", err)
+		}
+		signature, err := io.ReadAll(signatureFile)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to read checksums.txt.sig: %s", err)
 		}
 
 		log.Printf("[DEBUG] Verifying using PGP signing key")
-		if err := sigchecker.VerifyPGPSignature(bytes.NewReader(checksum), signatureFile); err != nil {
-			return "", fmt.Errorf("Failed to check checksums.txt signature: %s", err)
+		if err := sigchecker.VerifyPGPSignature(bytes.NewReader(checksum), bytes.NewReader(signature)); err != nil {
+			return nil, fmt.Errorf("Failed to check checksums.txt signature: %s", err)
 		}
 		log.Printf("[DEBUG] Verified signature successfully")
+		release.signature = signature
 
 		// If using built-in signing key, a warning will be shown.
 		if sigchecker.GetSigningKey() == builtinSigningKey {
-			legacyKeyUsed = true
+			release.legacyKeyUsed = true
 		}
 
 	case SignatureModeNone:
 		log.Printf("[DEBUG] Skipping signature verification")
 
 	default:
-		panic(fmt.Sprintf("never happened. signature=%s", sigMode))
+		panic(fmt.Sprintf("never happened. signature=%s", release.sigMode))
 	}
 
-	log.Printf("[DEBUG] Download %s", c.AssetName())
-	zipFile, err := c.downloadToTempFile(ctx, client, assets[c.AssetName()])
-	if zipFile != nil {
-		defer os.Remove(zipFile.Name())
-	}
+	release.checksummer, err = NewChecksummer(bytes.NewReader(checksum))
 	if err != nil {
-		return "", fmt.Errorf("Failed to download %s: %s", c.AssetName(), err)
+		return nil, fmt.Errorf("Failed to parse checksums file: %s", err)
 	}
+	return release, nil
+}
 
-	checksummer, err := NewChecksummer(bytes.NewReader(checksum))
+// verificationError returns ErrPluginNotVerified or ErrLegacySigningKeyUsed
+// if the release should be warned about its signature.
+func (r *verifiedRelease) verificationError() error {
+	if r.sigMode == SignatureModeNone {
+		return ErrPluginNotVerified
+	}
+	if r.legacyKeyUsed {
+		return ErrLegacySigningKeyUsed
+	}
+	return nil
+}
+
+// binaryHash downloads the zip file for the platform and returns the hash of the binary in it.
+// The binary is extracted to a temporary directory, not to the plugin directory.
+func (c *InstallConfig) binaryHash(ctx context.Context, source releaseSource, checksummer *Checksummer, platform string) (string, error) {
+	assetName := c.assetNameFor(platform)
+	log.Printf("[DEBUG] Download %s", assetName)
+	zipFile, err := source.download(ctx, assetName)
+	if zipFile != nil {
+		defer os.Remove(zipFile.Name())
+	}
 	if err != nil {
-		return "", fmt.Errorf("Failed to parse checksums file: %s", err)
+		return "", fmt.Errorf("Failed to download %s: %s", assetName, err)
 	}
-	if err = checksummer.Verify(c.AssetName(), zipFile); err != nil {
+	if err := checksummer.Verify(assetName, zipFile); err != nil {
 		return "", fmt.Errorf("Failed to verify checksums: %s", err)
 	}
-	log.Printf("[DEBUG] Matched checksum successfully")
+	if _, err := zipFile.Seek(0, 0); err != nil {
+		return "", err
+	}
 
-	if err = extractFileFromZipFile(zipFile, path); err != nil {
-		return "", fmt.Errorf("Failed to extract binary from %s: %s", c.AssetName(), err)
+	tempDir, err := os.MkdirTemp("", "tflint-lock-*")
+	if err != nil {
+		return "", err
 	}
+	defer os.RemoveAll(tempDir)
 
-	log.Printf("[DEBUG] Installed %s successfully", path)
-	if sigMode == SignatureModeNone {
-		return path, ErrPluginNotVerified
+	binaryName := fmt.Sprintf("tflint-ruleset-%s", c.Name)
+	if strings.HasPrefix(platform, "windows_") {
+		binaryName += ".exe"
 	}
-	if legacyKeyUsed {
-		return path, ErrLegacySigningKeyUsed
+	path := filepath.Join(tempDir, binaryName)
+	if err := extractFileFromZipFile(zipFile, path); err != nil {
+		return "", fmt.Errorf("Failed to extract binary from %s: %s", assetName, err)
 	}
-	return path, nil
+	return fileHash(path)
 }
 
 func (c *InstallConfig) signatureMode(repo *github.Repository, attestations []*github.Attestation, sigchecker *SignatureChecker) SignatureMode {
@@ -224,55 +376,111 @@ func (c *InstallConfig) signatureMode(repo *github.Repository, attestations []*g
 
 func (c *InstallConfig) shouldVerifyAttestations(repo *github.Repository, attestations []*github.Attestation) bool {
 	// If the repository is private, we ignore attestations because we need to apply a different policy.
+	// The policy can be set with certificate_identity_regexp.
 	// See https://github.com/terraform-linters/tflint/issues/2209
-	if repo != nil && repo.Private != nil && *repo.Private {
+	if repo != nil && repo.Private != nil && *repo.Private && c.CertificateIdentityRegexp == "" {
+		return false
+	}
+	if len(attestations) > 0 && c.CertificateIdentitySANRegex() == "" {
+		log.Printf("[DEBUG] No certificate identity for attestations, ignoring them")
 		return false
 	}
 	return len(attestations) > 0
 }
 
-func (c *InstallConfig) fetchFromGitHub(ctx context.Context, client *github.Client) (map[string]*github.ReleaseAsset, []byte, []*github.Attestation, *github.Repository, error) {
-	assets, err := c.fetchReleaseAssets(ctx, client)
-	if err != nil {
-		return nil, nil, nil, nil, fmt.Errorf("Failed to fetch GitHub releases: %w", err)
-	}
-
+// fetchChecksums downloads checksums.txt from the release source.
+// For GitHub sources, it also fetches artifact attestations of checksums.txt and the repository metadata.
+// For the plugin mirror, attestations saved in the mirror are returned instead.
+// Otherwise, or if the GitHub release has no attestations, the sigstore bundle stored next to checksums.txt is used if any.
+func (c *InstallConfig) fetchChecksums(ctx context.Context, source releaseSource) ([]byte, []*github.Attestation, *github.Repository, error) {
 	log.Printf("[DEBUG] Download checksums.txt")
-	checksumsFile, err := c.downloadToTempFile(ctx, client, assets["checksums.txt"])
+	checksumsFile, err := source.download(ctx, "checksums.txt")
 	if checksumsFile != nil {
 		defer os.Remove(checksumsFile.Name())
 	}
 	if err != nil {
-		return assets, nil, nil, nil, fmt.Errorf("Failed to download checksums.txt: %s", err)
+		return nil, nil, nil, fmt.Errorf("Failed to download checksums.txt: %s", err)
 	}
 	checksum, err := io.ReadAll(checksumsFile)
 	if err != nil {
-		return assets, nil, nil, nil, fmt.Errorf("Failed to read checksums.txt: %s", err)
+		return nil, nil, nil, fmt.Errorf("Failed to read checksums.txt: %s", err)
 	}
 
+	if mirror, ok := source.(*mirrorSource); ok {
+		attestations, err := mirror.attestations()
+		if err != nil {
+			return checksum, nil, nil, fmt.Errorf("Failed to read artifact attestations from the mirror: %s", err)
+		}
+		return checksum, attestations, nil, nil
+	}
+
+	gh, ok := source.(*githubSource)
+	if !ok {
+		log.Printf("[DEBUG] Artifact attestations are only supported on GitHub, looking for a sigstore bundle")
+		attestations, err := c.fetchSigstoreBundle(ctx, source)
+		return checksum, attestations, nil, err
+	}
 	// GitHub Enterprise Server does not support Artifact Attestations
 	if c.SourceHost != defaultSourceHost {
-		log.Printf("[DEBUG] Artifact attestations are not supported on GitHub Enterprise Server and will be ignored")
-		return assets, checksum, nil, nil, nil
+		log.Printf("[DEBUG] Artifact attestations are not supported on GitHub Enterprise Server, looking for a sigstore bundle")
+		attestations, err := c.fetchSigstoreBundle(ctx, source)
+		return checksum, attestations, nil, err
 	}
-	attestations, err := c.fetchArtifactAttestations(ctx, client, checksum)
+	attestations, err := c.fetchArtifactAttestations(ctx, gh.client, checksum)
 	if err != nil {
 		// If attestations are not available or not accessible, ignore them and
 		// continue with the remaining verification flow.
 		if isIgnorableAttestationError(err) {
 			log.Printf("[DEBUG] Artifact attestations unavailable and will be ignored: %s", err)
-			return assets, checksum, nil, nil, nil
+			attestations = nil
 		} else {
-			return assets, checksum, nil, nil, fmt.Errorf("Failed to download artifact attestations: %s", err)
+			return checksum, nil, nil, fmt.Errorf("Failed to download artifact attestations: %s", err)
+		}
+	}
+	if len(attestations) == 0 {
+		if attestations, err = c.fetchSigstoreBundle(ctx, source); err != nil {
+			return checksum, nil, nil, err
+		}
+		if len(attestations) == 0 {
+			return checksum, nil, nil, nil
 		}
 	}
 
-	repo, err := c.fetchRepository(ctx, client)
+	repo, err := c.fetchRepository(ctx, gh.client)
 	if err != nil {
-		return assets, checksum, attestations, nil, fmt.Errorf("Failed to get GitHub repository metadata: %s", err)
+		return checksum, attestations, nil, fmt.Errorf("Failed to get GitHub repository metadata: %s", err)
+	}
+
+	return checksum, attestations, repo, nil
+}
+
+// fetchSigstoreBundle downloads the sigstore bundle of checksums.txt stored next to the release assets,
+// like the output of "cosign sign-blob --bundle checksums.txt.sigstore.json checksums.txt".
+// The bundle is returned as an attestation so that it can be verified in the same way as artifact attestations.
+// Returns nil if the release does not contain the bundle.
+func (c *InstallConfig) fetchSigstoreBundle(ctx context.Context, source releaseSource) ([]*github.Attestation, error) {
+	if gh, ok := source.(*githubSource); ok {
+		if _, exists := gh.assets[sigstoreBundleFileName]; !exists {
+			return nil, nil
+		}
 	}
 
-	return assets, checksum, attestations, repo, nil
+	log.Printf("[DEBUG] Download %s", sigstoreBundleFileName)
+	bundleFile, err := source.download(ctx, sigstoreBundleFileName)
+	if bundleFile != nil {
+		defer os.Remove(bundleFile.Name())
+	}
+	if err != nil {
+		if errors.Is(err, errAssetNotFound) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("Failed to download %s: %s", sigstoreBundleFileName, err)
+	}
+	content, err := io.ReadAll(bundleFile)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to read %s: %s", sigstoreBundleFileName, err)
+	}
+	return []*github.Attestation{{Bundle: content}}, nil
 }
 
 // fetchRepository fetches GitHub repository metadata.
@@ -410,19 +618,7 @@ func (c *InstallConfig) downloadToTempFile(ctx context.Context, client *github.C
 	}
 	defer downloader.Close()
 
-	file, err := os.CreateTemp("", "tflint-download-temp-file-*")
-	if err != nil {
-		return nil, err
-	}
-	if _, err = io.Copy(file, downloader); err != nil {
-		return file, err
-	}
-	if _, err := file.Seek(0, 0); err != nil {
-		return file, err
-	}
-
-	log.Printf("[DEBUG] Downloaded to %s", file.Name())
-	return file, nil
+	return copyToTempFile(downloader)
 }
 
 // getGitHubToken gets a GitHub access token from environment variables.
@@ -202,6 +202,23 @@ func TestInstallConfigSignatureMode(t *testing.T) {
 			},
 			want: SignatureModeNone,
 		},
+		{
+			name:   "auto verifies private repo attestations with custom identity",
+			config: NewInstallConfig(tflint.EmptyConfig(), &tflint.PluginConfig{CertificateIdentityRegexp: "^https://github.com/example/"}),
+			repo:   &github.Repository{Private: &private},
+			attestations: []*github.Attestation{
+				{},
+			},
+			want: SignatureModeAttestation,
+		},
+		{
+			name:   "auto skips attestations without identity in non-GitHub sources",
+			config: NewInstallConfig(tflint.EmptyConfig(), &tflint.PluginConfig{SourceType: tflint.SourceTypeFile}),
+			attestations: []*github.Attestation{
+				{},
+			},
+			want: SignatureModeNone,
+		},
 		{
 			name:   "forced attestation",
 			config: NewInstallConfig(tflint.EmptyConfig(), &tflint.PluginConfig{Signature: "attestation", SigningKey: testSigningKey}),
@@ -231,6 +248,47 @@ func TestInstallConfigSignatureMode(t *testing.T) {
 	}
 }
 
+func TestInstallConfigCertificateIdentity(t *testing.T) {
+	tests := []struct {
+		name       string
+		config     *tflint.PluginConfig
+		wantSAN    string
+		wantIssuer string
+	}{
+		{
+			name:       "GitHub source",
+			config:     &tflint.PluginConfig{SourceHost: "github.com", SourceOwner: "terraform-linters", SourceRepo: "tflint-ruleset-aws"},
+			wantSAN:    `^https://github\.com/terraform-linters/tflint-ruleset-aws/`,
+			wantIssuer: "https://token.actions.githubusercontent.com",
+		},
+		{
+			name:       "custom identity",
+			config:     &tflint.PluginConfig{SourceHost: "github.com", SourceOwner: "terraform-linters", SourceRepo: "tflint-ruleset-aws", CertificateIdentityRegexp: "^https://ci.example.com/", CertificateOIDCIssuer: "https://oidc.example.com"},
+			wantSAN:    "^https://ci.example.com/",
+			wantIssuer: "https://oidc.example.com",
+		},
+		{
+			name:       "non-GitHub source",
+			config:     &tflint.PluginConfig{SourceType: tflint.SourceTypeGitLab, SourceHost: "gitlab.com", SourceOwner: "foo", SourceRepo: "bar"},
+			wantSAN:    "",
+			wantIssuer: "https://token.actions.githubusercontent.com",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			config := NewInstallConfig(tflint.EmptyConfig(), test.config)
+
+			if got := config.CertificateIdentitySANRegex(); got != test.wantSAN {
+				t.Errorf("unexpected SAN regex: want %s, got %s", test.wantSAN, got)
+			}
+			if got := config.CertificateIdentityIssuer(); got != test.wantIssuer {
+				t.Errorf("unexpected issuer: want %s, got %s", test.wantIssuer, got)
+			}
+		})
+	}
+}
+
 func TestNewGitHubClient(t *testing.T) {
 	cases := []struct {
 		name     string
@@ -0,0 +1,128 @@
+package plugin
+
+import (
+	"fmt"
+	"io/fs"
+	"log"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/go-version"
+	"github.com/terraform-linters/tflint-plugin-sdk/plugin/host2plugin"
+)
+
+// InstalledPlugin is a plugin binary found in the plugin directory.
+//
+// Automatically installed plugins are placed as "[plugin dir]/[source dir]/[version]/tflint-ruleset-[name]".
+// Plugins placed directly under the plugin directory are manually installed, and have no source and version.
+type InstalledPlugin struct {
+	Name string
+	// SourceDir is the slash-separated directory of the source, like "github.com/terraform-linters/tflint-ruleset-aws".
+	// For GitHub sources, it is the same as the source.
+	SourceDir string
+	Version   string
+	Path      string
+}
+
+// ManuallyInstalled returns whether the plugin is placed directly under the plugin directory.
+func (p *InstalledPlugin) ManuallyInstalled() bool {
+	return p.Version == ""
+}
+
+// FindInstalledPlugins returns all plugins installed in the plugin directory, sorted by path.
+// If the directory does not exist, it returns no plugins.
+func FindInstalledPlugins(dir string) ([]*InstalledPlugin, error) {
+	plugins := []*InstalledPlugin{}
+
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if path == dir && os.IsNotExist(err) {
+				return filepath.SkipAll
+			}
+			return err
+		}
+		if d.IsDir() || !strings.HasPrefix(d.Name(), "tflint-ruleset-") {
+			return nil
+		}
+
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		name := strings.TrimSuffix(strings.TrimPrefix(d.Name(), "tflint-ruleset-"), ".exe")
+		installed := &InstalledPlugin{Name: name, Path: path}
+
+		// [source dir]/[version]/tflint-ruleset-[name]
+		parts := strings.Split(filepath.ToSlash(rel), "/")
+		if len(parts) >= 3 {
+			installed.Version = parts[len(parts)-2]
+			installed.SourceDir = strings.Join(parts[:len(parts)-2], "/")
+		} else if len(parts) != 1 {
+			log.Printf("[DEBUG] Ignore %s as it is not in the plugin directory layout", path)
+			return nil
+		}
+		plugins = append(plugins, installed)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("Failed to find plugins in %s: %w", dir, err)
+	}
+
+	sort.Slice(plugins, func(i, j int) bool { return plugins[i].Path < plugins[j].Path })
+	return plugins, nil
+}
+
+// SDKVersion launches the plugin and returns the version of tflint-plugin-sdk it is built with.
+// Returns nil if the plugin is too old to report it (SDK < 0.14).
+func (p *InstalledPlugin) SDKVersion() (*version.Version, error) {
+	client := host2plugin.NewClient(&host2plugin.ClientOpts{Cmd: exec.Command(p.Path)})
+	defer client.Kill()
+
+	rpcClient, err := client.Client()
+	if err != nil {
+		return nil, err
+	}
+	raw, err := rpcClient.Dispense("ruleset")
+	if err != nil {
+		return nil, err
+	}
+
+	sdkVersion, err := raw.(*host2plugin.Client).SDKVersion()
+	if err != nil {
+		if IsSDKVersionUnimplemented(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return sdkVersion, nil
+}
+
+// Uninstall removes the version directory of the plugin, and empty parent directories up to the plugin directory.
+// Manually installed plugins cannot be uninstalled.
+func (p *InstalledPlugin) Uninstall(dir string) error {
+	if p.ManuallyInstalled() {
+		return fmt.Errorf(`Plugin "%s" is installed manually and cannot be uninstalled`, p.Name)
+	}
+
+	versionDir := filepath.Dir(p.Path)
+	if err := os.RemoveAll(versionDir); err != nil {
+		return err
+	}
+	log.Printf("[DEBUG] Removed %s", versionDir)
+
+	root := filepath.Clean(dir)
+	for parent := filepath.Dir(versionDir); parent != root && strings.HasPrefix(parent, root); parent = filepath.Dir(parent) {
+		entries, err := os.ReadDir(parent)
+		if err != nil || len(entries) > 0 {
+			break
+		}
+		if err := os.Remove(parent); err != nil {
+			return err
+		}
+		log.Printf("[DEBUG] Removed %s", parent)
+	}
+	return nil
+}
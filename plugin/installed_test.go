@@ -0,0 +1,68 @@
+package plugin
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func Test_FindInstalledPlugins(t *testing.T) {
+	dir := t.TempDir()
+	for _, path := range []string{
+		"tflint-ruleset-manual",
+		"github.com/terraform-linters/tflint-ruleset-aws/0.37.0/tflint-ruleset-aws",
+		"github.com/terraform-linters/tflint-ruleset-aws/0.38.2/tflint-ruleset-aws.exe",
+		"https/mirror.example.com_8443/tflint-ruleset-foo/0.1.0/tflint-ruleset-foo",
+		"github.com/terraform-linters/tflint-ruleset-aws/0.38.2/README.md",
+	} {
+		path = filepath.Join(dir, filepath.FromSlash(path))
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte("binary"), 0o755); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	got, err := FindInstalledPlugins(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []*InstalledPlugin{
+		{Name: "aws", SourceDir: "github.com/terraform-linters/tflint-ruleset-aws", Version: "0.37.0", Path: filepath.Join(dir, "github.com", "terraform-linters", "tflint-ruleset-aws", "0.37.0", "tflint-ruleset-aws")},
+		{Name: "aws", SourceDir: "github.com/terraform-linters/tflint-ruleset-aws", Version: "0.38.2", Path: filepath.Join(dir, "github.com", "terraform-linters", "tflint-ruleset-aws", "0.38.2", "tflint-ruleset-aws.exe")},
+		{Name: "foo", SourceDir: "https/mirror.example.com_8443/tflint-ruleset-foo", Version: "0.1.0", Path: filepath.Join(dir, "https", "mirror.example.com_8443", "tflint-ruleset-foo", "0.1.0", "tflint-ruleset-foo")},
+		{Name: "manual", Path: filepath.Join(dir, "tflint-ruleset-manual")},
+	}
+	if diff := cmp.Diff(expected, got); diff != "" {
+		t.Fatal(diff)
+	}
+
+	// Uninstalling the last version removes empty source directories
+	if err := got[2].Uninstall(dir); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "https")); !os.IsNotExist(err) {
+		t.Errorf("expected the empty source directory to be removed, but got %v", err)
+	}
+	if err := got[0].Uninstall(dir); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "github.com", "terraform-linters", "tflint-ruleset-aws", "0.38.2")); err != nil {
+		t.Errorf("expected other versions not to be removed, but got %s", err)
+	}
+	if err := got[3].Uninstall(dir); err == nil {
+		t.Error("expected manually installed plugins not to be uninstalled, but got no errors")
+	}
+
+	// The plugin directory does not exist
+	got, err = FindInstalledPlugins(filepath.Join(dir, "not_found"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 0 {
+		t.Errorf("expected no plugins, but got %d plugins", len(got))
+	}
+}
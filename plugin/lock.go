@@ -0,0 +1,366 @@
+package plugin
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2/gohcl"
+	"github.com/hashicorp/hcl/v2/hclparse"
+	"github.com/hashicorp/hcl/v2/hclwrite"
+	"github.com/terraform-linters/tflint/tflint"
+	"github.com/zclconf/go-cty/cty"
+)
+
+// LockFileName is the name of the plugin dependency lock file.
+const LockFileName = ".tflint.lock.hcl"
+
+const lockFileHeader = `# This file is maintained automatically by "tflint --init".
+# Manual edits may be lost in future updates.
+`
+
+// hashPrefix is the prefix of hashes in the lock file, which indicates the hash algorithm.
+const hashPrefix = "sha256:"
+
+// LockFile is a plugin dependency lock file like Terraform's .terraform.lock.hcl.
+// It records the source, the exact version, and the hashes of each plugin,
+// so that installed plugins can be verified before launching them.
+type LockFile struct {
+	Plugins map[string]*LockedPlugin
+
+	path   string
+	exists bool
+}
+
+// LockedPlugin is a plugin recorded in the lock file.
+type LockedPlugin struct {
+	Name    string
+	Source  string
+	Version string
+	// Constraints is the version constraint that the version is resolved from, if any.
+	Constraints string
+	// Platforms are the hashes of the release assets keyed by platforms like "linux_amd64".
+	Platforms map[string]*PlatformHashes
+}
+
+// PlatformHashes are the SHA-256 hashes of the release asset for a platform.
+// Zip is the hash of the zip file in the release, which is taken from checksums.txt.
+// Binary is the hash of the plugin binary extracted from the zip file, which is verified before launching.
+type PlatformHashes struct {
+	Zip    string
+	Binary string
+}
+
+type lockFileSchema struct {
+	Plugins []*lockedPluginSchema `hcl:"plugin,block"`
+}
+
+type lockedPluginSchema struct {
+	Name        string            `hcl:"name,label"`
+	Source      string            `hcl:"source"`
+	Version     string            `hcl:"version"`
+	Constraints string            `hcl:"constraints,optional"`
+	Platforms   []*platformSchema `hcl:"platform,block"`
+}
+
+type platformSchema struct {
+	Name   string `hcl:"name,label"`
+	Zip    string `hcl:"zip,optional"`
+	Binary string `hcl:"binary,optional"`
+}
+
+// LockFilePath returns the path of the lock file for the config.
+// The lock file is placed next to the config file, or in the current directory if the default config is used.
+func LockFilePath(config *tflint.Config) string {
+	if config.Path() == "" {
+		return LockFileName
+	}
+	return filepath.Join(filepath.Dir(config.Path()), LockFileName)
+}
+
+// LoadLockFile loads the lock file from the passed path.
+// If the file does not exist, it returns an empty lock file that will be created on save.
+func LoadLockFile(path string) (*LockFile, error) {
+	lock := &LockFile{Plugins: map[string]*LockedPlugin{}, path: path}
+
+	src, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			log.Printf("[DEBUG] Lock file not found: %s", path)
+			return lock, nil
+		}
+		return nil, fmt.Errorf("Failed to read %s: %w", path, err)
+	}
+	log.Printf("[INFO] Load lock file: %s", path)
+	lock.exists = true
+
+	file, diags := hclparse.NewParser().ParseHCL(src, path)
+	if diags.HasErrors() {
+		return nil, diags
+	}
+	var schema lockFileSchema
+	if diags := gohcl.DecodeBody(file.Body, nil, &schema); diags.HasErrors() {
+		return nil, diags
+	}
+
+	for _, p := range schema.Plugins {
+		if _, exists := lock.Plugins[p.Name]; exists {
+			return nil, fmt.Errorf(`%s: plugin "%s" is duplicated`, path, p.Name)
+		}
+		locked := &LockedPlugin{Name: p.Name, Source: p.Source, Version: p.Version, Constraints: p.Constraints, Platforms: map[string]*PlatformHashes{}}
+		for _, platform := range p.Platforms {
+			hashes := &PlatformHashes{}
+			if hashes.Zip, err = parseHash(platform.Zip); err != nil {
+				return nil, fmt.Errorf(`%s: plugin "%s": platform "%s": %w`, path, p.Name, platform.Name, err)
+			}
+			if hashes.Binary, err = parseHash(platform.Binary); err != nil {
+				return nil, fmt.Errorf(`%s: plugin "%s": platform "%s": %w`, path, p.Name, platform.Name, err)
+			}
+			locked.Platforms[platform.Name] = hashes
+		}
+		lock.Plugins[p.Name] = locked
+	}
+
+	return lock, nil
+}
+
+func parseHash(hash string) (string, error) {
+	if hash == "" {
+		return "", nil
+	}
+	if !strings.HasPrefix(hash, hashPrefix) {
+		return "", fmt.Errorf(`"%s" is invalid hash. Hashes must be prefixed with "%s"`, hash, hashPrefix)
+	}
+	hash = strings.TrimPrefix(hash, hashPrefix)
+	if _, err := hex.DecodeString(hash); err != nil {
+		return "", fmt.Errorf(`"%s" is invalid hash: %w`, hash, err)
+	}
+	return hash, nil
+}
+
+// Exists returns whether the lock file exists.
+// If it does not exist, installed plugins are not verified.
+func (l *LockFile) Exists() bool {
+	return l.exists
+}
+
+// Path returns the path of the lock file.
+func (l *LockFile) Path() string {
+	return l.path
+}
+
+// Locked returns the entry of the plugin if the lock file records the same source and version.
+// Returns nil if the plugin is not recorded, or the recorded entry is for another source or version.
+func (l *LockFile) Locked(config *InstallConfig) *LockedPlugin {
+	locked, exists := l.Plugins[config.Name]
+	if !exists || locked.Source != config.Source || locked.Version != config.Version {
+		return nil
+	}
+	return locked
+}
+
+// Verify checks the installed plugin binary against the hash recorded in the lock file.
+// If the lock file does not exist, the plugin is not verified for backward compatibility.
+// Plugins that are installed manually are not recorded in the lock file and are not verified.
+func (l *LockFile) Verify(config *InstallConfig, path string) error {
+	if !l.exists || config.ManuallyInstalled() {
+		return nil
+	}
+
+	locked, exists := l.Plugins[config.Name]
+	if !exists {
+		return fmt.Errorf(`Plugin "%s" is not recorded in %s. Run "tflint --init" to update the lock file`, config.Name, l.path)
+	}
+	if locked.Source != config.Source || locked.Version != config.Version {
+		return fmt.Errorf(
+			`Plugin "%s" is locked to %s %s in %s, but %s %s is configured. Run "tflint --init" to update the lock file`,
+			config.Name, locked.Source, locked.Version, l.path, config.Source, config.Version,
+		)
+	}
+	hashes, exists := locked.Platforms[currentPlatform()]
+	if !exists || hashes.Binary == "" {
+		return fmt.Errorf(`%s does not record the hash of plugin "%s" for %s. Run "tflint --init" to update the lock file`, l.path, config.Name, currentPlatform())
+	}
+
+	actual, err := fileHash(path)
+	if err != nil {
+		return fmt.Errorf(`Failed to calculate the hash of plugin "%s": %w`, config.Name, err)
+	}
+	if actual != hashes.Binary {
+		return fmt.Errorf(
+			`Plugin "%s" does not match the hash in %s: expected=%s, actual=%s. The plugin binary may have been tampered with. Run "tflint --init" to reinstall it`,
+			config.Name, l.path, hashes.Binary, actual,
+		)
+	}
+	log.Printf(`[DEBUG] Plugin "%s" matched the hash in %s`, config.Name, l.path)
+	return nil
+}
+
+// Save writes the lock file. Plugins and platforms are sorted by name, so that the output is stable.
+// If the content is not changed, the file is not written. Returns whether the file is written.
+// An empty lock file is not created if it does not exist.
+func (l *LockFile) Save() (bool, error) {
+	if !l.exists && len(l.Plugins) == 0 {
+		return false, nil
+	}
+
+	src := l.Bytes()
+	if current, err := os.ReadFile(l.path); err == nil && string(current) == string(src) {
+		return false, nil
+	}
+
+	if err := os.WriteFile(l.path, src, 0o644); err != nil {
+		return false, fmt.Errorf("Failed to write %s: %w", l.path, err)
+	}
+	l.exists = true
+	log.Printf("[INFO] Lock file saved: %s", l.path)
+	return true, nil
+}
+
+// Bytes returns the content of the lock file in HCL.
+func (l *LockFile) Bytes() []byte {
+	file := hclwrite.NewEmptyFile()
+	body := file.Body()
+
+	names := make([]string, 0, len(l.Plugins))
+	for name := range l.Plugins {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		locked := l.Plugins[name]
+
+		body.AppendNewline()
+		block := body.AppendNewBlock("plugin", []string{name}).Body()
+		block.SetAttributeValue("source", cty.StringVal(locked.Source))
+		block.SetAttributeValue("version", cty.StringVal(locked.Version))
+		if locked.Constraints != "" {
+			block.SetAttributeValue("constraints", cty.StringVal(locked.Constraints))
+		}
+
+		platforms := make([]string, 0, len(locked.Platforms))
+		for platform := range locked.Platforms {
+			platforms = append(platforms, platform)
+		}
+		sort.Strings(platforms)
+
+		for _, platform := range platforms {
+			hashes := locked.Platforms[platform]
+
+			block.AppendNewline()
+			platformBlock := block.AppendNewBlock("platform", []string{platform}).Body()
+			if hashes.Zip != "" {
+				platformBlock.SetAttributeValue("zip", cty.StringVal(hashPrefix+hashes.Zip))
+			}
+			if hashes.Binary != "" {
+				platformBlock.SetAttributeValue("binary", cty.StringVal(hashPrefix+hashes.Binary))
+			}
+		}
+	}
+
+	return append([]byte(lockFileHeader), hclwrite.Format(file.Bytes())...)
+}
+
+// lockedPluginFromChecksums returns a new entry of the plugin with the zip hashes of all platforms in checksums.txt.
+// Binary hashes are not included since they require downloading the zip files.
+func (c *InstallConfig) lockedPluginFromChecksums(checksummer *Checksummer) *LockedPlugin {
+	locked := &LockedPlugin{Name: c.Name, Source: c.Source, Version: c.Version, Platforms: map[string]*PlatformHashes{}}
+	if c.HasVersionConstraints() {
+		locked.Constraints = c.VersionConstraints.String()
+	}
+
+	prefix := fmt.Sprintf("tflint-ruleset-%s_", c.Name)
+	for filename, checksum := range checksummer.checksums {
+		if !strings.HasPrefix(filename, prefix) || filepath.Ext(filename) != ".zip" {
+			continue
+		}
+		platform := strings.TrimSuffix(strings.TrimPrefix(filename, prefix), ".zip")
+		locked.Platforms[platform] = &PlatformHashes{Zip: hex.EncodeToString(checksum)}
+	}
+	return locked
+}
+
+// verifyLockedZipHashes checks that the zip hashes in checksums.txt match the previously locked hashes.
+// This detects releases whose assets have been replaced after locking.
+func verifyLockedZipHashes(previous *LockedPlugin, current *LockedPlugin) error {
+	for platform, hashes := range previous.Platforms {
+		if hashes.Zip == "" {
+			continue
+		}
+		if got, exists := current.Platforms[platform]; !exists || got.Zip != hashes.Zip {
+			return fmt.Errorf(`checksums.txt does not match the lock file for %s. The release may have been modified after locking`, platform)
+		}
+	}
+	return nil
+}
+
+// MergeLockedPlugins merges the entries of the same plugin recorded in multiple lock files,
+// so that a plugin installed once for multiple working directories is verified against all of them.
+// Nil entries are ignored, and nil is returned if there are no entries.
+func MergeLockedPlugins(entries []*LockedPlugin) (*LockedPlugin, error) {
+	var merged *LockedPlugin
+	for _, entry := range entries {
+		if entry == nil {
+			continue
+		}
+		if merged == nil {
+			merged = &LockedPlugin{Name: entry.Name, Source: entry.Source, Version: entry.Version, Constraints: entry.Constraints, Platforms: map[string]*PlatformHashes{}}
+		}
+
+		for platform, hashes := range entry.Platforms {
+			current, exists := merged.Platforms[platform]
+			if !exists {
+				merged.Platforms[platform] = &PlatformHashes{Zip: hashes.Zip, Binary: hashes.Binary}
+				continue
+			}
+			if current.Zip != "" && hashes.Zip != "" && current.Zip != hashes.Zip {
+				return nil, fmt.Errorf(`lock files have different hashes of plugin "%s" for %s`, entry.Name, platform)
+			}
+			if current.Zip == "" {
+				current.Zip = hashes.Zip
+			}
+			if current.Binary == "" {
+				current.Binary = hashes.Binary
+			}
+		}
+	}
+	return merged, nil
+}
+
+// WithConstraints returns a copy of the entry whose version constraint is taken from the passed config.
+// The hashes are shared with the original entry.
+func (l *LockedPlugin) WithConstraints(c *InstallConfig) *LockedPlugin {
+	locked := *l
+	locked.Constraints = ""
+	if c.HasVersionConstraints() {
+		locked.Constraints = c.VersionConstraints.String()
+	}
+	return &locked
+}
+
+func currentPlatform() string {
+	return fmt.Sprintf("%s_%s", runtime.GOOS, runtime.GOARCH)
+}
+
+// fileHash returns the hex-encoded SHA-256 hash of the file.
+func fileHash(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	hash := sha256.New()
+	if _, err := io.Copy(hash, f); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
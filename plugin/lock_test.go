@@ -0,0 +1,301 @@
+package plugin
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/terraform-linters/tflint/tflint"
+)
+
+func Test_LockFile_roundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), LockFileName)
+
+	lock, err := LoadLockFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if lock.Exists() {
+		t.Fatal("expected the lock file not to exist")
+	}
+
+	lock.Plugins["aws"] = &LockedPlugin{
+		Name:        "aws",
+		Source:      "github.com/terraform-linters/tflint-ruleset-aws",
+		Version:     "0.21.1",
+		Constraints: "~> 0.21",
+		Platforms: map[string]*PlatformHashes{
+			"linux_amd64":  {Zip: "482419fdeed00692304e59558b5b0d915d4727868b88a5adbbbb76f5ed1b537a", Binary: "3a61fff3689f27c89bce22893219919c629d2e10b96e7eadd5fef9f0e90bb353"},
+			"darwin_amd64": {Zip: "db4eed4c0abcfb0b851da5bbfe8d0c71e1c2b6afe4fd627638a462c655045902"},
+		},
+	}
+	saved, err := lock.Save()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !saved {
+		t.Fatal("expected the lock file to be saved")
+	}
+
+	expected := `# This file is maintained automatically by "tflint --init".
+# Manual edits may be lost in future updates.
+
+plugin "aws" {
+  source      = "github.com/terraform-linters/tflint-ruleset-aws"
+  version     = "0.21.1"
+  constraints = "~> 0.21"
+
+  platform "darwin_amd64" {
+    zip = "sha256:db4eed4c0abcfb0b851da5bbfe8d0c71e1c2b6afe4fd627638a462c655045902"
+  }
+
+  platform "linux_amd64" {
+    zip    = "sha256:482419fdeed00692304e59558b5b0d915d4727868b88a5adbbbb76f5ed1b537a"
+    binary = "sha256:3a61fff3689f27c89bce22893219919c629d2e10b96e7eadd5fef9f0e90bb353"
+  }
+}
+`
+	src, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(expected, string(src)); diff != "" {
+		t.Fatal(diff)
+	}
+
+	loaded, err := LoadLockFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !loaded.Exists() {
+		t.Fatal("expected the lock file to exist")
+	}
+	if diff := cmp.Diff(lock.Plugins, loaded.Plugins); diff != "" {
+		t.Fatal(diff)
+	}
+
+	// Unchanged lock files are not written
+	saved, err = loaded.Save()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if saved {
+		t.Fatal("expected the unchanged lock file not to be saved")
+	}
+}
+
+func Test_LoadLockFile_invalid(t *testing.T) {
+	cases := []struct {
+		Name     string
+		Content  string
+		Expected string
+	}{
+		{
+			Name: "invalid hash prefix",
+			Content: `plugin "aws" {
+  source  = "github.com/terraform-linters/tflint-ruleset-aws"
+  version = "0.21.1"
+
+  platform "linux_amd64" {
+    zip = "md5:d41d8cd98f00b204e9800998ecf8427e"
+  }
+}`,
+			Expected: `plugin "aws": platform "linux_amd64": "md5:d41d8cd98f00b204e9800998ecf8427e" is invalid hash. Hashes must be prefixed with "sha256:"`,
+		},
+		{
+			Name: "duplicate plugins",
+			Content: `plugin "aws" {
+  source  = "github.com/terraform-linters/tflint-ruleset-aws"
+  version = "0.21.1"
+}
+plugin "aws" {
+  source  = "github.com/terraform-linters/tflint-ruleset-aws"
+  version = "0.22.0"
+}`,
+			Expected: `plugin "aws" is duplicated`,
+		},
+		{
+			Name: "missing version",
+			Content: `plugin "aws" {
+  source = "github.com/terraform-linters/tflint-ruleset-aws"
+}`,
+			Expected: `The argument "version" is required`,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.Name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), LockFileName)
+			if err := os.WriteFile(path, []byte(tc.Content), 0o644); err != nil {
+				t.Fatal(err)
+			}
+
+			_, err := LoadLockFile(path)
+			if err == nil {
+				t.Fatal("expected an error, but got no errors")
+			}
+			if !strings.Contains(err.Error(), tc.Expected) {
+				t.Errorf("expected to contain %q, but got %q", tc.Expected, err)
+			}
+		})
+	}
+}
+
+func Test_LockFile_Verify(t *testing.T) {
+	dir := t.TempDir()
+	binary := filepath.Join(dir, "tflint-ruleset-aws")
+	if err := os.WriteFile(binary, []byte("plugin binary"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	hash, err := fileHash(binary)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	installCfg := NewInstallConfig(tflint.EmptyConfig(), &tflint.PluginConfig{
+		Name:    "aws",
+		Enabled: true,
+		Source:  "github.com/terraform-linters/tflint-ruleset-aws",
+		Version: "0.21.1",
+	})
+	locked := func(version string, binaryHash string) map[string]*LockedPlugin {
+		return map[string]*LockedPlugin{
+			"aws": {
+				Name:      "aws",
+				Source:    "github.com/terraform-linters/tflint-ruleset-aws",
+				Version:   version,
+				Platforms: map[string]*PlatformHashes{currentPlatform(): {Binary: binaryHash}},
+			},
+		}
+	}
+
+	cases := []struct {
+		Name     string
+		Lock     *LockFile
+		Expected string
+	}{
+		{
+			Name: "no lock file",
+			Lock: &LockFile{Plugins: map[string]*LockedPlugin{}, path: LockFileName},
+		},
+		{
+			Name: "matched",
+			Lock: &LockFile{Plugins: locked("0.21.1", hash), path: LockFileName, exists: true},
+		},
+		{
+			Name:     "not recorded",
+			Lock:     &LockFile{Plugins: map[string]*LockedPlugin{}, path: LockFileName, exists: true},
+			Expected: `Plugin "aws" is not recorded in .tflint.lock.hcl. Run "tflint --init" to update the lock file`,
+		},
+		{
+			Name:     "version mismatch",
+			Lock:     &LockFile{Plugins: locked("0.20.0", hash), path: LockFileName, exists: true},
+			Expected: `Plugin "aws" is locked to github.com/terraform-linters/tflint-ruleset-aws 0.20.0 in .tflint.lock.hcl, but github.com/terraform-linters/tflint-ruleset-aws 0.21.1 is configured. Run "tflint --init" to update the lock file`,
+		},
+		{
+			Name:     "no hash for the platform",
+			Lock:     &LockFile{Plugins: locked("0.21.1", ""), path: LockFileName, exists: true},
+			Expected: `.tflint.lock.hcl does not record the hash of plugin "aws" for ` + currentPlatform() + `. Run "tflint --init" to update the lock file`,
+		},
+		{
+			Name:     "hash mismatch",
+			Lock:     &LockFile{Plugins: locked("0.21.1", "3a61fff3689f27c89bce22893219919c629d2e10b96e7eadd5fef9f0e90bb353"), path: LockFileName, exists: true},
+			Expected: `Plugin "aws" does not match the hash in .tflint.lock.hcl: expected=3a61fff3689f27c89bce22893219919c629d2e10b96e7eadd5fef9f0e90bb353, actual=` + hash + `. The plugin binary may have been tampered with. Run "tflint --init" to reinstall it`,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.Name, func(t *testing.T) {
+			err := tc.Lock.Verify(installCfg, binary)
+			if tc.Expected == "" {
+				if err != nil {
+					t.Fatalf("expected no errors, but got %s", err)
+				}
+				return
+			}
+			if err == nil {
+				t.Fatalf("expected=%s, actual=no errors", tc.Expected)
+			}
+			if err.Error() != tc.Expected {
+				t.Errorf("expected=%s, actual=%s", tc.Expected, err)
+			}
+		})
+	}
+}
+
+func Test_lockedPluginFromChecksums(t *testing.T) {
+	checksummer, err := NewChecksummer(strings.NewReader(`3a61fff3689f27c89bce22893219919c629d2e10b96e7eadd5fef9f0e90bb353  tflint-ruleset-aws_darwin_amd64.zip
+482419fdeed00692304e59558b5b0d915d4727868b88a5adbbbb76f5ed1b537a  tflint-ruleset-aws_linux_amd64.zip
+db4eed4c0abcfb0b851da5bbfe8d0c71e1c2b6afe4fd627638a462c655045902  tflint-ruleset-aws_windows_amd64.zip
+d41d8cd98f00b204e9800998ecf8427ed41d8cd98f00b204e9800998ecf8427e  tflint-ruleset-aws_linux_amd64.tar.gz
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	installCfg := NewInstallConfig(tflint.EmptyConfig(), &tflint.PluginConfig{
+		Name:    "aws",
+		Source:  "github.com/terraform-linters/tflint-ruleset-aws",
+		Version: "0.21.1",
+	})
+
+	got := installCfg.lockedPluginFromChecksums(checksummer)
+	expected := &LockedPlugin{
+		Name:    "aws",
+		Source:  "github.com/terraform-linters/tflint-ruleset-aws",
+		Version: "0.21.1",
+		Platforms: map[string]*PlatformHashes{
+			"darwin_amd64":  {Zip: "3a61fff3689f27c89bce22893219919c629d2e10b96e7eadd5fef9f0e90bb353"},
+			"linux_amd64":   {Zip: "482419fdeed00692304e59558b5b0d915d4727868b88a5adbbbb76f5ed1b537a"},
+			"windows_amd64": {Zip: "db4eed4c0abcfb0b851da5bbfe8d0c71e1c2b6afe4fd627638a462c655045902"},
+		},
+	}
+	if diff := cmp.Diff(expected, got); diff != "" {
+		t.Fatal(diff)
+	}
+
+	// The release has been modified after locking
+	previous := &LockedPlugin{Platforms: map[string]*PlatformHashes{"linux_amd64": {Zip: "3a61fff3689f27c89bce22893219919c629d2e10b96e7eadd5fef9f0e90bb353"}}}
+	if err := verifyLockedZipHashes(previous, got); err == nil {
+		t.Fatal("expected an error, but got no errors")
+	}
+	if err := verifyLockedZipHashes(&LockedPlugin{Platforms: map[string]*PlatformHashes{"linux_amd64": {Zip: "482419fdeed00692304e59558b5b0d915d4727868b88a5adbbbb76f5ed1b537a"}}}, got); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func Test_MergeLockedPlugins(t *testing.T) {
+	merged, err := MergeLockedPlugins([]*LockedPlugin{
+		nil,
+		{Name: "foo", Source: "github.com/example/tflint-ruleset-foo", Version: "0.1.0", Platforms: map[string]*PlatformHashes{
+			"linux_amd64": {Zip: "aaa", Binary: "bbb"},
+		}},
+		{Name: "foo", Source: "github.com/example/tflint-ruleset-foo", Version: "0.1.0", Platforms: map[string]*PlatformHashes{
+			"linux_amd64":  {Zip: "aaa"},
+			"darwin_arm64": {Zip: "ccc", Binary: "ddd"},
+		}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := &LockedPlugin{Name: "foo", Source: "github.com/example/tflint-ruleset-foo", Version: "0.1.0", Platforms: map[string]*PlatformHashes{
+		"linux_amd64":  {Zip: "aaa", Binary: "bbb"},
+		"darwin_arm64": {Zip: "ccc", Binary: "ddd"},
+	}}
+	if diff := cmp.Diff(expected, merged); diff != "" {
+		t.Fatal(diff)
+	}
+
+	if merged, err := MergeLockedPlugins([]*LockedPlugin{nil}); err != nil || merged != nil {
+		t.Fatalf("expected no entries, but got %v, %v", merged, err)
+	}
+
+	_, err = MergeLockedPlugins([]*LockedPlugin{
+		{Name: "foo", Platforms: map[string]*PlatformHashes{"linux_amd64": {Zip: "aaa"}}},
+		{Name: "foo", Platforms: map[string]*PlatformHashes{"linux_amd64": {Zip: "bbb"}}},
+	})
+	if err == nil || err.Error() != `lock files have different hashes of plugin "foo" for linux_amd64` {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
@@ -0,0 +1,155 @@
+package plugin
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"regexp"
+
+	"github.com/google/go-github/v81/github"
+)
+
+// attestationsFileName is the name of the file that stores artifact attestations of checksums.txt in a mirror.
+// Attestations are fetched from the GitHub API on install, so they are saved as a file for offline use.
+const attestationsFileName = "checksums.txt.attestations.json"
+
+var platformPattern = regexp.MustCompile(`^[a-z0-9]+_[a-z0-9]+$`)
+
+// ValidatePlatform returns an error if the platform is not in a format like "linux_amd64".
+func ValidatePlatform(platform string) error {
+	if !platformPattern.MatchString(platform) {
+		return fmt.Errorf(`"%s" is invalid platform. Platforms must be in the format "GOOS_GOARCH", like "linux_amd64"`, platform)
+	}
+	return nil
+}
+
+// MirrorDir returns the directory of the plugin mirror set by the TFLINT_PLUGIN_MIRROR environment variable.
+// If set, plugins are installed from the mirror instead of their sources.
+func MirrorDir() string {
+	return os.Getenv("TFLINT_PLUGIN_MIRROR")
+}
+
+// MirrorPath returns the directory of the release in the mirror, like "github.com/terraform-linters/tflint-ruleset-aws/v0.21.1".
+func (c *InstallConfig) MirrorPath() string {
+	return filepath.Join(c.sourceDir(), c.TagName())
+}
+
+// Mirror downloads the release assets of the plugin for the platforms into the mirror directory.
+// If no platforms are passed, the current platform is used. Returns the directory where the assets are saved.
+//
+// The release is verified in the same way as Install, and the signature of checksums.txt
+// (checksums.txt.sig or artifact attestations) is saved alongside the assets,
+// so that installing from the mirror verifies the release again without network access.
+//
+// Like Install, ErrPluginNotVerified and ErrLegacySigningKeyUsed are returned with the directory after saving.
+func (c *InstallConfig) Mirror(dir string, platforms []string) (string, error) {
+	if len(platforms) == 0 {
+		platforms = []string{currentPlatform()}
+	}
+
+	ctx := context.Background()
+	release, err := c.fetchVerifiedRelease(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	releaseDir := filepath.Join(dir, c.MirrorPath())
+	log.Printf("[DEBUG] Mkdir mirror dir: %s", releaseDir)
+	if err := os.MkdirAll(releaseDir, 0o755); err != nil {
+		return "", fmt.Errorf("Failed to mkdir to %s: %w", releaseDir, err)
+	}
+
+	// Assets are saved before checksums.txt and its signature,
+	// so that interrupted mirroring is not treated as a complete release.
+	for _, platform := range platforms {
+		if err := c.mirrorAsset(ctx, release, c.assetNameFor(platform), releaseDir); err != nil {
+			return "", err
+		}
+	}
+
+	if err := release.saveChecksums(releaseDir); err != nil {
+		return "", err
+	}
+
+	log.Printf("[DEBUG] Mirrored %s successfully", releaseDir)
+	return releaseDir, release.verificationError()
+}
+
+// saveChecksums saves checksums.txt and its signature in the directory.
+// checksums.txt is saved last, so that it marks the release as complete.
+func (r *verifiedRelease) saveChecksums(dir string) error {
+	switch r.sigMode {
+	case SignatureModeAttestation:
+		content, err := json.Marshal(r.attestations)
+		if err != nil {
+			return err
+		}
+		if err := os.WriteFile(filepath.Join(dir, attestationsFileName), content, 0o644); err != nil {
+			return fmt.Errorf("Failed to write %s: %w", attestationsFileName, err)
+		}
+	case SignatureModePGP:
+		if err := os.WriteFile(filepath.Join(dir, "checksums.txt.sig"), r.signature, 0o644); err != nil {
+			return fmt.Errorf("Failed to write checksums.txt.sig: %w", err)
+		}
+	}
+	if err := os.WriteFile(filepath.Join(dir, "checksums.txt"), r.checksum, 0o644); err != nil {
+		return fmt.Errorf("Failed to write checksums.txt: %w", err)
+	}
+	return nil
+}
+
+// mirrorAsset downloads the asset, verifies its checksum, and saves it in the directory.
+func (c *InstallConfig) mirrorAsset(ctx context.Context, release *verifiedRelease, assetName string, dir string) error {
+	log.Printf("[DEBUG] Download %s", assetName)
+	zipFile, err := release.source.download(ctx, assetName)
+	if zipFile != nil {
+		defer os.Remove(zipFile.Name())
+		defer zipFile.Close()
+	}
+	if err != nil {
+		return fmt.Errorf("Failed to download %s: %s", assetName, err)
+	}
+	if err := release.checksummer.Verify(assetName, zipFile); err != nil {
+		return fmt.Errorf("Failed to verify checksums: %s", err)
+	}
+	if _, err := zipFile.Seek(0, 0); err != nil {
+		return err
+	}
+
+	dst, err := os.Create(filepath.Join(dir, assetName))
+	if err != nil {
+		return fmt.Errorf("Failed to create %s: %w", assetName, err)
+	}
+	defer dst.Close()
+	if _, err := io.Copy(dst, zipFile); err != nil {
+		return fmt.Errorf("Failed to write %s: %w", assetName, err)
+	}
+	return nil
+}
+
+// mirrorSource copies assets from the plugin mirror created by Mirror.
+// Unlike the file source, artifact attestations saved in the mirror are also available.
+type mirrorSource struct {
+	fileSource
+}
+
+// attestations returns artifact attestations saved in the mirror.
+// Returns nil if the release in the mirror is not verified with attestations.
+func (s *mirrorSource) attestations() ([]*github.Attestation, error) {
+	content, err := os.ReadFile(filepath.Join(s.dir, attestationsFileName))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var attestations []*github.Attestation
+	if err := json.Unmarshal(content, &attestations); err != nil {
+		return nil, fmt.Errorf("Failed to parse %s: %s", attestationsFileName, err)
+	}
+	return attestations, nil
+}
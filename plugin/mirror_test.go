@@ -0,0 +1,184 @@
+package plugin
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-github/v81/github"
+	"github.com/terraform-linters/tflint/tflint"
+)
+
+func Test_Mirror(t *testing.T) {
+	signer, signingKey := newSigningKey(t)
+
+	original := PluginRoot
+	PluginRoot = t.TempDir()
+	defer func() { PluginRoot = original }()
+
+	release := t.TempDir()
+	writeRelease(t, release, "plugin binary", signer)
+
+	pluginCfg := &tflint.PluginConfig{
+		Name:       "foo",
+		Enabled:    true,
+		Version:    "0.1.0",
+		Source:     "file://" + filepath.ToSlash(release),
+		SourceType: tflint.SourceTypeFile,
+		SourceURL:  release,
+		SigningKey: signingKey,
+	}
+	config := NewInstallConfig(tflint.EmptyConfig(), pluginCfg)
+
+	mirror := t.TempDir()
+	dir, err := config.Mirror(mirror, nil)
+	if err != nil {
+		t.Fatalf("Failed to mirror: %s", err)
+	}
+	if dir != filepath.Join(mirror, config.MirrorPath()) {
+		t.Fatalf("Mirrored to unexpected dir: %s", dir)
+	}
+	for _, name := range []string{"checksums.txt", "checksums.txt.sig", config.AssetName()} {
+		if _, err := os.Stat(filepath.Join(dir, name)); err != nil {
+			t.Fatalf("%s is not mirrored: %s", name, err)
+		}
+	}
+
+	// Platforms not in the release cannot be mirrored
+	if _, err := config.Mirror(t.TempDir(), []string{"plan9_arm"}); err == nil || !strings.Contains(err.Error(), "Failed to download tflint-ruleset-foo_plan9_arm.zip") {
+		t.Fatalf("expected a download error, but got %v", err)
+	}
+
+	// Install from the mirror after the original release is removed
+	if err := os.RemoveAll(release); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("TFLINT_PLUGIN_MIRROR", mirror)
+
+	path, err := config.Install()
+	if err != nil {
+		t.Fatalf("Failed to install from the mirror: %s", err)
+	}
+	got, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != "plugin binary" {
+		t.Fatalf("Installed binary is invalid: %s", got)
+	}
+
+	// The mirror is verified like the original release
+	if err := os.WriteFile(filepath.Join(dir, config.AssetName()), []byte("tampered"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := config.Install(); err == nil || !strings.Contains(err.Error(), "Failed to verify checksums: Failed to match checksums") {
+		t.Fatalf("expected a checksum error, but got %v", err)
+	}
+}
+
+func Test_mirrorSource_attestations(t *testing.T) {
+	dir := t.TempDir()
+	source := &mirrorSource{fileSource{dir: dir}}
+
+	got, err := source.attestations()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != nil {
+		t.Fatalf("expected no attestations, but got %v", got)
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, attestationsFileName), []byte(`[{"bundle":{"mediaType":"application/vnd.dev.sigstore.bundle.v0.3+json"},"repository_id":1}]`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	got, err = source.attestations()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 1 || got[0].RepositoryID != 1 {
+		t.Fatalf("unexpected attestations: %v", got)
+	}
+}
+
+func Test_ValidatePlatform(t *testing.T) {
+	for _, platform := range []string{"linux_amd64", "darwin_arm64", "windows_386"} {
+		if err := ValidatePlatform(platform); err != nil {
+			t.Errorf("%s: expected no errors, but got %s", platform, err)
+		}
+	}
+	for _, platform := range []string{"linux", "linux-amd64", "Linux_AMD64", "linux_amd64_v2"} {
+		if err := ValidatePlatform(platform); err == nil {
+			t.Errorf("%s: expected an error, but got no errors", platform)
+		}
+	}
+}
+
+func Test_verifiedRelease_saveChecksums(t *testing.T) {
+	dir := t.TempDir()
+	release := &verifiedRelease{
+		checksum:     []byte("checksums"),
+		sigMode:      SignatureModeAttestation,
+		attestations: []*github.Attestation{{RepositoryID: 1}},
+	}
+	if err := release.saveChecksums(dir); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, name := range []string{"checksums.txt", attestationsFileName} {
+		if _, err := os.Stat(filepath.Join(dir, name)); err != nil {
+			t.Fatalf("%s is not saved: %s", name, err)
+		}
+	}
+	// The trusted root is not saved, since anyone who can write to the mirror could replace it
+	if _, err := os.Stat(filepath.Join(dir, "trusted_root.json")); !os.IsNotExist(err) {
+		t.Fatalf("expected trusted_root.json not to be saved, but got %v", err)
+	}
+}
+
+func Test_Install_fromMirrorIgnoresTrustedRoot(t *testing.T) {
+	original := PluginRoot
+	PluginRoot = t.TempDir()
+	defer func() { PluginRoot = original }()
+
+	pluginCfg := &tflint.PluginConfig{
+		Name:        "foo",
+		Enabled:     true,
+		Version:     "0.1.0",
+		Source:      "github.com/example/tflint-ruleset-foo",
+		Signature:   "attestation",
+		TrustedRoot: filepath.Join(t.TempDir(), "trusted_root.json"),
+	}
+	config := NewInstallConfig(tflint.EmptyConfig(), pluginCfg)
+
+	mirror := t.TempDir()
+	dir := filepath.Join(mirror, config.MirrorPath())
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	files := map[string]string{
+		"checksums.txt":      "checksums",
+		attestationsFileName: `[{"bundle":{"mediaType":"application/vnd.dev.sigstore.bundle.v0.3+json"},"repository_id":1}]`,
+		"trusted_root.json":  "invalid",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	t.Setenv("TFLINT_PLUGIN_MIRROR", mirror)
+
+	// Attestations in the mirror are verified with the configured trusted root, not with the one in the mirror
+	want := fmt.Sprintf("failed to load trusted root from %s: ", pluginCfg.TrustedRoot)
+	if _, err := config.Install(); err == nil || !strings.Contains(err.Error(), want) {
+		t.Fatalf("expected an error to load the configured trusted root, but got %v", err)
+	}
+
+	// Without trusted_root, the trusted root is fetched via TUF instead of the mirror
+	pluginCfg.TrustedRoot = ""
+	mirrorRoot := filepath.Join(dir, "trusted_root.json")
+	if _, err := NewInstallConfig(tflint.EmptyConfig(), pluginCfg).Install(); err == nil || strings.Contains(err.Error(), mirrorRoot) {
+		t.Fatalf("expected an error without loading the trusted root in the mirror, but got %v", err)
+	}
+}
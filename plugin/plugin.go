@@ -26,3 +26,11 @@ func (p *Plugin) Clean() {
 		client.Kill()
 	}
 }
+
+// Kill ends the process of the named plugin, e.g. when it hangs.
+// Pending requests to the plugin fail, and the ruleset cannot be used after that.
+func (p *Plugin) Kill(name string) {
+	if client, exists := p.clients[name]; exists {
+		client.Kill()
+	}
+}
@@ -1,6 +1,7 @@
 package plugin
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"log"
@@ -18,19 +19,48 @@ import (
 )
 
 // GRPCServer is a gRPC server for responding to requests from plugins.
+//
+// Servers sharing the same runners can respond to requests from multiple plugins concurrently,
+// as requests other than ApplyChanges do not modify modules, and issues are emitted in a goroutine-safe way.
+// ApplyChanges must not be called while other servers are responding to requests for the same runners.
 type GRPCServer struct {
+	ctx              context.Context
 	mu               sync.Mutex
 	runner           *tflint.Runner
 	rootRunner       *tflint.Runner
 	files            map[string]*hcl.File
 	clientSDKVersion *version.Version
+	profile          *tflint.Profile
+	pluginName       string
 }
 
 var _ plugin2host.Server = (*GRPCServer)(nil)
 
 // NewGRPCServer initializes a gRPC server for plugins.
-func NewGRPCServer(runner *tflint.Runner, rootRunner *tflint.Runner, files map[string]*hcl.File, sdkVersion *version.Version) *GRPCServer {
-	return &GRPCServer{runner: runner, rootRunner: rootRunner, files: files, clientSDKVersion: sdkVersion}
+// Once the context is cancelled, requests from plugins fail so that the running check is aborted.
+func NewGRPCServer(ctx context.Context, runner *tflint.Runner, rootRunner *tflint.Runner, files map[string]*hcl.File, sdkVersion *version.Version) *GRPCServer {
+	return &GRPCServer{ctx: ctx, runner: runner, rootRunner: rootRunner, files: files, clientSDKVersion: sdkVersion}
+}
+
+// WithProfile records the time spent in requests from the named plugin in the profile.
+func (s *GRPCServer) WithProfile(profile *tflint.Profile, pluginName string) *GRPCServer {
+	s.profile = profile
+	s.pluginName = pluginName
+	return s
+}
+
+// startProfile starts measuring a request and returns a function to end it.
+func (s *GRPCServer) startProfile(name string, args map[string]string) func() {
+	if s.profile == nil {
+		return func() {}
+	}
+	return s.profile.Start(&tflint.ProfileEvent{
+		Category: tflint.ProfileCategoryCallback,
+		Name:     name,
+		Plugin:   s.pluginName,
+		Module:   s.runner.ModulePath(),
+		Args:     args,
+	})
 }
 
 // GetOriginalwd returns the original working directory.
@@ -45,6 +75,12 @@ func (s *GRPCServer) GetModulePath() []string {
 
 // GetModuleContent returns module content based on the passed schema and options.
 func (s *GRPCServer) GetModuleContent(bodyS *hclext.BodySchema, opts sdk.GetModuleContentOption) (*hclext.BodyContent, hcl.Diagnostics) {
+	defer s.startProfile("GetModuleContent", map[string]string{"resource_type": opts.Hint.ResourceType})()
+
+	if err := s.ctx.Err(); err != nil {
+		return nil, hcl.Diagnostics{{Severity: hcl.DiagError, Summary: err.Error()}}
+	}
+
 	var module *terraform.Module
 	var ctx *terraform.Evaluator
 
@@ -77,6 +113,9 @@ func (s *GRPCServer) GetModuleContent(bodyS *hclext.BodySchema, opts sdk.GetModu
 
 // GetFile returns the hcl.File based on passed the file name.
 func (s *GRPCServer) GetFile(name string) (*hcl.File, error) {
+	if err := s.ctx.Err(); err != nil {
+		return nil, err
+	}
 	// Considering that autofix has been applied, prioritize returning the value of runner.Files().
 	if file, exists := s.runner.Files()[name]; exists {
 		return file, nil
@@ -104,6 +143,11 @@ func (s *GRPCServer) GetFiles(ty sdk.ModuleCtxType) map[string][]byte {
 // It returns an extracted body content and sources.
 // The reason for returning sources is to encode the expression, and there is room for improvement here.
 func (s *GRPCServer) GetRuleConfigContent(name string, bodyS *hclext.BodySchema) (*hclext.BodyContent, map[string][]byte, error) {
+	defer s.startProfile("GetRuleConfigContent", map[string]string{"rule": name})()
+
+	if err := s.ctx.Err(); err != nil {
+		return nil, nil, err
+	}
 	config := s.runner.RuleConfig(name)
 	if config == nil {
 		return &hclext.BodyContent{}, s.runner.ConfigSources(), nil
@@ -129,6 +173,12 @@ func (s *GRPCServer) GetRuleConfigContent(name string, bodyS *hclext.BodySchema)
 
 // EvaluateExpr returns the value of the passed expression.
 func (s *GRPCServer) EvaluateExpr(expr hcl.Expression, opts sdk.EvaluateExprOption) (cty.Value, error) {
+	defer s.startProfile("EvaluateExpr", map[string]string{"range": expr.Range().String()})()
+
+	if err := s.ctx.Err(); err != nil {
+		return cty.NullVal(cty.NilType), err
+	}
+
 	var runner *tflint.Runner
 	switch opts.ModuleCtx {
 	case sdk.SelfModuleCtxType:
@@ -167,12 +217,19 @@ func (s *GRPCServer) EvaluateExpr(expr hcl.Expression, opts sdk.EvaluateExprOpti
 // However, some ranges may be syntactically valid but not actually represent an expression.
 // In these cases, the "expression" is still provided as context and the client should ignore any errors when attempting to evaluate it.
 func (s *GRPCServer) EmitIssue(rule sdk.Rule, message string, location hcl.Range, fixable bool) (bool, error) {
+	defer s.startProfile("EmitIssue", map[string]string{"rule": rule.Name()})()
+
+	if err := s.ctx.Err(); err != nil {
+		return false, err
+	}
+
 	// If the issue range represents an expression, it is emitted based on that context.
 	// This is required to emit issues in called modules.
 	expr, err := s.getExprFromRange(location)
 	if err != nil {
 		// If the range does not represent an expression, just emit it without context.
-		return s.runner.EmitIssue(rule, message, location, fixable), nil
+		// Even so, the empty context is set so as not to use the context of other concurrent requests.
+		expr = nil
 	}
 
 	var applied bool
@@ -197,6 +254,11 @@ func (s *GRPCServer) getExprFromRange(location hcl.Range) (hcl.Expression, error
 
 // ApplyChanges applies the autofix changes to the runner.
 func (s *GRPCServer) ApplyChanges(changes map[string][]byte) error {
+	defer s.startProfile("ApplyChanges", nil)()
+
+	if err := s.ctx.Err(); err != nil {
+		return err
+	}
 	diags := s.runner.ApplyChanges(changes)
 	if diags.HasErrors() {
 		return diags
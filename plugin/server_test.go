@@ -1,6 +1,7 @@
 package plugin
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"maps"
@@ -49,7 +50,7 @@ resource "aws_instance" "bar" {
 	instance_type = "m5.2xlarge"
 }`})
 
-	server := NewGRPCServer(runner, rootRunner, runner.Files(), SDKVersion)
+	server := NewGRPCServer(context.Background(), runner, rootRunner, runner.Files(), SDKVersion)
 
 	tests := []struct {
 		Name string
@@ -267,7 +268,7 @@ resource "aws_instance" "foo" {
 			files := runner.Files()
 			maps.Copy(files, rootRunner.Files())
 
-			server := NewGRPCServer(runner, rootRunner, files, SDKVersion)
+			server := NewGRPCServer(context.Background(), runner, rootRunner, files, SDKVersion)
 
 			if diags := runner.ApplyChanges(test.Changes); diags.HasErrors() {
 				t.Fatal(diags)
@@ -300,7 +301,7 @@ resource "aws_instance" "bar" {
 	instance_type = "m5.2xlarge"
 }`})
 
-	server := NewGRPCServer(runner, rootRunner, runner.Files(), SDKVersion)
+	server := NewGRPCServer(context.Background(), runner, rootRunner, runner.Files(), SDKVersion)
 
 	tests := []struct {
 		Name string
@@ -364,7 +365,7 @@ rule "test_in_file" {
 	fileConfig.Merge(cliConfig)
 	runner := tflint.TestRunnerWithConfig(t, map[string]string{}, fileConfig)
 
-	server := NewGRPCServer(runner, nil, runner.Files(), SDKVersion)
+	server := NewGRPCServer(context.Background(), runner, nil, runner.Files(), SDKVersion)
 
 	// default error check helper
 	neverHappend := func(err error) bool { return err != nil }
@@ -473,7 +474,7 @@ variable "foo" {
 	default = "baz"
 }`})
 
-	server := NewGRPCServer(runner, rootRunner, runner.Files(), SDKVersion)
+	server := NewGRPCServer(context.Background(), runner, rootRunner, runner.Files(), SDKVersion)
 
 	sdkv21 := version.Must(version.NewVersion("0.21.0"))
 
@@ -666,7 +667,7 @@ resource "aws_instance" "foo" {
 		t.Run(test.Name, func(t *testing.T) {
 			runner := tflint.TestRunner(t, map[string]string{"main.tf": config})
 
-			server := NewGRPCServer(runner, nil, runner.Files(), SDKVersion)
+			server := NewGRPCServer(context.Background(), runner, nil, runner.Files(), SDKVersion)
 
 			_, err := server.EmitIssue(test.Args())
 			if err != nil {
@@ -716,7 +717,7 @@ resource "aws_instance" "foo" {
 		t.Run(test.name, func(t *testing.T) {
 			runner := tflint.TestRunner(t, test.files)
 
-			server := NewGRPCServer(runner, nil, runner.Files(), SDKVersion)
+			server := NewGRPCServer(context.Background(), runner, nil, runner.Files(), SDKVersion)
 
 			err := server.ApplyChanges(test.changes)
 			if err != nil {
@@ -730,3 +731,32 @@ resource "aws_instance" "foo" {
 		})
 	}
 }
+
+func TestCancelledContext(t *testing.T) {
+	runner := tflint.TestRunner(t, map[string]string{"main.tf": `
+resource "aws_instance" "foo" {
+	instance_type = "t2.micro"
+}`})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	server := NewGRPCServer(ctx, runner, runner, runner.Files(), SDKVersion)
+	cancel()
+
+	schema := &hclext.BodySchema{Blocks: []hclext.BlockSchema{{Type: "resource", LabelNames: []string{"type", "name"}}}}
+	if _, diags := server.GetModuleContent(schema, sdk.GetModuleContentOption{ModuleCtx: sdk.SelfModuleCtxType}); !diags.HasErrors() {
+		t.Error("expected GetModuleContent to fail, but succeeded")
+	}
+	if _, err := server.GetFile("main.tf"); !errors.Is(err, context.Canceled) {
+		t.Errorf("expected GetFile to return context.Canceled, but got %v", err)
+	}
+	wantType := cty.String
+	if _, err := server.EvaluateExpr(hcl.StaticExpr(cty.StringVal("foo"), hcl.Range{}), sdk.EvaluateExprOption{WantType: &wantType, ModuleCtx: sdk.SelfModuleCtxType}); !errors.Is(err, context.Canceled) {
+		t.Errorf("expected EvaluateExpr to return context.Canceled, but got %v", err)
+	}
+	if _, err := server.EmitIssue(&testRule{}, "test", hcl.Range{Filename: "main.tf"}, false); !errors.Is(err, context.Canceled) {
+		t.Errorf("expected EmitIssue to return context.Canceled, but got %v", err)
+	}
+	if len(runner.Issues) != 0 {
+		t.Errorf("expected no issues, but got %d", len(runner.Issues))
+	}
+}
@@ -9,6 +9,7 @@ import (
 	"strings"
 
 	"github.com/google/go-github/v81/github"
+	"github.com/mitchellh/go-homedir"
 	"github.com/sigstore/sigstore-go/pkg/bundle"
 	"github.com/sigstore/sigstore-go/pkg/root"
 	"github.com/sigstore/sigstore-go/pkg/tuf"
@@ -28,12 +29,12 @@ func NewSignatureChecker(config *InstallConfig) *SignatureChecker {
 }
 
 // GetSigningKey returns an ASCII armored signing key.
-// If the plugin is under the terraform-linters organization, you can use the built-in key even if the signing_key is omitted.
+// If the plugin is under the terraform-linters organization on GitHub, you can use the built-in key even if the signing_key is omitted.
 func (c *SignatureChecker) GetSigningKey() string {
 	if c.config.SigningKey != "" {
 		return c.config.SigningKey
 	}
-	if c.config.SourceOwner == "terraform-linters" {
+	if c.config.SourceType == "" && c.config.SourceOwner == "terraform-linters" {
 		return builtinSigningKey
 	}
 	return c.config.SigningKey
@@ -79,17 +80,7 @@ func (c *SignatureChecker) VerifyAttestations(target io.Reader, attestations []*
 	}
 	artifactDigest := sha256.Sum256(artifact)
 
-	// Initialize Sigstore trust root
-	// This saves the caches under the "~/.sigstore"
-	client, err := tuf.New(tuf.DefaultOptions())
-	if err != nil {
-		return err
-	}
-	trustedrootJSON, err := client.GetTarget("trusted_root.json")
-	if err != nil {
-		return err
-	}
-	trustedRoot, err := root.NewTrustedRootFromJSON(trustedrootJSON)
+	trustedRoot, err := c.trustedRoot()
 	if err != nil {
 		return err
 	}
@@ -151,6 +142,36 @@ func (c *SignatureChecker) VerifyAttestations(target io.Reader, attestations []*
 	return verifyErr
 }
 
+// trustedRoot returns the Sigstore trusted root. If trusted_root is set, it is loaded from the file,
+// which allows verifying attestations offline or against a private Sigstore instance.
+// Otherwise, the trusted root of the public good instance is fetched via TUF.
+func (c *SignatureChecker) trustedRoot() (*root.TrustedRoot, error) {
+	if c.config.TrustedRoot != "" {
+		path, err := homedir.Expand(c.config.TrustedRoot)
+		if err != nil {
+			return nil, err
+		}
+		log.Printf("[DEBUG] Load trusted root from %s", path)
+		trustedRoot, err := root.NewTrustedRootFromPath(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load trusted root from %s: %s", path, err)
+		}
+		return trustedRoot, nil
+	}
+
+	// Initialize Sigstore trust root
+	// This saves the caches under the "~/.sigstore"
+	client, err := tuf.New(tuf.DefaultOptions())
+	if err != nil {
+		return nil, err
+	}
+	trustedrootJSON, err := client.GetTarget("trusted_root.json")
+	if err != nil {
+		return nil, err
+	}
+	return root.NewTrustedRootFromJSON(trustedrootJSON)
+}
+
 // builtinSigningKey is the default signing key that applies only to plugins under the terraform-linters organization.
 // This makes it possible for the plugins we distribute to be used safely without having to set signing key.
 var builtinSigningKey string = `
@@ -584,3 +584,14 @@ var testSigstoreBundle035 string = `{
     ]
   }
 }`
+
+func Test_SignatureChecker_VerifyAttestations_trustedRoot(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "trusted_root.json")
+	sigchecker := NewSignatureChecker(NewInstallConfig(tflint.EmptyConfig(), &tflint.PluginConfig{TrustedRoot: path}))
+
+	// The trusted root is loaded from the file instead of the public good instance
+	err := sigchecker.VerifyAttestations(strings.NewReader("checksums"), []*github.Attestation{{Bundle: []byte(testSigstoreBundle035)}})
+	if err == nil || !strings.HasPrefix(err.Error(), fmt.Sprintf("failed to load trusted root from %s: ", path)) {
+		t.Fatalf("expected an error to load the trusted root, but got %v", err)
+	}
+}
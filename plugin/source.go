@@ -0,0 +1,277 @@
+package plugin
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+
+	"github.com/google/go-github/v81/github"
+	"github.com/terraform-linters/tflint/tflint"
+)
+
+// errAssetNotFound is returned when the release does not contain the asset.
+var errAssetNotFound = errors.New("file not found in the release. Does the release contain the file with the correct name ?")
+
+// releaseSource downloads assets of a plugin release.
+// Assets must follow the same naming conventions regardless of the source.
+// See InstallConfig.Install for the conventions.
+type releaseSource interface {
+	// download downloads the asset with the name to a local temp file.
+	// It is the caller's responsibility to delete the generated temp file.
+	download(ctx context.Context, name string) (*os.File, error)
+}
+
+// newReleaseSource returns a source of the release based on the source type.
+// For sources with release APIs, the release is fetched here.
+// If the plugin mirror is set, assets are copied from the mirror regardless of the source type.
+func (c *InstallConfig) newReleaseSource(ctx context.Context) (releaseSource, error) {
+	if dir := MirrorDir(); dir != "" {
+		log.Printf("[DEBUG] TFLINT_PLUGIN_MIRROR set, plugins will be installed from %s", dir)
+		return &mirrorSource{fileSource{dir: filepath.Join(dir, c.MirrorPath())}}, nil
+	}
+
+	switch c.SourceType {
+	case tflint.SourceTypeHTTPS:
+		return &httpSource{client: newHTTPClient(), baseURL: fmt.Sprintf("%s/%s", c.SourceURL, c.TagName())}, nil
+
+	case tflint.SourceTypeFile:
+		return &fileSource{dir: filepath.Join(c.SourceURL, c.TagName())}, nil
+
+	case tflint.SourceTypeGitLab:
+		return c.newGitLabSource(ctx)
+
+	case tflint.SourceTypeGitea:
+		return c.newGiteaSource(ctx)
+
+	default:
+		client, err := newGitHubClient(ctx, c)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to create GitHub client: %s", err)
+		}
+		assets, err := c.fetchReleaseAssets(ctx, client)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to fetch GitHub releases: %w", err)
+		}
+		return &githubSource{config: c, client: client, assets: assets}, nil
+	}
+}
+
+// githubSource downloads assets from GitHub releases.
+// This is the only source that supports artifact attestations.
+type githubSource struct {
+	config *InstallConfig
+	client *github.Client
+	assets map[string]*github.ReleaseAsset
+}
+
+func (s *githubSource) download(ctx context.Context, name string) (*os.File, error) {
+	return s.config.downloadToTempFile(ctx, s.client, s.assets[name])
+}
+
+// httpSource downloads assets over HTTP(S).
+// If assets is nil, assets are downloaded from the base URL, like "${baseURL}/checksums.txt".
+// Otherwise, assets are downloaded from the URLs returned by the release API.
+type httpSource struct {
+	client  *http.Client
+	baseURL string
+	assets  map[string]string
+
+	// header is sent only to the host of the release API, so that tokens are not leaked to other hosts.
+	host   string
+	header http.Header
+}
+
+func (s *httpSource) download(ctx context.Context, name string) (*os.File, error) {
+	u := fmt.Sprintf("%s/%s", s.baseURL, url.PathEscape(name))
+	if s.assets != nil {
+		var exists bool
+		if u, exists = s.assets[name]; !exists {
+			return nil, errAssetNotFound
+		}
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return nil, err
+	}
+	if req.URL.Host == s.host {
+		for key, values := range s.header {
+			req.Header[key] = values
+		}
+	}
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusNotFound:
+		return nil, errAssetNotFound
+	default:
+		return nil, fmt.Errorf("GET %s: %s", u, resp.Status)
+	}
+
+	return copyToTempFile(resp.Body)
+}
+
+// fileSource copies assets from a local directory, like a mirror on a shared file system.
+// The directory layout is the same as the HTTPS source, like "${dir}/v1.0.0/checksums.txt".
+type fileSource struct {
+	dir string
+}
+
+func (s *fileSource) download(ctx context.Context, name string) (*os.File, error) {
+	f, err := os.Open(filepath.Join(s.dir, name))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, errAssetNotFound
+		}
+		return nil, err
+	}
+	defer f.Close()
+
+	return copyToTempFile(f)
+}
+
+// gitLabRelease is a response of the GitLab Releases API.
+// See https://docs.gitlab.com/api/releases/#get-a-release-by-a-tag-name
+type gitLabRelease struct {
+	Assets struct {
+		Links []struct {
+			Name           string `json:"name"`
+			URL            string `json:"url"`
+			DirectAssetURL string `json:"direct_asset_url"`
+		} `json:"links"`
+	} `json:"assets"`
+}
+
+// newGitLabSource fetches the release from the GitLab Releases API.
+func (c *InstallConfig) newGitLabSource(ctx context.Context) (releaseSource, error) {
+	apiURL := fmt.Sprintf("%s/releases/%s", c.gitLabProjectURL(), url.PathEscape(c.TagName()))
+	source := c.newGitLabAPISource()
+
+	var release gitLabRelease
+	if err := source.getJSON(ctx, apiURL, &release); err != nil {
+		return nil, fmt.Errorf("Failed to fetch GitLab releases: %w", err)
+	}
+	for _, link := range release.Assets.Links {
+		log.Printf("[DEBUG] asset found: %s", link.Name)
+		if link.DirectAssetURL != "" {
+			source.assets[link.Name] = link.DirectAssetURL
+		} else {
+			source.assets[link.Name] = link.URL
+		}
+	}
+	return source, nil
+}
+
+// gitLabProjectURL returns the API URL of the GitLab project.
+func (c *InstallConfig) gitLabProjectURL() string {
+	project := url.PathEscape(fmt.Sprintf("%s/%s", c.SourceOwner, c.SourceRepo))
+	return fmt.Sprintf("https://%s/api/v4/projects/%s", c.SourceHost, project)
+}
+
+// newGitLabAPISource returns a source without assets to call the GitLab API.
+// If GITLAB_TOKEN is set, requests to the GitLab host are authenticated with it.
+func (c *InstallConfig) newGitLabAPISource() *httpSource {
+	header := http.Header{}
+	if t := os.Getenv("GITLAB_TOKEN"); t != "" {
+		log.Printf("[DEBUG] GITLAB_TOKEN set, plugin requests to %s will be authenticated", c.SourceHost)
+		header.Set("PRIVATE-TOKEN", t)
+	}
+	return &httpSource{client: newHTTPClient(), assets: map[string]string{}, host: c.SourceHost, header: header}
+}
+
+// giteaRelease is a response of the Gitea Releases API.
+// See https://docs.gitea.com/api/ (repoGetReleaseByTag)
+type giteaRelease struct {
+	Assets []struct {
+		Name               string `json:"name"`
+		BrowserDownloadURL string `json:"browser_download_url"`
+	} `json:"assets"`
+}
+
+// newGiteaSource fetches the release from the Gitea Releases API. Forgejo is also supported.
+func (c *InstallConfig) newGiteaSource(ctx context.Context) (releaseSource, error) {
+	apiURL := fmt.Sprintf("%s/releases/tags/%s", c.giteaRepoURL(), url.PathEscape(c.TagName()))
+	source := c.newGiteaAPISource()
+
+	var release giteaRelease
+	if err := source.getJSON(ctx, apiURL, &release); err != nil {
+		return nil, fmt.Errorf("Failed to fetch Gitea releases: %w", err)
+	}
+	for _, asset := range release.Assets {
+		log.Printf("[DEBUG] asset found: %s", asset.Name)
+		source.assets[asset.Name] = asset.BrowserDownloadURL
+	}
+	return source, nil
+}
+
+// giteaRepoURL returns the API URL of the Gitea repository.
+func (c *InstallConfig) giteaRepoURL() string {
+	return fmt.Sprintf("https://%s/api/v1/repos/%s/%s", c.SourceHost, url.PathEscape(c.SourceOwner), url.PathEscape(c.SourceRepo))
+}
+
+// newGiteaAPISource returns a source without assets to call the Gitea API.
+// If GITEA_TOKEN is set, requests to the Gitea host are authenticated with it.
+func (c *InstallConfig) newGiteaAPISource() *httpSource {
+	header := http.Header{}
+	if t := os.Getenv("GITEA_TOKEN"); t != "" {
+		log.Printf("[DEBUG] GITEA_TOKEN set, plugin requests to %s will be authenticated", c.SourceHost)
+		header.Set("Authorization", "token "+t)
+	}
+	return &httpSource{client: newHTTPClient(), assets: map[string]string{}, host: c.SourceHost, header: header}
+}
+
+// getJSON sends a GET request to the release API and decodes the JSON response.
+func (s *httpSource) getJSON(ctx context.Context, u string, v any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+	for key, values := range s.header {
+		req.Header[key] = values
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("GET %s: %s", u, resp.Status)
+	}
+	return json.NewDecoder(resp.Body).Decode(v)
+}
+
+func newHTTPClient() *http.Client {
+	return &http.Client{Transport: &requestLoggingTransport{http.DefaultTransport}}
+}
+
+// copyToTempFile copies the content to a local temp file and rewinds it.
+// It is the caller's responsibility to delete the generated temp file.
+func copyToTempFile(r io.Reader) (*os.File, error) {
+	file, err := os.CreateTemp("", "tflint-download-temp-file-*")
+	if err != nil {
+		return nil, err
+	}
+	if _, err = io.Copy(file, r); err != nil {
+		return file, err
+	}
+	if _, err := file.Seek(0, 0); err != nil {
+		return file, err
+	}
+
+	log.Printf("[DEBUG] Downloaded to %s", file.Name())
+	return file, nil
+}
@@ -0,0 +1,347 @@
+package plugin
+
+import (
+	"archive/zip"
+	"bytes"
+	"crypto/sha256"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/terraform-linters/tflint/tflint"
+	"golang.org/x/crypto/openpgp"       //nolint:staticcheck
+	"golang.org/x/crypto/openpgp/armor" //nolint:staticcheck
+)
+
+// writeRelease writes release assets of the "foo" plugin for the current platform to the directory,
+// in the layout of HTTPS and file sources. If signer is not nil, checksums.txt is signed with it.
+func writeRelease(t *testing.T, dir string, binary string, signer *openpgp.Entity) {
+	t.Helper()
+
+	releaseDir := filepath.Join(dir, "v0.1.0")
+	if err := os.MkdirAll(releaseDir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	buf := new(bytes.Buffer)
+	zw := zip.NewWriter(buf)
+	w, err := zw.Create("tflint-ruleset-foo" + fileExt())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := w.Write([]byte(binary)); err != nil {
+		t.Fatal(err)
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	assetName := fmt.Sprintf("tflint-ruleset-foo_%s.zip", currentPlatform())
+	if err := os.WriteFile(filepath.Join(releaseDir, assetName), buf.Bytes(), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	checksums := fmt.Sprintf("%x  %s\n", sha256.Sum256(buf.Bytes()), assetName)
+	if err := os.WriteFile(filepath.Join(releaseDir, "checksums.txt"), []byte(checksums), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	if signer != nil {
+		sig := new(bytes.Buffer)
+		if err := openpgp.DetachSign(sig, signer, strings.NewReader(checksums), nil); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(releaseDir, "checksums.txt.sig"), sig.Bytes(), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+// newSigningKey returns a new PGP entity and its ASCII armored public key.
+func newSigningKey(t *testing.T) (*openpgp.Entity, string) {
+	t.Helper()
+
+	entity, err := openpgp.NewEntity("tflint", "test", "tflint@example.com", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	buf := new(bytes.Buffer)
+	w, err := armor.Encode(buf, openpgp.PublicKeyType, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := entity.Serialize(w); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return entity, buf.String()
+}
+
+func Test_Install_fromFileSource(t *testing.T) {
+	signer, signingKey := newSigningKey(t)
+	_, otherKey := newSigningKey(t)
+
+	cases := []struct {
+		Name       string
+		SigningKey string
+		Tamper     bool
+		Expected   string
+	}{
+		{
+			Name:       "verified",
+			SigningKey: signingKey,
+		},
+		{
+			Name:       "signed by another key",
+			SigningKey: otherKey,
+			Expected:   "Failed to check checksums.txt signature: openpgp: signature made by unknown entity",
+		},
+		{
+			Name:       "tampered zip file",
+			SigningKey: signingKey,
+			Tamper:     true,
+			Expected:   "Failed to verify checksums: Failed to match checksums",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.Name, func(t *testing.T) {
+			original := PluginRoot
+			PluginRoot = t.TempDir()
+			defer func() { PluginRoot = original }()
+
+			mirror := t.TempDir()
+			writeRelease(t, mirror, "plugin binary", signer)
+			if tc.Tamper {
+				assetPath := filepath.Join(mirror, "v0.1.0", fmt.Sprintf("tflint-ruleset-foo_%s.zip", currentPlatform()))
+				if err := os.WriteFile(assetPath, []byte("tampered"), 0o644); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			config := NewInstallConfig(tflint.EmptyConfig(), &tflint.PluginConfig{
+				Name:       "foo",
+				Enabled:    true,
+				Version:    "0.1.0",
+				Source:     "file://" + filepath.ToSlash(mirror),
+				SourceType: tflint.SourceTypeFile,
+				SourceURL:  mirror,
+				SigningKey: tc.SigningKey,
+			})
+
+			path, err := config.Install()
+			if tc.Expected != "" {
+				if err == nil || !strings.Contains(err.Error(), tc.Expected) {
+					t.Fatalf("expected to contain %q, but got %v", tc.Expected, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Failed to install: %s", err)
+			}
+
+			got, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(got) != "plugin binary" {
+				t.Fatalf("Installed binary is invalid: %s", got)
+			}
+		})
+	}
+}
+
+func Test_Install_withSigstoreBundle(t *testing.T) {
+	original := PluginRoot
+	PluginRoot = t.TempDir()
+	defer func() { PluginRoot = original }()
+
+	release := t.TempDir()
+	writeRelease(t, release, "plugin binary", nil)
+	trustedRoot := filepath.Join(t.TempDir(), "trusted_root.json")
+
+	config := NewInstallConfig(tflint.EmptyConfig(), &tflint.PluginConfig{
+		Name:                      "foo",
+		Enabled:                   true,
+		Version:                   "0.1.0",
+		Source:                    "file://" + filepath.ToSlash(release),
+		SourceType:                tflint.SourceTypeFile,
+		SourceURL:                 release,
+		Signature:                 "attestation",
+		CertificateIdentityRegexp: "^https://ci.example.com/",
+		TrustedRoot:               trustedRoot,
+	})
+
+	// Without the bundle, the release cannot be verified
+	if _, err := config.Install(); err == nil || !strings.Contains(err.Error(), "no attestations found") {
+		t.Fatalf("expected an error for missing attestations, but got %v", err)
+	}
+
+	// The bundle next to checksums.txt is verified with the trusted root
+	if err := os.WriteFile(filepath.Join(release, "v0.1.0", sigstoreBundleFileName), []byte(testSigstoreBundle035), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := config.Install(); err == nil || !strings.Contains(err.Error(), "failed to load trusted root from "+trustedRoot) {
+		t.Fatalf("expected an error to load the trusted root, but got %v", err)
+	}
+}
+
+func Test_Install_fromHTTPSSource(t *testing.T) {
+	original := PluginRoot
+	PluginRoot = t.TempDir()
+	defer func() { PluginRoot = original }()
+
+	mirror := t.TempDir()
+	writeRelease(t, mirror, "plugin binary", nil)
+	server := httptest.NewServer(http.FileServer(http.Dir(mirror)))
+	defer server.Close()
+
+	config := NewInstallConfig(tflint.EmptyConfig(), &tflint.PluginConfig{
+		Name:       "foo",
+		Enabled:    true,
+		Version:    "0.1.0",
+		Source:     server.URL,
+		SourceType: tflint.SourceTypeHTTPS,
+		SourceURL:  server.URL,
+	})
+
+	// Without signing keys, the checksums are verified, but the signature is not
+	path, locked, err := config.InstallWithLock(nil, false)
+	if err != ErrPluginNotVerified {
+		t.Fatalf("expected ErrPluginNotVerified, but got %v", err)
+	}
+	got, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != "plugin binary" {
+		t.Fatalf("Installed binary is invalid: %s", got)
+	}
+	if locked.Platforms[currentPlatform()].Binary != fmt.Sprintf("%x", sha256.Sum256([]byte("plugin binary"))) {
+		t.Fatalf("Locked binary hash is invalid: %s", locked.Platforms[currentPlatform()].Binary)
+	}
+}
+
+func Test_releaseSource_APIs(t *testing.T) {
+	cases := []struct {
+		Name       string
+		SourceType string
+		Owner      string
+		TokenEnv   string
+		APIPath    string
+		Header     string
+		HeaderVal  string
+		Response   func(serverURL string) string
+	}{
+		{
+			Name:       "GitLab",
+			SourceType: tflint.SourceTypeGitLab,
+			Owner:      "group/subgroup",
+			TokenEnv:   "GITLAB_TOKEN",
+			APIPath:    "/api/v4/projects/group%2Fsubgroup%2Ftflint-ruleset-foo/releases/v0.1.0",
+			Header:     "PRIVATE-TOKEN",
+			HeaderVal:  "secret",
+			Response: func(serverURL string) string {
+				return fmt.Sprintf(`{"assets":{"links":[{"name":"checksums.txt","url":"%s/other","direct_asset_url":"%s/assets/checksums.txt"}]}}`, serverURL, serverURL)
+			},
+		},
+		{
+			Name:       "Gitea",
+			SourceType: tflint.SourceTypeGitea,
+			Owner:      "owner",
+			TokenEnv:   "GITEA_TOKEN",
+			APIPath:    "/api/v1/repos/owner/tflint-ruleset-foo/releases/tags/v0.1.0",
+			Header:     "Authorization",
+			HeaderVal:  "token secret",
+			Response: func(serverURL string) string {
+				return fmt.Sprintf(`{"assets":[{"name":"checksums.txt","browser_download_url":"%s/assets/checksums.txt"}]}`, serverURL)
+			},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.Name, func(t *testing.T) {
+			var server *httptest.Server
+			server = httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if got := r.Header.Get(tc.Header); got != tc.HeaderVal {
+					http.Error(w, fmt.Sprintf("unexpected %s: %s", tc.Header, got), http.StatusUnauthorized)
+					return
+				}
+				switch r.URL.EscapedPath() {
+				case tc.APIPath:
+					fmt.Fprint(w, tc.Response(server.URL))
+				case "/assets/checksums.txt":
+					fmt.Fprint(w, "checksums")
+				default:
+					http.NotFound(w, r)
+				}
+			}))
+			defer server.Close()
+
+			originalTransport := http.DefaultTransport
+			http.DefaultTransport = server.Client().Transport
+			defer func() { http.DefaultTransport = originalTransport }()
+			t.Setenv(tc.TokenEnv, "secret")
+
+			config := NewInstallConfig(tflint.EmptyConfig(), &tflint.PluginConfig{
+				Name:        "foo",
+				Version:     "0.1.0",
+				SourceType:  tc.SourceType,
+				SourceHost:  strings.TrimPrefix(server.URL, "https://"),
+				SourceOwner: tc.Owner,
+				SourceRepo:  "tflint-ruleset-foo",
+			})
+			source, err := config.newReleaseSource(t.Context())
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			file, err := source.download(t.Context(), "checksums.txt")
+			if file != nil {
+				defer os.Remove(file.Name())
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, err := os.ReadFile(file.Name())
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(got) != "checksums" {
+				t.Fatalf("Downloaded asset is invalid: %s", got)
+			}
+
+			if _, err := source.download(t.Context(), "checksums.txt.sig"); err != errAssetNotFound {
+				t.Fatalf("expected errAssetNotFound, but got %v", err)
+			}
+		})
+	}
+}
+
+func Test_InstallPath_sources(t *testing.T) {
+	cases := []struct {
+		Source     string
+		SourceType string
+		Expected   string
+	}{
+		{Source: "github.com/foo/tflint-ruleset-foo", Expected: "github.com/foo/tflint-ruleset-foo/0.1.0/tflint-ruleset-foo"},
+		{Source: "gitlab::gitlab.com/group/subgroup/tflint-ruleset-foo", SourceType: tflint.SourceTypeGitLab, Expected: "gitlab/gitlab.com/group/subgroup/tflint-ruleset-foo/0.1.0/tflint-ruleset-foo"},
+		{Source: "gitea::codeberg.org/foo/tflint-ruleset-foo", SourceType: tflint.SourceTypeGitea, Expected: "gitea/codeberg.org/foo/tflint-ruleset-foo/0.1.0/tflint-ruleset-foo"},
+		{Source: "https://mirror.example.com:8443/tflint-ruleset-foo", SourceType: tflint.SourceTypeHTTPS, Expected: "https/mirror.example.com_8443/tflint-ruleset-foo/0.1.0/tflint-ruleset-foo"},
+		{Source: "file:///srv/mirror/tflint-ruleset-foo", SourceType: tflint.SourceTypeFile, Expected: "file/srv/mirror/tflint-ruleset-foo/0.1.0/tflint-ruleset-foo"},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.Source, func(t *testing.T) {
+			config := NewInstallConfig(tflint.EmptyConfig(), &tflint.PluginConfig{Name: "foo", Version: "0.1.0", Source: tc.Source, SourceType: tc.SourceType})
+			if got := config.InstallPath(); got != filepath.FromSlash(tc.Expected) {
+				t.Errorf("expected=%s, got=%s", filepath.FromSlash(tc.Expected), got)
+			}
+		})
+	}
+}
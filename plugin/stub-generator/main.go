@@ -37,6 +37,7 @@ func main() {
 	execCommand("go", "build", "-o", pluginDir+"/tflint-ruleset-testing"+fileExt(), "./sources/testing/main.go")
 	execCommand("go", "build", "-o", pluginDir+"/tflint-ruleset-customrulesettesting"+fileExt(), "./sources/customrulesettesting/main.go")
 	execCommand("go", "build", "-o", pluginDir+"/tflint-ruleset-incompatiblehost"+fileExt(), "./sources/incompatiblehost/main.go")
+	execCommand("go", "build", "-o", pluginDir+"/tflint-ruleset-failing"+fileExt(), "./sources/failing/main.go")
 	execCommand("go", "build", "-o", "../../integrationtest/inspection/plugin/.tflint.d/plugins/tflint-ruleset-example"+fileExt(), "./sources/example/main.go")
 }
 
@@ -0,0 +1,126 @@
+package main
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/terraform-linters/tflint-plugin-sdk/hclext"
+	"github.com/terraform-linters/tflint-plugin-sdk/plugin"
+	"github.com/terraform-linters/tflint-plugin-sdk/tflint"
+)
+
+func main() {
+	plugin.Serve(&plugin.ServeOpts{
+		RuleSet: &tflint.BuiltinRuleSet{
+			Name:    "failing",
+			Version: "0.1.0",
+			Rules: []tflint.Rule{
+				NewFailingIssueRule(),
+				NewFailingCheckRule(),
+			},
+		},
+	})
+}
+
+// FailingIssueRule emits an issue for each aws_instance before FailingCheckRule fails
+type FailingIssueRule struct {
+	tflint.DefaultRule
+}
+
+// NewFailingIssueRule returns a new rule
+func NewFailingIssueRule() *FailingIssueRule {
+	return &FailingIssueRule{}
+}
+
+// Name returns the rule name
+func (r *FailingIssueRule) Name() string {
+	return "failing_issue"
+}
+
+// Enabled returns whether the rule is enabled by default
+func (r *FailingIssueRule) Enabled() bool {
+	return true
+}
+
+// Severity returns the rule severity
+func (r *FailingIssueRule) Severity() tflint.Severity {
+	return tflint.ERROR
+}
+
+// Link returns the rule reference link
+func (r *FailingIssueRule) Link() string {
+	return ""
+}
+
+// Check emits an issue for each aws_instance
+func (r *FailingIssueRule) Check(runner tflint.Runner) error {
+	resources, err := runner.GetResourceContent("aws_instance", &hclext.BodySchema{}, nil)
+	if err != nil {
+		return err
+	}
+
+	for _, resource := range resources.Blocks {
+		if err := runner.EmitIssue(r, "issue emitted before the failure", resource.DefRange); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// FailingCheckRule fails on failing_check resources.
+// If the name of the resource is "hang", it never returns instead.
+// It also fails on files autofixed by the testing plugin, so that it fails in the second attempt of --fix.
+type FailingCheckRule struct {
+	tflint.DefaultRule
+}
+
+// NewFailingCheckRule returns a new rule
+func NewFailingCheckRule() *FailingCheckRule {
+	return &FailingCheckRule{}
+}
+
+// Name returns the rule name
+func (r *FailingCheckRule) Name() string {
+	return "failing_check"
+}
+
+// Enabled returns whether the rule is enabled by default
+func (r *FailingCheckRule) Enabled() bool {
+	return true
+}
+
+// Severity returns the rule severity
+func (r *FailingCheckRule) Severity() tflint.Severity {
+	return tflint.ERROR
+}
+
+// Link returns the rule reference link
+func (r *FailingCheckRule) Link() string {
+	return ""
+}
+
+// Check fails or hangs on failing_check resources, and fails on autofixed files
+func (r *FailingCheckRule) Check(runner tflint.Runner) error {
+	files, err := runner.GetFiles()
+	if err != nil {
+		return err
+	}
+	for _, file := range files {
+		if strings.Contains(string(file.Bytes), "# autofixed") {
+			return errors.New("an error occurred in Check after autofix")
+		}
+	}
+
+	resources, err := runner.GetResourceContent("failing_check", &hclext.BodySchema{}, nil)
+	if err != nil {
+		return err
+	}
+
+	for _, resource := range resources.Blocks {
+		if resource.Labels[1] == "hang" {
+			select {}
+		}
+		return errors.New("an error occurred in Check")
+	}
+	return nil
+}
@@ -0,0 +1,214 @@
+package plugin
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/google/go-github/v81/github"
+	"github.com/hashicorp/go-version"
+	"github.com/terraform-linters/tflint/tflint"
+)
+
+// HasVersionConstraints returns whether the plugin is configured with a version constraint like "~> 0.38".
+// Such plugins must be resolved to an exact version with ResolveVersion or LockFile.Resolve
+// before installing or launching them.
+func (c *InstallConfig) HasVersionConstraints() bool {
+	return c.VersionConstraints != nil
+}
+
+// WithVersion returns a copy of the config whose version is replaced with the passed exact version.
+// The version constraint is kept so that it can be recorded in the lock file.
+func (c *InstallConfig) WithVersion(v string) *InstallConfig {
+	pluginCfg := *c.PluginConfig
+	pluginCfg.Version = v
+	return NewInstallConfig(c.globalConfig, &pluginCfg)
+}
+
+// ResolveVersion resolves the version constraint to the newest release that matches it.
+// Releases are listed from the source, or from the plugin mirror if set.
+// Pre-releases match only if the constraint explicitly contains a pre-release version.
+// If the version is already exact, the config is returned as is.
+func (c *InstallConfig) ResolveVersion() (*InstallConfig, error) {
+	if !c.HasVersionConstraints() {
+		return c, nil
+	}
+
+	tags, err := c.listReleaseTags(context.Background())
+	if err != nil {
+		return nil, fmt.Errorf("Failed to list releases: %w", err)
+	}
+
+	resolved := newestVersion(tags, c.VersionConstraints)
+	if resolved == "" {
+		return nil, fmt.Errorf(`No releases of plugin "%s" match the version constraint "%s"`, c.Name, c.Version)
+	}
+
+	log.Printf(`[DEBUG] Resolved the version constraint "%s" of plugin "%s" to %s`, c.Version, c.Name, resolved)
+	return c.WithVersion(resolved), nil
+}
+
+// LatestVersion returns the version of the newest release in the source, excluding pre-releases.
+// Unlike ResolveVersion, the configured version and constraint are ignored.
+func (c *InstallConfig) LatestVersion() (string, error) {
+	tags, err := c.listReleaseTags(context.Background())
+	if err != nil {
+		return "", fmt.Errorf("Failed to list releases: %w", err)
+	}
+
+	latest := newestVersion(tags, nil)
+	if latest == "" {
+		return "", fmt.Errorf(`No releases of plugin "%s" found`, c.Name)
+	}
+	return latest, nil
+}
+
+// newestVersion returns the newest version of the tags that matches the constraints, without the "v" prefix.
+// If constraints are nil, pre-releases are excluded. Returns an empty string if no tags match.
+func newestVersion(tags []string, constraints version.Constraints) string {
+	var newest *version.Version
+	var ret string
+	for _, tag := range tags {
+		// Tags must be in a format like `v1.1.1`. See TagName
+		if !strings.HasPrefix(tag, "v") {
+			continue
+		}
+		v, err := version.NewVersion(strings.TrimPrefix(tag, "v"))
+		if err != nil {
+			log.Printf("[DEBUG] Ignore release %s: %s", tag, err)
+			continue
+		}
+		if constraints != nil && !constraints.Check(v) {
+			continue
+		}
+		if constraints == nil && v.Prerelease() != "" {
+			continue
+		}
+		if newest == nil || v.GreaterThan(newest) {
+			newest = v
+			ret = strings.TrimPrefix(tag, "v")
+		}
+	}
+	return ret
+}
+
+// listReleaseTags returns tag names of all releases in the source.
+// Draft releases are not included.
+func (c *InstallConfig) listReleaseTags(ctx context.Context) ([]string, error) {
+	if dir := MirrorDir(); dir != "" {
+		return listDirNames(filepath.Join(dir, c.sourceDir()))
+	}
+
+	switch c.SourceType {
+	case tflint.SourceTypeHTTPS:
+		return nil, fmt.Errorf("HTTPS sources do not support version constraints because releases cannot be listed. Use an exact version instead")
+
+	case tflint.SourceTypeFile:
+		return listDirNames(c.SourceURL)
+
+	case tflint.SourceTypeGitLab:
+		source := c.newGitLabAPISource()
+		tags := []string{}
+		for page := 1; ; page++ {
+			var releases []struct {
+				TagName string `json:"tag_name"`
+			}
+			if err := source.getJSON(ctx, fmt.Sprintf("%s/releases?per_page=100&page=%d", c.gitLabProjectURL(), page), &releases); err != nil {
+				return nil, fmt.Errorf("Failed to fetch GitLab releases: %w", err)
+			}
+			for _, release := range releases {
+				tags = append(tags, release.TagName)
+			}
+			if len(releases) < 100 {
+				return tags, nil
+			}
+		}
+
+	case tflint.SourceTypeGitea:
+		source := c.newGiteaAPISource()
+		tags := []string{}
+		for page := 1; ; page++ {
+			var releases []struct {
+				TagName string `json:"tag_name"`
+				Draft   bool   `json:"draft"`
+			}
+			if err := source.getJSON(ctx, fmt.Sprintf("%s/releases?limit=50&page=%d", c.giteaRepoURL(), page), &releases); err != nil {
+				return nil, fmt.Errorf("Failed to fetch Gitea releases: %w", err)
+			}
+			for _, release := range releases {
+				if !release.Draft {
+					tags = append(tags, release.TagName)
+				}
+			}
+			if len(releases) < 50 {
+				return tags, nil
+			}
+		}
+
+	default:
+		client, err := newGitHubClient(ctx, c)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to create GitHub client: %s", err)
+		}
+		tags := []string{}
+		opts := &github.ListOptions{PerPage: 100}
+		for {
+			releases, resp, err := client.Repositories.ListReleases(ctx, c.SourceOwner, c.SourceRepo, opts)
+			if err != nil {
+				return nil, fmt.Errorf("Failed to fetch GitHub releases: %w", err)
+			}
+			for _, release := range releases {
+				if !release.GetDraft() {
+					tags = append(tags, release.GetTagName())
+				}
+			}
+			if resp.NextPage == 0 {
+				return tags, nil
+			}
+			opts.Page = resp.NextPage
+		}
+	}
+}
+
+// listDirNames returns names of directories in the directory.
+// In file sources and mirrors, each directory is a release named by the tag.
+func listDirNames(dir string) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	names := []string{}
+	for _, entry := range entries {
+		if entry.IsDir() {
+			names = append(names, entry.Name())
+		}
+	}
+	return names, nil
+}
+
+// Resolve returns the config with the version recorded in the lock file if the plugin has a version constraint.
+// Returns nil if the lock file does not record a version of the same source that satisfies the constraint.
+// If the version is already exact, the config is returned as is.
+func (l *LockFile) Resolve(config *InstallConfig) *InstallConfig {
+	if !config.HasVersionConstraints() {
+		return config
+	}
+
+	locked, exists := l.Plugins[config.Name]
+	if !exists || locked.Source != config.Source {
+		return nil
+	}
+	v, err := version.NewVersion(locked.Version)
+	if err != nil || !config.VersionConstraints.Check(v) {
+		return nil
+	}
+	return config.WithVersion(locked.Version)
+}
+
+// unresolvedVersionError returns an error for plugins whose version constraint is not resolved in the lock file.
+func (l *LockFile) unresolvedVersionError(config *InstallConfig) error {
+	return fmt.Errorf(`Plugin "%s" has the version constraint "%s", but %s does not record a matching version. Run "tflint --init" to resolve it`, config.Name, config.Version, l.path)
+}
@@ -0,0 +1,236 @@
+package plugin
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/go-version"
+	"github.com/terraform-linters/tflint/tflint"
+)
+
+func Test_ResolveVersion(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{"v0.1.0", "v0.2.0", "v0.3.0-rc1", "v1.0.0", "latest"} {
+		if err := os.Mkdir(filepath.Join(dir, name), 0o755); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	cases := []struct {
+		Name       string
+		Constraint string
+		Expected   string
+		Error      string
+	}{
+		{
+			Name:       "pessimistic constraint",
+			Constraint: "~> 0.1",
+			Expected:   "0.2.0",
+		},
+		{
+			Name:       "range",
+			Constraint: ">= 0.1, < 0.2",
+			Expected:   "0.1.0",
+		},
+		{
+			Name:       "newest",
+			Constraint: ">= 0.1",
+			Expected:   "1.0.0",
+		},
+		{
+			Name:       "pre-release",
+			Constraint: "0.3.0-rc1",
+			Expected:   "0.3.0-rc1",
+		},
+		{
+			Name:       "no matches",
+			Constraint: "~> 2.0",
+			Error:      `No releases of plugin "foo" match the version constraint "~> 2.0"`,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.Name, func(t *testing.T) {
+			config := NewInstallConfig(tflint.EmptyConfig(), &tflint.PluginConfig{
+				Name:               "foo",
+				Version:            tc.Constraint,
+				Source:             "file://" + filepath.ToSlash(dir),
+				SourceType:         tflint.SourceTypeFile,
+				SourceURL:          dir,
+				VersionConstraints: version.MustConstraints(version.NewConstraint(tc.Constraint)),
+			})
+
+			resolved, err := config.ResolveVersion()
+			if tc.Error != "" {
+				if err == nil || err.Error() != tc.Error {
+					t.Fatalf("expected=%s, actual=%v", tc.Error, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if resolved.Version != tc.Expected {
+				t.Fatalf("expected=%s, actual=%s", tc.Expected, resolved.Version)
+			}
+			// The original config is not changed
+			if config.Version != tc.Constraint {
+				t.Fatalf("The original config is changed: %s", config.Version)
+			}
+		})
+	}
+}
+
+func Test_ResolveVersion_HTTPS(t *testing.T) {
+	config := NewInstallConfig(tflint.EmptyConfig(), &tflint.PluginConfig{
+		Name:               "foo",
+		Version:            "~> 0.1",
+		Source:             "https://mirror.example.com/tflint-ruleset-foo",
+		SourceType:         tflint.SourceTypeHTTPS,
+		SourceURL:          "https://mirror.example.com/tflint-ruleset-foo",
+		VersionConstraints: version.MustConstraints(version.NewConstraint("~> 0.1")),
+	})
+
+	_, err := config.ResolveVersion()
+	if err == nil || !strings.Contains(err.Error(), "HTTPS sources do not support version constraints") {
+		t.Fatalf("expected an error for HTTPS sources, but got %v", err)
+	}
+}
+
+func Test_ResolveVersion_APIs(t *testing.T) {
+	cases := []struct {
+		Name       string
+		SourceType string
+		Owner      string
+		Responses  map[string]string
+	}{
+		{
+			Name:       "GitLab",
+			SourceType: tflint.SourceTypeGitLab,
+			Owner:      "group",
+			Responses: map[string]string{
+				"/api/v4/projects/group%2Ftflint-ruleset-foo/releases?page=1&per_page=100": `[{"tag_name":"v0.2.0"},{"tag_name":"v0.1.0"}]`,
+			},
+		},
+		{
+			Name:       "Gitea",
+			SourceType: tflint.SourceTypeGitea,
+			Owner:      "owner",
+			Responses: map[string]string{
+				"/api/v1/repos/owner/tflint-ruleset-foo/releases?limit=50&page=1": `[{"tag_name":"v0.3.0","draft":true},{"tag_name":"v0.2.0"},{"tag_name":"v0.1.0"}]`,
+			},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.Name, func(t *testing.T) {
+			server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				key := fmt.Sprintf("%s?%s", r.URL.EscapedPath(), r.URL.Query().Encode())
+				resp, exists := tc.Responses[key]
+				if !exists {
+					http.NotFound(w, r)
+					return
+				}
+				fmt.Fprint(w, resp)
+			}))
+			defer server.Close()
+
+			originalTransport := http.DefaultTransport
+			http.DefaultTransport = server.Client().Transport
+			defer func() { http.DefaultTransport = originalTransport }()
+
+			config := NewInstallConfig(tflint.EmptyConfig(), &tflint.PluginConfig{
+				Name:               "foo",
+				Version:            "~> 0.1",
+				SourceType:         tc.SourceType,
+				SourceHost:         strings.TrimPrefix(server.URL, "https://"),
+				SourceOwner:        tc.Owner,
+				SourceRepo:         "tflint-ruleset-foo",
+				VersionConstraints: version.MustConstraints(version.NewConstraint("~> 0.1")),
+			})
+
+			resolved, err := config.ResolveVersion()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if resolved.Version != "0.2.0" {
+				t.Fatalf("expected=0.2.0, actual=%s", resolved.Version)
+			}
+		})
+	}
+}
+
+func Test_LockFile_Resolve(t *testing.T) {
+	lock := &LockFile{
+		Plugins: map[string]*LockedPlugin{
+			"aws": {
+				Name:        "aws",
+				Source:      "github.com/terraform-linters/tflint-ruleset-aws",
+				Version:     "0.38.2",
+				Constraints: "~> 0.38",
+			},
+		},
+		path:   LockFileName,
+		exists: true,
+	}
+
+	cases := []struct {
+		Name       string
+		Source     string
+		Constraint string
+		Expected   string
+	}{
+		{
+			Name:       "locked",
+			Source:     "github.com/terraform-linters/tflint-ruleset-aws",
+			Constraint: "~> 0.38",
+			Expected:   "0.38.2",
+		},
+		{
+			Name:       "constraint changed, but still satisfied",
+			Source:     "github.com/terraform-linters/tflint-ruleset-aws",
+			Constraint: ">= 0.30",
+			Expected:   "0.38.2",
+		},
+		{
+			Name:       "not satisfied",
+			Source:     "github.com/terraform-linters/tflint-ruleset-aws",
+			Constraint: "~> 0.39",
+		},
+		{
+			Name:       "source changed",
+			Source:     "github.com/example/tflint-ruleset-aws",
+			Constraint: "~> 0.38",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.Name, func(t *testing.T) {
+			config := NewInstallConfig(tflint.EmptyConfig(), &tflint.PluginConfig{
+				Name:               "aws",
+				Source:             tc.Source,
+				Version:            tc.Constraint,
+				VersionConstraints: version.MustConstraints(version.NewConstraint(tc.Constraint)),
+			})
+
+			resolved := lock.Resolve(config)
+			if tc.Expected == "" {
+				if resolved != nil {
+					t.Fatalf("expected not to be resolved, but got %s", resolved.Version)
+				}
+				return
+			}
+			if resolved == nil {
+				t.Fatal("expected to be resolved, but got nil")
+			}
+			if resolved.Version != tc.Expected {
+				t.Fatalf("expected=%s, actual=%s", tc.Expected, resolved.Version)
+			}
+		})
+	}
+}
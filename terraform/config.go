@@ -71,8 +71,9 @@ func BuildConfig(root *Module, walker ModuleWalker, originalWd string, variables
 
 	ctx := &Evaluator{
 		Meta: &ContextMeta{
-			Env:                Workspace(),
+			Env:                WorkspaceIn(root.WorkingDir),
 			OriginalWorkingDir: originalWd,
+			BaseDir:            root.WorkingDir,
 		},
 		ModulePath:     cfg.Path.UnkeyedInstanceShim(),
 		Config:         cfg.Root,
@@ -263,8 +264,9 @@ func buildChildCtx(parent *Config, parentCtx *Evaluator, callName string, child
 
 	return &Evaluator{
 		Meta: &ContextMeta{
-			Env:                Workspace(),
+			Env:                parentCtx.Meta.Env,
 			OriginalWorkingDir: parentCtx.Meta.OriginalWorkingDir,
+			BaseDir:            parentCtx.Meta.BaseDir,
 		},
 		ModulePath:     child.Path.UnkeyedInstanceShim(),
 		Config:         parent.Root,
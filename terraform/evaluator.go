@@ -21,6 +21,9 @@ import (
 type ContextMeta struct {
 	Env                string
 	OriginalWorkingDir string
+	// BaseDir is the directory where relative paths in file functions are resolved.
+	// Empty means the current directory.
+	BaseDir string
 }
 
 type Evaluator struct {
@@ -58,6 +61,9 @@ func (e *Evaluator) scope() *lang.Scope {
 		CallStack:           lang.NewCallStack(),
 		ResolvedLocalValues: map[string]cty.Value{},
 	}
+	if e.Meta != nil {
+		scope.BaseDir = e.Meta.BaseDir
+	}
 	scope.Data = &evaluationData{
 		Scope:          scope,
 		Meta:           e.Meta,
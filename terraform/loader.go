@@ -25,7 +25,8 @@ type Loader struct {
 	parser  *Parser
 	modules moduleMgr
 
-	baseDir string
+	baseDir    string
+	workingDir string
 }
 
 // NewLoader creates and returns a loader that reads configuration from the
@@ -39,8 +40,6 @@ type Loader struct {
 // If an original working dir is passed, the paths of the loaded files will
 // be relative to that directory.
 func NewLoader(fs afero.Afero, originalWd string) (*Loader, error) {
-	log.Print("[INFO] Initialize new loader")
-
 	wd, err := os.Getwd()
 	if err != nil {
 		return nil, fmt.Errorf("failed to determine current working directory: %s", err)
@@ -50,16 +49,36 @@ func NewLoader(fs afero.Afero, originalWd string) (*Loader, error) {
 		return nil, fmt.Errorf("failed to determine base dir: %s", err)
 	}
 
+	return newLoader(fs, baseDir, "", nil)
+}
+
+// NewLoaderWithCache creates a loader that reads the configuration in the working directory
+// without referencing the current directory. The given filesystem must resolve relative paths
+// against the working directory, and the paths of the loaded files are relative to it.
+//
+// Files and called modules parsed by previous loaders sharing the cache are reused,
+// as long as their sources are unchanged. Passing nil disables the cache.
+func NewLoaderWithCache(fs afero.Afero, workingDir string, cache *ParseCache) (*Loader, error) {
+	return newLoader(fs, ".", workingDir, cache)
+}
+
+func newLoader(fs afero.Afero, baseDir string, workingDir string, cache *ParseCache) (*Loader, error) {
+	log.Print("[INFO] Initialize new loader")
+
+	parser := NewParser(fs)
+	parser.cache = cache
+
 	ret := &Loader{
-		parser: NewParser(fs),
+		parser: parser,
 		modules: moduleMgr{
 			fs:       fs,
 			manifest: moduleManifest{},
 		},
-		baseDir: baseDir,
+		baseDir:    baseDir,
+		workingDir: workingDir,
 	}
 
-	err = ret.modules.readModuleManifest()
+	err := ret.modules.readModuleManifest()
 	if err != nil {
 		return nil, fmt.Errorf("failed to read module manifest: %s", err)
 	}
@@ -69,7 +88,11 @@ func NewLoader(fs afero.Afero, originalWd string) (*Loader, error) {
 
 // LoadRootModule reads the root module using the loader's parser options.
 func (l *Loader) LoadRootModule(dir string) (*Module, hcl.Diagnostics) {
-	return l.parser.LoadConfigDir(l.baseDir, dir)
+	mod, diags := l.parser.LoadConfigDir(l.baseDir, dir)
+	if mod != nil {
+		mod.WorkingDir = l.workingDir
+	}
+	return mod, diags
 }
 
 // ModuleWalker returns a walker suitable for loading already-installed modules.
@@ -111,7 +134,7 @@ func (l *Loader) moduleWalkerFunc(walkLocal, walkRemote bool) ModuleWalkerFunc {
 					},
 				}
 			}
-			mod, diags := l.parser.LoadConfigDir(l.baseDir, dir)
+			mod, diags := l.parser.loadModuleDir(l.baseDir, dir)
 			return mod, nil, diags
 
 		case addrs.ModuleSourceRemote:
@@ -135,7 +158,7 @@ func (l *Loader) moduleWalkerFunc(walkLocal, walkRemote bool) ModuleWalkerFunc {
 				}
 			}
 			log.Printf("[DEBUG] Trying to load the remote module: key=%s, version=%s, dir=%s", key, record.VersionStr, record.Dir)
-			mod, diags := l.parser.LoadConfigDir(l.baseDir, record.Dir)
+			mod, diags := l.parser.loadModuleDir(l.baseDir, record.Dir)
 			return mod, record.Version, diags
 
 		default:
@@ -161,7 +184,7 @@ func (l *Loader) LoadValuesFiles(dir string, files ...string) ([]InputValues, hc
 		return nil, diags
 	}
 	defaultVarsFile := filepath.Join(dir, defaultVarsFilename)
-	if _, err := os.Stat(defaultVarsFile); err == nil {
+	if _, err := l.parser.fs.Stat(defaultVarsFile); err == nil {
 		autoLoadFiles = append([]string{defaultVarsFile}, autoLoadFiles...)
 	}
 
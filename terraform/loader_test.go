@@ -716,3 +716,61 @@ func testChildModule(t *testing.T, config *Config, key string, wantPath string)
 		t.Fatalf("`%s` module path: want=%s, got=%s", key, wantPath, modulePath)
 	}
 }
+
+func TestLoadConfig_withCache(t *testing.T) {
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	fs := afero.Afero{Fs: afero.NewMemMapFs()}
+	if err := fs.WriteFile("main.tf", []byte(`module "child" { source = "./child" }`), os.ModePerm); err != nil {
+		t.Fatal(err)
+	}
+	if err := fs.WriteFile("child/main.tf", []byte(`variable "foo" {}`), os.ModePerm); err != nil {
+		t.Fatal(err)
+	}
+
+	cache := NewParseCache()
+	load := func() *Config {
+		loader, err := NewLoaderWithCache(fs, wd, cache)
+		if err != nil {
+			t.Fatal(err)
+		}
+		rootMod, diags := loader.LoadRootModule(".")
+		if diags.HasErrors() {
+			t.Fatal(diags)
+		}
+		config, diags := BuildConfig(rootMod, loader.ModuleWalker(CallLocalModule), wd)
+		if diags.HasErrors() {
+			t.Fatal(diags)
+		}
+		if _, exists := loader.Files()[filepath.Join("child", "main.tf")]; !exists {
+			t.Fatalf("child module file is not registered: %#v", loader.Files())
+		}
+		return config
+	}
+
+	first := load()
+	second := load()
+	if first.Module == second.Module {
+		t.Fatal("root module should not be reused")
+	}
+	if first.Module.Files["main.tf"] != second.Module.Files["main.tf"] {
+		t.Fatal("unchanged file should be reused")
+	}
+	if first.Children["child"].Module != second.Children["child"].Module {
+		t.Fatal("unchanged module should be reused")
+	}
+
+	if err := fs.WriteFile("child/main.tf", []byte(`variable "bar" {}`), os.ModePerm); err != nil {
+		t.Fatal(err)
+	}
+	third := load()
+	if second.Children["child"].Module == third.Children["child"].Module {
+		t.Fatal("changed module should not be reused")
+	}
+	if _, exists := third.Children["child"].Module.Variables["bar"]; !exists {
+		t.Fatalf("changed module should be reloaded: %#v", third.Children["child"].Module.Variables)
+	}
+}
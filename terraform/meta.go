@@ -20,12 +20,22 @@ func dataDir() string {
 }
 
 func Workspace() string {
+	return WorkspaceIn("")
+}
+
+// WorkspaceIn is the same as Workspace, but resolves the data dir relative to the given directory
+// instead of the current directory. An empty directory means the current directory.
+func WorkspaceIn(dir string) string {
 	if envVar := os.Getenv("TF_WORKSPACE"); envVar != "" {
 		log.Printf("[INFO] TF_WORKSPACE environment variable found: %s", envVar)
 		return envVar
 	}
 
-	envData, _ := os.ReadFile(filepath.Join(dataDir(), "environment"))
+	dataDir := dataDir()
+	if !filepath.IsAbs(dataDir) {
+		dataDir = filepath.Join(dir, dataDir)
+	}
+	envData, _ := os.ReadFile(filepath.Join(dataDir, "environment"))
 	current := string(bytes.TrimSpace(envData))
 	if current != "" {
 		log.Printf("[INFO] environment file found: %s", current)
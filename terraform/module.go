@@ -18,6 +18,11 @@ type Module struct {
 
 	SourceDir string
 
+	// WorkingDir is the directory where the root module was loaded from.
+	// Relative paths, such as those in file functions, are resolved against it.
+	// Empty means the current directory.
+	WorkingDir string
+
 	Sources map[string][]byte
 	Files   map[string]*hcl.File
 
@@ -0,0 +1,79 @@
+package terraform
+
+import (
+	"bytes"
+	"sync"
+
+	"github.com/hashicorp/hcl/v2"
+)
+
+// ParseCache retains files and modules parsed by loaders, so that subsequent
+// loaders can reuse them as long as the sources are unchanged. This is useful
+// for long-running processes like the language server, which reloads
+// configurations every time a file is edited.
+//
+// Only called modules are cached because the root module can be rebuilt by autofixes.
+// Parsed files are never modified, so they can be shared between modules.
+type ParseCache struct {
+	mu      sync.Mutex
+	files   map[string]*cachedFile
+	modules map[string]*Module
+}
+
+type cachedFile struct {
+	file  *hcl.File
+	diags hcl.Diagnostics
+}
+
+// NewParseCache returns a new empty cache.
+func NewParseCache() *ParseCache {
+	return &ParseCache{
+		files:   map[string]*cachedFile{},
+		modules: map[string]*Module{},
+	}
+}
+
+// file returns the cached file if it was parsed from the same source.
+func (c *ParseCache) file(path string, src []byte) (*hcl.File, hcl.Diagnostics, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	cached, exists := c.files[path]
+	if !exists || !bytes.Equal(cached.file.Bytes, src) {
+		return nil, nil, false
+	}
+	return cached.file, cached.diags, true
+}
+
+func (c *ParseCache) addFile(path string, file *hcl.File, diags hcl.Diagnostics) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.files[path] = &cachedFile{file: file, diags: diags}
+}
+
+// module returns the cached module if all files in the module directory are unchanged.
+// sources is the current sources of the files in the directory.
+func (c *ParseCache) module(dir string, sources map[string][]byte) (*Module, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	mod, exists := c.modules[dir]
+	if !exists || len(mod.Sources) != len(sources) {
+		return nil, false
+	}
+	for path, src := range sources {
+		cached, exists := mod.Sources[path]
+		if !exists || !bytes.Equal(cached, src) {
+			return nil, false
+		}
+	}
+	return mod, true
+}
+
+func (c *ParseCache) addModule(dir string, mod *Module) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.modules[dir] = mod
+}
@@ -2,6 +2,7 @@ package terraform
 
 import (
 	"fmt"
+	"log"
 	"os"
 	"path/filepath"
 	"sort"
@@ -19,8 +20,9 @@ import (
 // It retains a cache of all files that are loaded so that they can be used
 // to create source code snippets in diagnostics, etc.
 type Parser struct {
-	fs afero.Afero
-	p  *hclparse.Parser
+	fs    afero.Afero
+	p     *hclparse.Parser
+	cache *ParseCache
 }
 
 // NewParser creates and returns a new Parser that reads files from the given
@@ -211,12 +213,64 @@ func (p *Parser) loadHCLFile(baseDir, path string) (*hcl.File, hcl.Diagnostics)
 		}
 	}
 
+	if p.cache != nil {
+		if file, diags, ok := p.cache.file(realPath, src); ok {
+			p.p.AddFile(realPath, file)
+			return file, diags
+		}
+	}
+
+	var file *hcl.File
+	var diags hcl.Diagnostics
 	switch {
 	case strings.HasSuffix(path, ".json"):
-		return p.p.ParseJSON(src, realPath)
+		file, diags = p.p.ParseJSON(src, realPath)
 	default:
-		return p.p.ParseHCL(src, realPath)
+		file, diags = p.p.ParseHCL(src, realPath)
 	}
+
+	if p.cache != nil && file != nil {
+		p.cache.addFile(realPath, file, diags)
+	}
+	return file, diags
+}
+
+// loadModuleDir is the same as LoadConfigDir, but reuses the cached module
+// if all files in the directory are unchanged.
+func (p *Parser) loadModuleDir(baseDir, dir string) (*Module, hcl.Diagnostics) {
+	if p.cache == nil {
+		return p.LoadConfigDir(baseDir, dir)
+	}
+
+	primaries, overrides, diags := p.configDirFiles(baseDir, dir)
+	if diags.HasErrors() {
+		return nil, diags
+	}
+	sources := map[string][]byte{}
+	for _, path := range append(primaries, overrides...) {
+		src, err := p.fs.ReadFile(path)
+		if err != nil {
+			// Let LoadConfigDir report the error
+			return p.LoadConfigDir(baseDir, dir)
+		}
+		sources[filepath.Join(baseDir, path)] = src
+	}
+
+	key := filepath.Join(baseDir, dir)
+	if mod, ok := p.cache.module(key, sources); ok {
+		log.Printf("[DEBUG] Reuse the cached module: dir=%s", key)
+		// Register files so that they can be referenced via Files and Sources
+		for path, file := range mod.Files {
+			p.p.AddFile(path, file)
+		}
+		return mod, nil
+	}
+
+	mod, diags := p.LoadConfigDir(baseDir, dir)
+	if len(diags) == 0 {
+		p.cache.addModule(key, mod)
+	}
+	return mod, diags
 }
 
 // Sources returns a map of the cached source buffers for all files that
@@ -4,10 +4,13 @@ import (
 	"fmt"
 	"log"
 	"maps"
+	"net/url"
 	"os"
 	"path/filepath"
+	"regexp"
 	"slices"
 	"strings"
+	"time"
 
 	"github.com/hashicorp/go-version"
 	hcl "github.com/hashicorp/hcl/v2"
@@ -61,6 +64,21 @@ var innerConfigSchema = &hcl.BodySchema{
 	},
 }
 
+// removedConfigAttributes are the attributes of the "config" block that have been removed.
+// They remain in innerConfigSchema in order to return errors explaining the replacement.
+var removedConfigAttributes = []string{"module"}
+
+// ConfigAttributes returns the names of the attributes available in the "config" block.
+func ConfigAttributes() []string {
+	names := []string{}
+	for _, attr := range innerConfigSchema.Attributes {
+		if !slices.Contains(removedConfigAttributes, attr.Name) {
+			names = append(names, attr.Name)
+		}
+	}
+	return names
+}
+
 var validFormats = []string{
 	"default",
 	"json",
@@ -68,6 +86,11 @@ var validFormats = []string{
 	"junit",
 	"compact",
 	"sarif",
+	"ndjson",
+	"markdown",
+	"html",
+	"rdjson",
+	"rdjsonl",
 }
 
 const (
@@ -84,6 +107,15 @@ var validPluginSignatures = []string{
 	signatureModeNone,
 }
 
+// Source types of plugins other than GitHub.
+// GitHub sources do not have a source type for backward compatibility.
+const (
+	SourceTypeGitLab = "gitlab"
+	SourceTypeGitea  = "gitea"
+	SourceTypeHTTPS  = "https"
+	SourceTypeFile   = "file"
+)
+
 // Config describes the behavior of TFLint
 type Config struct {
 	CallModuleType    terraform.CallModuleType
@@ -143,12 +175,33 @@ type PluginConfig struct {
 	Signature  string `hcl:"signature,optional"`
 	SigningKey string `hcl:"signing_key,optional"`
 
+	// Keyless signing identities for artifact attestations. By default, attestations must be signed
+	// by GitHub Actions in the source repository.
+	CertificateIdentityRegexp string `hcl:"certificate_identity_regexp,optional"`
+	CertificateOIDCIssuer     string `hcl:"certificate_oidc_issuer,optional"`
+	// TrustedRoot is the path to a Sigstore trusted_root.json used instead of the public good instance.
+	TrustedRoot string `hcl:"trusted_root,optional"`
+
+	// Timeout is the maximum duration of a check by the plugin, like "2m".
+	Timeout string `hcl:"timeout,optional"`
+
 	Body hcl.Body `hcl:",remain"`
 
 	// Parsed source attributes
+	// SourceType is empty for GitHub sources. SourceURL is set only for "https" and "file" sources.
+	// For GitLab sources, SourceOwner is the namespace of the project, which may contain subgroups.
+	SourceType  string
+	SourceURL   string
 	SourceHost  string
 	SourceOwner string
 	SourceRepo  string
+
+	// VersionConstraints is set if the version is a constraint like "~> 0.38" instead of an exact version.
+	// The constraint is resolved to the newest matching release by "tflint --init" and recorded in the lock file.
+	VersionConstraints version.Constraints
+
+	// CheckTimeout is the parsed timeout. Zero means no timeout.
+	CheckTimeout time.Duration
 }
 
 // EmptyConfig returns default config
@@ -565,6 +618,12 @@ func (c *Config) IsJSONConfig() bool {
 	return filepath.Ext(c.configPath) == ".json"
 }
 
+// Path returns the path of the loaded config file.
+// Returns an empty string if the default config is used.
+func (c *Config) Path() string {
+	return c.configPath
+}
+
 // Sources returns parsed config file sources.
 // To support bundle plugin config, this function returns c.sources
 // with a merge of the pseudo config file.
@@ -703,15 +762,12 @@ func (c *PluginConfig) validate() error {
 			return fmt.Errorf(`plugin "%s": "version" attribute cannot be omitted when specifying "source"`, c.Name)
 		}
 
-		parts := strings.Split(c.Source, "/")
-		// Expected `github.com/owner/repo` format
-		if len(parts) != 3 {
-			return fmt.Errorf(`plugin "%s": "source" is invalid. Must be a GitHub reference in the format "${host}/${owner}/${repo}"`, c.Name)
+		if err := c.parseSource(); err != nil {
+			return err
+		}
+		if err := c.parseVersion(); err != nil {
+			return err
 		}
-
-		c.SourceHost = parts[0]
-		c.SourceOwner = parts[1]
-		c.SourceRepo = parts[2]
 	}
 
 	if c.Signature != "" {
@@ -721,7 +777,122 @@ func (c *PluginConfig) validate() error {
 		if c.SigningKey != "" && slices.Contains([]string{signatureModeAttestation, signatureModeNone}, c.Signature) {
 			return fmt.Errorf(`plugin "%s": "signing_key" cannot be used when "signature" is %q`, c.Name, c.Signature)
 		}
+		// Artifact attestations are a GitHub feature, but sigstore bundles stored next to the release
+		// can be verified in other sources if the signing identity is set
+		if c.Signature == signatureModeAttestation && c.SourceType != "" && c.CertificateIdentityRegexp == "" {
+			return fmt.Errorf(`plugin "%s": "attestation" signature is only supported for GitHub sources unless "certificate_identity_regexp" is set`, c.Name)
+		}
+		if slices.Contains([]string{signatureModePGP, signatureModeNone}, c.Signature) {
+			for _, attr := range []struct{ name, value string }{
+				{"certificate_identity_regexp", c.CertificateIdentityRegexp},
+				{"certificate_oidc_issuer", c.CertificateOIDCIssuer},
+				{"trusted_root", c.TrustedRoot},
+			} {
+				if attr.value != "" {
+					return fmt.Errorf(`plugin "%s": "%s" cannot be used when "signature" is %q`, c.Name, attr.name, c.Signature)
+				}
+			}
+		}
+	}
+
+	if c.CertificateIdentityRegexp != "" {
+		if _, err := regexp.Compile(c.CertificateIdentityRegexp); err != nil {
+			return fmt.Errorf(`plugin "%s": "%s" is invalid certificate_identity_regexp; %w`, c.Name, c.CertificateIdentityRegexp, err)
+		}
+	}
+
+	if c.Timeout != "" {
+		timeout, err := time.ParseDuration(c.Timeout)
+		if err != nil || timeout <= 0 {
+			return fmt.Errorf(`plugin "%s": "%s" is invalid timeout. Must be a positive duration like "2m"`, c.Name, c.Timeout)
+		}
+		c.CheckTimeout = timeout
+	}
+
+	return nil
+}
+
+// parseVersion parses the version attribute. The version is either an exact version like "0.38.0",
+// or a version constraint like "~> 0.38" in the same syntax as Terraform's version constraints.
+func (c *PluginConfig) parseVersion() error {
+	if _, err := version.NewVersion(c.Version); err == nil {
+		return nil
 	}
 
+	constraints, err := version.NewConstraint(c.Version)
+	if err != nil {
+		return fmt.Errorf(`plugin "%s": "%s" is invalid version. Must be an exact version like "0.1.0" or a version constraint like "~> 0.1"`, c.Name, c.Version)
+	}
+	c.VersionConstraints = constraints
 	return nil
 }
+
+// parseSource parses the source attribute. The following formats are supported:
+//
+//   - GitHub: "${host}/${owner}/${repo}"
+//   - GitLab: "gitlab::${host}/${namespace}/${project}"
+//   - Gitea: "gitea::${host}/${owner}/${repo}"
+//   - HTTPS: "https://${host}/${path}"
+//   - File: "file://${path}"
+func (c *PluginConfig) parseSource() error {
+	switch {
+	case strings.HasPrefix(c.Source, "https://"), strings.HasPrefix(c.Source, "file://"):
+		u, err := url.Parse(c.Source)
+		if err != nil {
+			return fmt.Errorf(`plugin "%s": "source" is invalid URL: %w`, c.Name, err)
+		}
+		if u.Scheme == "https" {
+			if u.Host == "" {
+				return fmt.Errorf(`plugin "%s": "source" is invalid. Must be a URL in the format "https://${host}/${path}"`, c.Name)
+			}
+			c.SourceType = SourceTypeHTTPS
+			c.SourceHost = u.Host
+			c.SourceURL = strings.TrimSuffix(c.Source, "/")
+			return nil
+		}
+		if u.Host != "" || u.Path == "" {
+			return fmt.Errorf(`plugin "%s": "source" is invalid. Must be an absolute path in the format "file:///${path}"`, c.Name)
+		}
+		c.SourceType = SourceTypeFile
+		c.SourceURL = filepath.FromSlash(strings.TrimSuffix(u.Path, "/"))
+		// Windows paths are written as file:///C:/path
+		if len(c.SourceURL) > 2 && c.SourceURL[2] == ':' {
+			c.SourceURL = c.SourceURL[1:]
+		}
+		return nil
+
+	case strings.HasPrefix(c.Source, SourceTypeGitLab+"::"):
+		parts := strings.Split(strings.TrimPrefix(c.Source, SourceTypeGitLab+"::"), "/")
+		if len(parts) < 3 || slices.Contains(parts, "") {
+			return fmt.Errorf(`plugin "%s": "source" is invalid. Must be a GitLab reference in the format "gitlab::${host}/${namespace}/${project}"`, c.Name)
+		}
+		c.SourceType = SourceTypeGitLab
+		c.SourceHost = parts[0]
+		c.SourceOwner = strings.Join(parts[1:len(parts)-1], "/")
+		c.SourceRepo = parts[len(parts)-1]
+		return nil
+
+	case strings.HasPrefix(c.Source, SourceTypeGitea+"::"):
+		parts := strings.Split(strings.TrimPrefix(c.Source, SourceTypeGitea+"::"), "/")
+		if len(parts) != 3 || slices.Contains(parts, "") {
+			return fmt.Errorf(`plugin "%s": "source" is invalid. Must be a Gitea reference in the format "gitea::${host}/${owner}/${repo}"`, c.Name)
+		}
+		c.SourceType = SourceTypeGitea
+		c.SourceHost = parts[0]
+		c.SourceOwner = parts[1]
+		c.SourceRepo = parts[2]
+		return nil
+
+	default:
+		parts := strings.Split(c.Source, "/")
+		// Expected `github.com/owner/repo` format
+		if len(parts) != 3 {
+			return fmt.Errorf(`plugin "%s": "source" is invalid. Must be a GitHub reference in the format "${host}/${owner}/${repo}"`, c.Name)
+		}
+
+		c.SourceHost = parts[0]
+		c.SourceOwner = parts[1]
+		c.SourceRepo = parts[2]
+		return nil
+	}
+}
@@ -4,11 +4,14 @@ import (
 	"errors"
 	"fmt"
 	"os"
+	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/hashicorp/go-version"
 	hcl "github.com/hashicorp/hcl/v2"
 	"github.com/hashicorp/hcl/v2/hclsyntax"
 	"github.com/spf13/afero"
@@ -285,7 +288,7 @@ config {
 }`,
 			},
 			errCheck: func(err error) bool {
-				return err == nil || err.Error() != "invalid is invalid format. Allowed formats are: default, json, checkstyle, junit, compact, sarif"
+				return err == nil || err.Error() != "invalid is invalid format. Allowed formats are: default, json, checkstyle, junit, compact, sarif, ndjson, markdown, html, rdjson, rdjsonl"
 			},
 		},
 		{
@@ -435,6 +438,376 @@ plugin "foo" {
 			},
 			errCheck: neverHappend,
 		},
+		{
+			name: "plugin with version constraint",
+			file: "plugin_with_version_constraint.hcl",
+			files: map[string]string{
+				"plugin_with_version_constraint.hcl": `
+plugin "foo" {
+	enabled = true
+
+	version = "~> 0.38"
+	source = "github.com/foo/bar"
+}`,
+			},
+			want: &Config{
+				CallModuleType:    terraform.CallLocalModule,
+				Force:             false,
+				IgnoreModules:     map[string]bool{},
+				Varfiles:          []string{},
+				Variables:         []string{},
+				DisabledByDefault: false,
+				Rules:             map[string]*RuleConfig{},
+				Plugins: map[string]*PluginConfig{
+					"foo": {
+						Name:               "foo",
+						Enabled:            true,
+						Version:            "~> 0.38",
+						Source:             "github.com/foo/bar",
+						SourceHost:         "github.com",
+						SourceOwner:        "foo",
+						SourceRepo:         "bar",
+						VersionConstraints: version.MustConstraints(version.NewConstraint("~> 0.38")),
+					},
+					"terraform": {
+						Name:    "terraform",
+						Enabled: true,
+					},
+				},
+			},
+			errCheck: neverHappend,
+		},
+		{
+			name: "plugin with invalid version",
+			file: "plugin_with_invalid_version.hcl",
+			files: map[string]string{
+				"plugin_with_invalid_version.hcl": `
+plugin "foo" {
+	enabled = true
+
+	version = "latest"
+	source = "github.com/foo/bar"
+}`,
+			},
+			errCheck: func(err error) bool {
+				return err == nil || err.Error() != `plugin "foo": "latest" is invalid version. Must be an exact version like "0.1.0" or a version constraint like "~> 0.1"`
+			},
+		},
+		{
+			name: "plugin with GitLab source",
+			file: "plugin_with_gitlab_source.hcl",
+			files: map[string]string{
+				"plugin_with_gitlab_source.hcl": `
+plugin "foo" {
+	enabled = true
+
+	version = "0.1.0"
+	source = "gitlab::gitlab.example.com/group/subgroup/bar"
+}`,
+			},
+			want: &Config{
+				CallModuleType:    terraform.CallLocalModule,
+				Force:             false,
+				IgnoreModules:     map[string]bool{},
+				Varfiles:          []string{},
+				Variables:         []string{},
+				DisabledByDefault: false,
+				Rules:             map[string]*RuleConfig{},
+				Plugins: map[string]*PluginConfig{
+					"foo": {
+						Name:        "foo",
+						Enabled:     true,
+						Version:     "0.1.0",
+						Source:      "gitlab::gitlab.example.com/group/subgroup/bar",
+						SourceType:  "gitlab",
+						SourceHost:  "gitlab.example.com",
+						SourceOwner: "group/subgroup",
+						SourceRepo:  "bar",
+					},
+					"terraform": {
+						Name:    "terraform",
+						Enabled: true,
+					},
+				},
+			},
+			errCheck: neverHappend,
+		},
+		{
+			name: "plugin with Gitea source",
+			file: "plugin_with_gitea_source.hcl",
+			files: map[string]string{
+				"plugin_with_gitea_source.hcl": `
+plugin "foo" {
+	enabled = true
+
+	version = "0.1.0"
+	source = "gitea::codeberg.org/foo/bar"
+}`,
+			},
+			want: &Config{
+				CallModuleType:    terraform.CallLocalModule,
+				Force:             false,
+				IgnoreModules:     map[string]bool{},
+				Varfiles:          []string{},
+				Variables:         []string{},
+				DisabledByDefault: false,
+				Rules:             map[string]*RuleConfig{},
+				Plugins: map[string]*PluginConfig{
+					"foo": {
+						Name:        "foo",
+						Enabled:     true,
+						Version:     "0.1.0",
+						Source:      "gitea::codeberg.org/foo/bar",
+						SourceType:  "gitea",
+						SourceHost:  "codeberg.org",
+						SourceOwner: "foo",
+						SourceRepo:  "bar",
+					},
+					"terraform": {
+						Name:    "terraform",
+						Enabled: true,
+					},
+				},
+			},
+			errCheck: neverHappend,
+		},
+		{
+			name: "plugin with HTTPS source",
+			file: "plugin_with_https_source.hcl",
+			files: map[string]string{
+				"plugin_with_https_source.hcl": `
+plugin "foo" {
+	enabled = true
+
+	version = "0.1.0"
+	source = "https://mirror.example.com/tflint-ruleset-foo/"
+}`,
+			},
+			want: &Config{
+				CallModuleType:    terraform.CallLocalModule,
+				Force:             false,
+				IgnoreModules:     map[string]bool{},
+				Varfiles:          []string{},
+				Variables:         []string{},
+				DisabledByDefault: false,
+				Rules:             map[string]*RuleConfig{},
+				Plugins: map[string]*PluginConfig{
+					"foo": {
+						Name:       "foo",
+						Enabled:    true,
+						Version:    "0.1.0",
+						Source:     "https://mirror.example.com/tflint-ruleset-foo/",
+						SourceType: "https",
+						SourceURL:  "https://mirror.example.com/tflint-ruleset-foo",
+						SourceHost: "mirror.example.com",
+					},
+					"terraform": {
+						Name:    "terraform",
+						Enabled: true,
+					},
+				},
+			},
+			errCheck: neverHappend,
+		},
+		{
+			name: "plugin with file source",
+			file: "plugin_with_file_source.hcl",
+			files: map[string]string{
+				"plugin_with_file_source.hcl": `
+plugin "foo" {
+	enabled = true
+
+	version = "0.1.0"
+	source = "file:///srv/mirror/tflint-ruleset-foo"
+}`,
+			},
+			want: &Config{
+				CallModuleType:    terraform.CallLocalModule,
+				Force:             false,
+				IgnoreModules:     map[string]bool{},
+				Varfiles:          []string{},
+				Variables:         []string{},
+				DisabledByDefault: false,
+				Rules:             map[string]*RuleConfig{},
+				Plugins: map[string]*PluginConfig{
+					"foo": {
+						Name:       "foo",
+						Enabled:    true,
+						Version:    "0.1.0",
+						Source:     "file:///srv/mirror/tflint-ruleset-foo",
+						SourceType: "file",
+						SourceURL:  filepath.FromSlash("/srv/mirror/tflint-ruleset-foo"),
+					},
+					"terraform": {
+						Name:    "terraform",
+						Enabled: true,
+					},
+				},
+			},
+			errCheck: neverHappend,
+		},
+		{
+			name: "plugin with attestation signature and non-GitHub source",
+			file: "plugin_with_attestation_and_gitlab_source.hcl",
+			files: map[string]string{
+				"plugin_with_attestation_and_gitlab_source.hcl": `
+plugin "foo" {
+	enabled = true
+
+	version = "0.1.0"
+	source = "gitlab::gitlab.com/foo/bar"
+	signature = "attestation"
+}`,
+			},
+			errCheck: func(err error) bool {
+				return err == nil || err.Error() != `plugin "foo": "attestation" signature is only supported for GitHub sources unless "certificate_identity_regexp" is set`
+			},
+		},
+		{
+			name: "plugin with custom certificate identity",
+			file: "plugin_with_custom_certificate_identity.hcl",
+			files: map[string]string{
+				"plugin_with_custom_certificate_identity.hcl": `
+plugin "foo" {
+	enabled = true
+
+	version = "0.1.0"
+	source = "gitlab::gitlab.example.com/foo/bar"
+	signature = "attestation"
+	certificate_identity_regexp = "^https://gitlab.example.com/foo/bar//"
+	certificate_oidc_issuer = "https://gitlab.example.com"
+	trusted_root = "trusted_root.json"
+}`,
+			},
+			want: &Config{
+				CallModuleType:    terraform.CallLocalModule,
+				Force:             false,
+				IgnoreModules:     map[string]bool{},
+				Varfiles:          []string{},
+				Variables:         []string{},
+				DisabledByDefault: false,
+				Rules:             map[string]*RuleConfig{},
+				Plugins: map[string]*PluginConfig{
+					"foo": {
+						Name:                      "foo",
+						Enabled:                   true,
+						Version:                   "0.1.0",
+						Source:                    "gitlab::gitlab.example.com/foo/bar",
+						Signature:                 "attestation",
+						CertificateIdentityRegexp: "^https://gitlab.example.com/foo/bar//",
+						CertificateOIDCIssuer:     "https://gitlab.example.com",
+						TrustedRoot:               "trusted_root.json",
+						SourceType:                "gitlab",
+						SourceHost:                "gitlab.example.com",
+						SourceOwner:               "foo",
+						SourceRepo:                "bar",
+					},
+					"terraform": {
+						Name:    "terraform",
+						Enabled: true,
+					},
+				},
+			},
+			errCheck: neverHappend,
+		},
+		{
+			name: "plugin with pgp signature and trusted root",
+			file: "plugin_with_pgp_signature_and_trusted_root.hcl",
+			files: map[string]string{
+				"plugin_with_pgp_signature_and_trusted_root.hcl": `
+plugin "foo" {
+	enabled = true
+
+	version = "0.1.0"
+	source = "github.com/foo/bar"
+	signature = "pgp"
+	trusted_root = "trusted_root.json"
+}`,
+			},
+			errCheck: func(err error) bool {
+				return err == nil || err.Error() != `plugin "foo": "trusted_root" cannot be used when "signature" is "pgp"`
+			},
+		},
+		{
+			name: "plugin with invalid certificate identity regexp",
+			file: "plugin_with_invalid_certificate_identity_regexp.hcl",
+			files: map[string]string{
+				"plugin_with_invalid_certificate_identity_regexp.hcl": `
+plugin "foo" {
+	enabled = true
+
+	version = "0.1.0"
+	source = "github.com/foo/bar"
+	certificate_identity_regexp = "^https://github.com/foo/bar/(.github"
+}`,
+			},
+			errCheck: func(err error) bool {
+				return err == nil || !strings.HasPrefix(err.Error(), `plugin "foo": "^https://github.com/foo/bar/(.github" is invalid certificate_identity_regexp; `)
+			},
+		},
+		{
+			name: "plugin with timeout",
+			file: "plugin_with_timeout.hcl",
+			files: map[string]string{
+				"plugin_with_timeout.hcl": `
+plugin "foo" {
+	enabled = true
+	timeout = "2m"
+}`,
+			},
+			want: &Config{
+				CallModuleType:    terraform.CallLocalModule,
+				Force:             false,
+				IgnoreModules:     map[string]bool{},
+				Varfiles:          []string{},
+				Variables:         []string{},
+				DisabledByDefault: false,
+				Rules:             map[string]*RuleConfig{},
+				Plugins: map[string]*PluginConfig{
+					"foo": {
+						Name:         "foo",
+						Enabled:      true,
+						Timeout:      "2m",
+						CheckTimeout: 2 * time.Minute,
+					},
+					"terraform": {
+						Name:    "terraform",
+						Enabled: true,
+					},
+				},
+			},
+			errCheck: neverHappend,
+		},
+		{
+			name: "plugin with invalid timeout",
+			file: "plugin_with_invalid_timeout.hcl",
+			files: map[string]string{
+				"plugin_with_invalid_timeout.hcl": `
+plugin "foo" {
+	enabled = true
+	timeout = "2"
+}`,
+			},
+			errCheck: func(err error) bool {
+				return err == nil || err.Error() != `plugin "foo": "2" is invalid timeout. Must be a positive duration like "2m"`
+			},
+		},
+		{
+			name: "plugin with invalid Gitea source",
+			file: "plugin_with_invalid_gitea_source.hcl",
+			files: map[string]string{
+				"plugin_with_invalid_gitea_source.hcl": `
+plugin "foo" {
+	enabled = true
+
+	version = "0.1.0"
+	source = "gitea::codeberg.org/foo/bar/baz"
+}`,
+			},
+			errCheck: func(err error) bool {
+				return err == nil || err.Error() != `plugin "foo": "source" is invalid. Must be a Gitea reference in the format "gitea::${host}/${owner}/${repo}"`
+			},
+		},
 		{
 			name: "prefer the passed file over TFLINT_CONFIG_FILE",
 			file: "cli.hcl",
@@ -788,6 +1161,7 @@ config {
 				cmpopts.IgnoreUnexported(Config{}),
 				cmpopts.IgnoreFields(PluginConfig{}, "Body"),
 				cmpopts.IgnoreFields(RuleConfig{}, "Body"),
+				cmp.Comparer(func(x, y version.Constraints) bool { return x.String() == y.String() }),
 			}
 			if diff := cmp.Diff(test.want, got, opts...); diff != "" {
 				t.Fatal(diff)
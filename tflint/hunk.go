@@ -0,0 +1,116 @@
+package tflint
+
+import (
+	"strings"
+
+	hcl "github.com/hashicorp/hcl/v2"
+)
+
+// Hunk is a change made by autofixes, replacing lines [Start, End) of the original source
+// with Text. Line numbers are 0-based. If Start equals End, Text is inserted before Start.
+type Hunk struct {
+	Start int
+	End   int
+	Text  string
+}
+
+// maxHunkDiffCells limits the size of the table used to compute the line-based diff.
+// Larger changes are reported as a single hunk.
+const maxHunkDiffCells = 4_000_000
+
+// DiffHunks returns the hunks that turn original into fixed, based on the longest common subsequence of lines.
+func DiffHunks(original, fixed []byte) []Hunk {
+	a := splitLines(original)
+	b := splitLines(fixed)
+
+	// Trim the common prefix and suffix so that only the changed part is compared.
+	prefix := 0
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(a)-prefix && suffix < len(b)-prefix && a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+	a = a[prefix : len(a)-suffix]
+	b = b[prefix : len(b)-suffix]
+
+	if len(a) == 0 && len(b) == 0 {
+		return []Hunk{}
+	}
+	if (len(a)+1)*(len(b)+1) > maxHunkDiffCells {
+		return []Hunk{{Start: prefix, End: prefix + len(a), Text: strings.Join(b, "")}}
+	}
+
+	// lcs[i][j] is the length of the longest common subsequence of a[i:] and b[j:]
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	hunks := []Hunk{}
+	var current *Hunk
+	var inserted []string
+	flush := func() {
+		if current != nil {
+			current.Text = strings.Join(inserted, "")
+			hunks = append(hunks, *current)
+			current = nil
+			inserted = nil
+		}
+	}
+
+	i, j := 0, 0
+	for i < len(a) || j < len(b) {
+		switch {
+		case i < len(a) && j < len(b) && a[i] == b[j]:
+			flush()
+			i++
+			j++
+		case j < len(b) && (i == len(a) || lcs[i][j+1] >= lcs[i+1][j]):
+			if current == nil {
+				current = &Hunk{Start: prefix + i, End: prefix + i}
+			}
+			inserted = append(inserted, b[j])
+			j++
+		default:
+			if current == nil {
+				current = &Hunk{Start: prefix + i, End: prefix + i}
+			}
+			current.End++
+			i++
+		}
+	}
+	flush()
+
+	return hunks
+}
+
+// Overlaps returns whether the hunk overlaps with the lines of the range.
+func (h *Hunk) Overlaps(rng hcl.Range) bool {
+	// Convert to 0-based, half-open line range
+	start, end := rng.Start.Line-1, rng.End.Line
+	if h.Start == h.End {
+		// Insertion is overlapped if it is inserted within the range or right after it
+		return start <= h.Start && h.Start <= end
+	}
+	return h.Start < end && start < h.End
+}
+
+// splitLines splits the source into lines, keeping line endings.
+func splitLines(src []byte) []string {
+	lines := strings.SplitAfter(string(src), "\n")
+	if lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return lines
+}
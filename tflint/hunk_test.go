@@ -0,0 +1,71 @@
+package tflint
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestDiffHunks(t *testing.T) {
+	tests := []struct {
+		name     string
+		original string
+		fixed    string
+		want     []Hunk
+	}{
+		{
+			name:     "no changes",
+			original: "a\nb\nc\n",
+			fixed:    "a\nb\nc\n",
+			want:     []Hunk{},
+		},
+		{
+			name:     "replace a line",
+			original: "a\nb\nc\n",
+			fixed:    "a\nB\nc\n",
+			want:     []Hunk{{Start: 1, End: 2, Text: "B\n"}},
+		},
+		{
+			name:     "replace multiple lines",
+			original: "a\nb\nc\nd\ne\n",
+			fixed:    "A\nb\nc\nD\ne\n",
+			want: []Hunk{
+				{Start: 0, End: 1, Text: "A\n"},
+				{Start: 3, End: 4, Text: "D\n"},
+			},
+		},
+		{
+			name:     "insert lines",
+			original: "a\nc\n",
+			fixed:    "a\nb1\nb2\nc\n",
+			want:     []Hunk{{Start: 1, End: 1, Text: "b1\nb2\n"}},
+		},
+		{
+			name:     "delete lines",
+			original: "a\nb\nc\n",
+			fixed:    "a\nc\n",
+			want:     []Hunk{{Start: 1, End: 2, Text: ""}},
+		},
+		{
+			name:     "without trailing newline",
+			original: "a\nb",
+			fixed:    "a\nB",
+			want:     []Hunk{{Start: 1, End: 2, Text: "B"}},
+		},
+		{
+			name:     "empty original",
+			original: "",
+			fixed:    "a\n",
+			want:     []Hunk{{Start: 0, End: 0, Text: "a\n"}},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got := DiffHunks([]byte(test.original), []byte(test.fixed))
+			if diff := cmp.Diff(test.want, got); diff != "" {
+				t.Fatal(diff)
+			}
+		})
+	}
+}
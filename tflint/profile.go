@@ -0,0 +1,211 @@
+package tflint
+
+import (
+	"cmp"
+	"encoding/json"
+	"io"
+	"maps"
+	"slices"
+	"sync"
+	"time"
+)
+
+// Categories of events recorded in a profile.
+const (
+	// ProfileCategoryTFLint is for operations in TFLint, such as loading modules.
+	ProfileCategoryTFLint = "tflint"
+	// ProfileCategoryPlugin is for requests to plugins, such as applying configs and checking modules.
+	ProfileCategoryPlugin = "plugin"
+	// ProfileCategoryCallback is for requests from plugins to TFLint, such as evaluating expressions.
+	ProfileCategoryCallback = "callback"
+)
+
+// Profile is the timing profile of an inspection.
+// Events can be recorded from multiple goroutines.
+type Profile struct {
+	Events []*ProfileEvent `json:"events"`
+
+	mu sync.Mutex
+}
+
+// ProfileEvent is an operation measured in a profile.
+type ProfileEvent struct {
+	Category string            `json:"category"`
+	Name     string            `json:"name"`
+	Plugin   string            `json:"plugin,omitempty"`
+	Module   string            `json:"module,omitempty"`
+	Dir      string            `json:"dir,omitempty"`
+	Args     map[string]string `json:"args,omitempty"`
+	Start    time.Time         `json:"start"`
+	Duration time.Duration     `json:"duration"`
+}
+
+// ProfileEntry is the total time of events with the same category, name, and plugin.
+type ProfileEntry struct {
+	Category string        `json:"category"`
+	Name     string        `json:"name"`
+	Plugin   string        `json:"plugin,omitempty"`
+	Count    int           `json:"count"`
+	Total    time.Duration `json:"total"`
+	Max      time.Duration `json:"max"`
+}
+
+// NewProfile returns a new empty profile.
+func NewProfile() *Profile {
+	return &Profile{Events: []*ProfileEvent{}}
+}
+
+// Start starts measuring an operation and returns a function to end it.
+// If the profile is nil, nothing is recorded, so callers don't need to check whether profiling is enabled.
+func (p *Profile) Start(event *ProfileEvent) func() {
+	if p == nil {
+		return func() {}
+	}
+
+	event.Start = time.Now()
+	return func() {
+		event.Duration = time.Since(event.Start)
+
+		p.mu.Lock()
+		defer p.mu.Unlock()
+		p.Events = append(p.Events, event)
+	}
+}
+
+// Merge adds events of the other profile recorded in the given directory to the receiver.
+func (p *Profile) Merge(dir string, other *Profile) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	for _, event := range other.Events {
+		event.Dir = dir
+		p.Events = append(p.Events, event)
+	}
+}
+
+// Breakdown returns the total time of events grouped by category, name, and plugin,
+// sorted in descending order of the total time.
+func (p *Profile) Breakdown() []*ProfileEntry {
+	entries := map[[3]string]*ProfileEntry{}
+	for _, event := range p.Events {
+		key := [3]string{event.Category, event.Name, event.Plugin}
+		entry, exists := entries[key]
+		if !exists {
+			entry = &ProfileEntry{Category: event.Category, Name: event.Name, Plugin: event.Plugin}
+			entries[key] = entry
+		}
+		entry.Count++
+		entry.Total += event.Duration
+		entry.Max = max(entry.Max, event.Duration)
+	}
+
+	ret := slices.Collect(maps.Values(entries))
+	slices.SortFunc(ret, func(a, b *ProfileEntry) int {
+		if a.Total != b.Total {
+			return cmp.Compare(b.Total, a.Total)
+		}
+		return cmp.Or(
+			cmp.Compare(a.Category, b.Category),
+			cmp.Compare(a.Name, b.Name),
+			cmp.Compare(a.Plugin, b.Plugin),
+		)
+	})
+	return ret
+}
+
+// traceEvent is an event in the Chrome trace event format.
+// See https://docs.google.com/document/d/1CvAClvFfyA5R-PhYUmn5OOQtYMH4h6I0nSsKchNAySU
+type traceEvent struct {
+	Name      string            `json:"name"`
+	Category  string            `json:"cat,omitempty"`
+	Phase     string            `json:"ph"`
+	Timestamp int64             `json:"ts"`
+	Duration  int64             `json:"dur,omitempty"`
+	PID       int               `json:"pid"`
+	TID       int               `json:"tid"`
+	Args      map[string]string `json:"args,omitempty"`
+}
+
+// Write writes the profile in the JSON object format of Chrome trace events,
+// which can be loaded into chrome://tracing or Perfetto. The breakdown is written
+// in the "breakdown" key, which is ignored by the trace viewers.
+//
+// Each directory is shown as a process, and TFLint and each runner of plugins are shown as threads,
+// so that events run concurrently do not overlap in the same thread.
+func (p *Profile) Write(w io.Writer) error {
+	var origin time.Time
+	for _, event := range p.Events {
+		if origin.IsZero() || event.Start.Before(origin) {
+			origin = event.Start
+		}
+	}
+
+	events := slices.Clone(p.Events)
+	slices.SortStableFunc(events, func(a, b *ProfileEvent) int { return a.Start.Compare(b.Start) })
+
+	traceEvents := []traceEvent{}
+	pids := map[string]int{}
+	tids := map[[2]string]int{}
+	for _, event := range events {
+		pid, exists := pids[event.Dir]
+		if !exists {
+			pid = len(pids) + 1
+			pids[event.Dir] = pid
+			name := event.Dir
+			if name == "" {
+				name = "."
+			}
+			traceEvents = append(traceEvents, traceEvent{Name: "process_name", Phase: "M", PID: pid, Args: map[string]string{"name": name}})
+		}
+
+		thread := "tflint"
+		if event.Plugin != "" {
+			thread = event.Plugin
+			if event.Module != "" {
+				thread += " (" + event.Module + ")"
+			}
+		}
+		tid, exists := tids[[2]string{event.Dir, thread}]
+		if !exists {
+			tid = len(tids) + 1
+			tids[[2]string{event.Dir, thread}] = tid
+			traceEvents = append(traceEvents, traceEvent{Name: "thread_name", Phase: "M", PID: pid, TID: tid, Args: map[string]string{"name": thread}})
+		}
+
+		args := maps.Clone(event.Args)
+		for key, value := range map[string]string{"plugin": event.Plugin, "module": event.Module} {
+			if value == "" {
+				continue
+			}
+			if args == nil {
+				args = map[string]string{}
+			}
+			args[key] = value
+		}
+		traceEvents = append(traceEvents, traceEvent{
+			Name:      event.Name,
+			Category:  event.Category,
+			Phase:     "X",
+			Timestamp: event.Start.Sub(origin).Microseconds(),
+			Duration:  event.Duration.Microseconds(),
+			PID:       pid,
+			TID:       tid,
+			Args:      args,
+		})
+	}
+
+	out, err := json.MarshalIndent(struct {
+		TraceEvents     []traceEvent    `json:"traceEvents"`
+		DisplayTimeUnit string          `json:"displayTimeUnit"`
+		Breakdown       []*ProfileEntry `json:"breakdown"`
+	}{
+		TraceEvents:     traceEvents,
+		DisplayTimeUnit: "ms",
+		Breakdown:       p.Breakdown(),
+	}, "", "  ")
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(out)
+	return err
+}
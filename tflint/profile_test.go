@@ -0,0 +1,166 @@
+package tflint
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestProfile_Start(t *testing.T) {
+	profile := NewProfile()
+	end := profile.Start(&ProfileEvent{Category: ProfileCategoryTFLint, Name: "BuildRunners"})
+	end()
+
+	if len(profile.Events) != 1 {
+		t.Fatalf("expected 1 event, got %d", len(profile.Events))
+	}
+	if profile.Events[0].Start.IsZero() {
+		t.Error("expected the start time to be recorded")
+	}
+
+	// Nothing is recorded if profiling is disabled
+	var disabled *Profile
+	disabled.Start(&ProfileEvent{Category: ProfileCategoryTFLint, Name: "BuildRunners"})()
+}
+
+func TestProfile_Breakdown(t *testing.T) {
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	profile := &Profile{
+		Events: []*ProfileEvent{
+			{Category: ProfileCategoryTFLint, Name: "BuildRunners", Start: start, Duration: 2 * time.Second},
+			{Category: ProfileCategoryPlugin, Name: "Check", Plugin: "aws", Module: "root", Start: start, Duration: 3 * time.Second},
+			{Category: ProfileCategoryPlugin, Name: "Check", Plugin: "aws", Module: "module.foo", Start: start, Duration: time.Second},
+			{Category: ProfileCategoryPlugin, Name: "Check", Plugin: "google", Module: "root", Start: start, Duration: time.Second},
+			{Category: ProfileCategoryCallback, Name: "EvaluateExpr", Plugin: "aws", Module: "root", Start: start, Duration: time.Second},
+		},
+	}
+
+	expected := []*ProfileEntry{
+		{Category: ProfileCategoryPlugin, Name: "Check", Plugin: "aws", Count: 2, Total: 4 * time.Second, Max: 3 * time.Second},
+		{Category: ProfileCategoryTFLint, Name: "BuildRunners", Count: 1, Total: 2 * time.Second, Max: 2 * time.Second},
+		{Category: ProfileCategoryCallback, Name: "EvaluateExpr", Plugin: "aws", Count: 1, Total: time.Second, Max: time.Second},
+		{Category: ProfileCategoryPlugin, Name: "Check", Plugin: "google", Count: 1, Total: time.Second, Max: time.Second},
+	}
+	if diff := cmp.Diff(expected, profile.Breakdown()); diff != "" {
+		t.Fatal(diff)
+	}
+}
+
+func TestProfile_Write(t *testing.T) {
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	profile := NewProfile()
+	profile.Merge("subdir", &Profile{
+		Events: []*ProfileEvent{
+			{Category: ProfileCategoryPlugin, Name: "Check", Plugin: "aws", Module: "root", Start: start.Add(time.Millisecond), Duration: 3 * time.Millisecond},
+			{Category: ProfileCategoryTFLint, Name: "BuildRunners", Start: start, Duration: time.Millisecond},
+			{Category: ProfileCategoryCallback, Name: "EmitIssue", Plugin: "aws", Module: "root", Args: map[string]string{"rule": "aws_instance_invalid_type"}, Start: start.Add(2 * time.Millisecond), Duration: 500 * time.Microsecond},
+		},
+	})
+
+	buf := new(bytes.Buffer)
+	if err := profile.Write(buf); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := `{
+  "traceEvents": [
+    {
+      "name": "process_name",
+      "ph": "M",
+      "ts": 0,
+      "pid": 1,
+      "tid": 0,
+      "args": {
+        "name": "subdir"
+      }
+    },
+    {
+      "name": "thread_name",
+      "ph": "M",
+      "ts": 0,
+      "pid": 1,
+      "tid": 1,
+      "args": {
+        "name": "tflint"
+      }
+    },
+    {
+      "name": "BuildRunners",
+      "cat": "tflint",
+      "ph": "X",
+      "ts": 0,
+      "dur": 1000,
+      "pid": 1,
+      "tid": 1
+    },
+    {
+      "name": "thread_name",
+      "ph": "M",
+      "ts": 0,
+      "pid": 1,
+      "tid": 2,
+      "args": {
+        "name": "aws (root)"
+      }
+    },
+    {
+      "name": "Check",
+      "cat": "plugin",
+      "ph": "X",
+      "ts": 1000,
+      "dur": 3000,
+      "pid": 1,
+      "tid": 2,
+      "args": {
+        "module": "root",
+        "plugin": "aws"
+      }
+    },
+    {
+      "name": "EmitIssue",
+      "cat": "callback",
+      "ph": "X",
+      "ts": 2000,
+      "dur": 500,
+      "pid": 1,
+      "tid": 2,
+      "args": {
+        "module": "root",
+        "plugin": "aws",
+        "rule": "aws_instance_invalid_type"
+      }
+    }
+  ],
+  "displayTimeUnit": "ms",
+  "breakdown": [
+    {
+      "category": "plugin",
+      "name": "Check",
+      "plugin": "aws",
+      "count": 1,
+      "total": 3000000,
+      "max": 3000000
+    },
+    {
+      "category": "tflint",
+      "name": "BuildRunners",
+      "count": 1,
+      "total": 1000000,
+      "max": 1000000
+    },
+    {
+      "category": "callback",
+      "name": "EmitIssue",
+      "plugin": "aws",
+      "count": 1,
+      "total": 500000,
+      "max": 500000
+    }
+  ]
+}`
+	if diff := cmp.Diff(expected, buf.String()); diff != "" {
+		t.Fatal(diff)
+	}
+}
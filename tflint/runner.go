@@ -5,6 +5,7 @@ import (
 	"log"
 	"maps"
 	"path/filepath"
+	"sync"
 
 	hcl "github.com/hashicorp/hcl/v2"
 	"github.com/terraform-linters/tflint-plugin-sdk/hclext"
@@ -17,6 +18,11 @@ import (
 // Runner checks templates according rules.
 // For variables interpolation, it has Terraform eval context.
 // After checking, it accumulates results as issues.
+//
+// A runner can be checked by multiple plugins concurrently. Reading the module,
+// evaluating expressions, and emitting issues with EmitIssue and WithExpressionContext
+// are goroutine-safe. ApplyChanges rebuilds the module, so it must not be called
+// while other goroutines are using the runner.
 type Runner struct {
 	TFConfig *terraform.Config
 	Issues   Issues
@@ -27,6 +33,11 @@ type Runner struct {
 	currentExpr hcl.Expression
 	modVars     map[string]*moduleVariable
 	changes     map[string][]byte
+
+	// mu guards Issues while emitting issues.
+	mu sync.Mutex
+	// exprMu serializes WithExpressionContext so that currentExpr is not overwritten by other goroutines.
+	exprMu sync.Mutex
 }
 
 // Rule is interface for building the issue
@@ -52,8 +63,9 @@ func NewRunner(originalWorkingDir string, c *Config, ants map[string]Annotations
 	}
 	ctx := &terraform.Evaluator{
 		Meta: &terraform.ContextMeta{
-			Env:                terraform.Workspace(),
+			Env:                terraform.WorkspaceIn(cfg.Root.Module.WorkingDir),
 			OriginalWorkingDir: originalWorkingDir,
+			BaseDir:            cfg.Root.Module.WorkingDir,
 		},
 		ModulePath:     cfg.Path.UnkeyedInstanceShim(),
 		Config:         cfg.Root,
@@ -205,6 +217,14 @@ func (r *Runner) LookupChanges(files ...string) map[string][]byte {
 	return changes
 }
 
+// ModulePath returns the path of the module like "module.foo", or "root" for the root module.
+func (r *Runner) ModulePath() string {
+	if r.TFConfig.Path.IsRoot() {
+		return "root"
+	}
+	return r.TFConfig.Path.String()
+}
+
 // File returns the raw *hcl.File representation of a Terraform configuration at the specified path,
 // or nil if there path does not match any configuration.
 func (r *Runner) File(path string) *hcl.File {
@@ -225,6 +245,9 @@ func (r *Runner) Sources() map[string][]byte {
 
 // EmitIssue builds an issue and accumulates it.
 // Returns true if the issue was not ignored by annotations.
+//
+// Issues in called modules are emitted based on the current expression context.
+// When emitting issues from multiple goroutines, call it within WithExpressionContext.
 func (r *Runner) EmitIssue(rule Rule, message string, location hcl.Range, fixable bool) bool {
 	if r.TFConfig.Path.IsRoot() {
 		return r.emitIssue(&Issue{
@@ -256,7 +279,11 @@ func (r *Runner) EmitIssue(rule Rule, message string, location hcl.Range, fixabl
 }
 
 // WithExpressionContext sets the context of the passed expression currently being processed.
+// Calls from multiple goroutines are serialized.
 func (r *Runner) WithExpressionContext(expr hcl.Expression, proc func() error) error {
+	r.exprMu.Lock()
+	defer r.exprMu.Unlock()
+
 	r.currentExpr = expr
 	err := proc()
 	r.currentExpr = nil
@@ -301,6 +328,8 @@ func (r *Runner) emitIssue(issue *Issue) bool {
 			}
 		}
 	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
 	r.Issues = append(r.Issues, issue)
 	return true
 }
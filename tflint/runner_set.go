@@ -1,6 +1,7 @@
 package tflint
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/terraform-linters/tflint/terraform"
@@ -8,11 +9,15 @@ import (
 
 // BuildRunners loads the module rooted at dir using the given loader and config,
 // returning the root runner and its module runners.
-func BuildRunners(loader *terraform.Loader, config *Config, workingDir, dir string) (*Runner, []*Runner, error) {
+// If the context is cancelled, loading is aborted before the next step and the context error is returned.
+func BuildRunners(ctx context.Context, loader *terraform.Loader, config *Config, workingDir, dir string) (*Runner, []*Runner, error) {
 	rootMod, diags := loader.LoadRootModule(dir)
 	if diags.HasErrors() {
 		return nil, []*Runner{}, fmt.Errorf("Failed to load the root module; %w", diags)
 	}
+	if err := ctx.Err(); err != nil {
+		return nil, []*Runner{}, err
+	}
 
 	files, diags := loader.LoadConfigDirFiles(dir)
 	if diags.HasErrors() {
@@ -37,6 +42,9 @@ func BuildRunners(loader *terraform.Loader, config *Config, workingDir, dir stri
 		return nil, []*Runner{}, fmt.Errorf("Failed to parse variables; %w", diags)
 	}
 	variables = append(variables, cliVars)
+	if err := ctx.Err(); err != nil {
+		return nil, []*Runner{}, err
+	}
 
 	configs, diags := terraform.BuildConfig(
 		rootMod,
@@ -47,6 +55,9 @@ func BuildRunners(loader *terraform.Loader, config *Config, workingDir, dir stri
 	if diags.HasErrors() {
 		return nil, []*Runner{}, fmt.Errorf("Failed to build configurations; %w", diags)
 	}
+	if err := ctx.Err(); err != nil {
+		return nil, []*Runner{}, err
+	}
 
 	runner, err := NewRunner(workingDir, config, annotations, configs, variables...)
 	if err != nil {
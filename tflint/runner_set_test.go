@@ -1,6 +1,7 @@
 package tflint
 
 import (
+	"context"
 	"os"
 	"testing"
 
@@ -89,7 +90,7 @@ resource "aws_instance" "child" {}
 			config := EmptyConfig()
 			config.Varfiles = tc.varfiles
 
-			runner, moduleRunners, err := BuildRunners(loader, config, wd, ".")
+			runner, moduleRunners, err := BuildRunners(context.Background(), loader, config, wd, ".")
 			if err != nil {
 				t.Fatalf("Unexpected error occurred: %s", err)
 			}
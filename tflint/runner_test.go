@@ -2,6 +2,7 @@ package tflint
 
 import (
 	"path/filepath"
+	"sync"
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
@@ -812,6 +813,55 @@ func Test_EmitIssue(t *testing.T) {
 	}
 }
 
+func Test_EmitIssue_concurrently(t *testing.T) {
+	runner := TestRunner(t, map[string]string{"module.tf": "foo = 1\nbar = 2"})
+	runner.TFConfig.Path = []string{"module", "module1"}
+	runner.modVars = map[string]*moduleVariable{
+		"foo": {Root: true, DeclRange: hcl.Range{Filename: "module.tf", Start: hcl.Pos{Line: 1}}},
+		"bar": {Root: true, DeclRange: hcl.Range{Filename: "module.tf", Start: hcl.Pos{Line: 2}}},
+	}
+
+	var wg sync.WaitGroup
+	for i := range 100 {
+		name := "foo"
+		if i%2 == 1 {
+			name = "bar"
+		}
+		wg.Add(1)
+		go func(name string) {
+			defer wg.Done()
+
+			expr, diags := hclsyntax.ParseExpression([]byte("var."+name), "test.tf", hcl.InitialPos)
+			if diags.HasErrors() {
+				t.Error(diags)
+				return
+			}
+			err := runner.WithExpressionContext(expr, func() error {
+				runner.EmitIssue(&testRule{}, name, hcl.Range{Filename: "test.tf", Start: hcl.Pos{Line: 1}}, false)
+				return nil
+			})
+			if err != nil {
+				t.Error(err)
+			}
+		}(name)
+	}
+	wg.Wait()
+
+	if len(runner.Issues) != 100 {
+		t.Fatalf("expected 100 issues, got %d", len(runner.Issues))
+	}
+	// Each issue is emitted based on its own expression context
+	for _, issue := range runner.Issues {
+		expected := 1
+		if issue.Message == "bar" {
+			expected = 2
+		}
+		if issue.Range.Start.Line != expected {
+			t.Errorf("expected the issue of %s to be emitted at line %d, got %d", issue.Message, expected, issue.Range.Start.Line)
+		}
+	}
+}
+
 func TestApplyChanges(t *testing.T) {
 	tests := []struct {
 		name    string
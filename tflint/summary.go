@@ -0,0 +1,104 @@
+package tflint
+
+import (
+	"strings"
+	"time"
+
+	sdk "github.com/terraform-linters/tflint-plugin-sdk/tflint"
+)
+
+// Inspection phases measured in a summary.
+const (
+	PhaseConfig       = "config"
+	PhaseLoading      = "loading"
+	PhasePluginLaunch = "plugin_launch"
+	PhaseCheck        = "check"
+)
+
+// Phases is the list of inspection phases in the order they are performed.
+var Phases = []string{PhaseConfig, PhaseLoading, PhasePluginLaunch, PhaseCheck}
+
+// Summary is the statistics of an inspection.
+// In recursive inspection, summaries of each directory are merged.
+// Since directories are inspected in parallel, phase durations are the longest
+// of all directories rather than the total, so that they do not exceed the elapsed time.
+type Summary struct {
+	Rules         map[string]int           `json:"rules"`
+	Severities    map[string]int           `json:"severities"`
+	Plugins       map[string]int           `json:"plugins"`
+	Dirs          map[string]int           `json:"dirs"`
+	Fixable       int                      `json:"fixable"`
+	Unfixable     int                      `json:"unfixable"`
+	Files         int                      `json:"files"`
+	ModuleRunners int                      `json:"module_runners"`
+	Phases        map[string]time.Duration `json:"phases"`
+	Elapsed       time.Duration            `json:"elapsed"`
+}
+
+// NewSummary returns a new empty summary.
+func NewSummary() *Summary {
+	return &Summary{
+		Rules:      map[string]int{},
+		Severities: map[string]int{},
+		Plugins:    map[string]int{},
+		Dirs:       map[string]int{},
+		Phases:     map[string]time.Duration{},
+	}
+}
+
+// AddIssues counts the given issues found in the given directory.
+// rulePlugins maps rule names to the names of plugins that provide them.
+// Issues of rules not in the map are counted as an unknown plugin.
+func (s *Summary) AddIssues(dir string, issues Issues, rulePlugins map[string]string) {
+	if _, exists := s.Dirs[dir]; !exists {
+		s.Dirs[dir] = 0
+	}
+
+	for _, issue := range issues {
+		s.Rules[issue.Rule.Name()]++
+		s.Severities[severityName(issue.Rule.Severity())]++
+		s.Dirs[dir]++
+
+		plugin, exists := rulePlugins[issue.Rule.Name()]
+		if !exists {
+			plugin = "(unknown)"
+		}
+		s.Plugins[plugin]++
+
+		if issue.Fixable {
+			s.Fixable++
+		} else {
+			s.Unfixable++
+		}
+	}
+}
+
+// Merge adds statistics of the other summary to the receiver.
+// Elapsed time is not merged because it is measured by the caller.
+func (s *Summary) Merge(other *Summary) {
+	mergeCounts(s.Rules, other.Rules)
+	mergeCounts(s.Severities, other.Severities)
+	mergeCounts(s.Plugins, other.Plugins)
+	mergeCounts(s.Dirs, other.Dirs)
+	s.Fixable += other.Fixable
+	s.Unfixable += other.Unfixable
+	s.Files += other.Files
+	s.ModuleRunners += other.ModuleRunners
+	for phase, duration := range other.Phases {
+		s.Phases[phase] = max(s.Phases[phase], duration)
+	}
+}
+
+// severityName returns the name of the severity used in outputs, such as "info" for notices.
+func severityName(severity Severity) string {
+	if severity == sdk.NOTICE {
+		return "info"
+	}
+	return strings.ToLower(severity.String())
+}
+
+func mergeCounts(dst, src map[string]int) {
+	for key, count := range src {
+		dst[key] += count
+	}
+}
@@ -0,0 +1,79 @@
+package tflint
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	sdk "github.com/terraform-linters/tflint-plugin-sdk/tflint"
+)
+
+func TestSummary_AddIssues(t *testing.T) {
+	summary := NewSummary()
+	issues := Issues{
+		{Rule: &rule{RawName: "rule1", RawSeverity: sdk.ERROR}, Fixable: true},
+		{Rule: &rule{RawName: "rule1", RawSeverity: sdk.ERROR}},
+		{Rule: &rule{RawName: "rule2", RawSeverity: sdk.WARNING}},
+		{Rule: &rule{RawName: "rule3", RawSeverity: sdk.NOTICE}},
+	}
+	rulePlugins := map[string]string{"rule1": "foo", "rule2": "bar"}
+
+	summary.AddIssues("subdir", issues, rulePlugins)
+	summary.AddIssues("empty", Issues{}, rulePlugins)
+
+	expected := &Summary{
+		Rules:      map[string]int{"rule1": 2, "rule2": 1, "rule3": 1},
+		Severities: map[string]int{"error": 2, "warning": 1, "info": 1},
+		Plugins:    map[string]int{"foo": 2, "bar": 1, "(unknown)": 1},
+		Dirs:       map[string]int{"subdir": 4, "empty": 0},
+		Fixable:    1,
+		Unfixable:  3,
+		Phases:     map[string]time.Duration{},
+	}
+	if diff := cmp.Diff(expected, summary); diff != "" {
+		t.Fatal(diff)
+	}
+}
+
+func TestSummary_Merge(t *testing.T) {
+	summary := &Summary{
+		Rules:         map[string]int{"rule1": 1},
+		Severities:    map[string]int{"error": 1},
+		Plugins:       map[string]int{"foo": 1},
+		Dirs:          map[string]int{"subdir1": 1},
+		Fixable:       1,
+		Files:         2,
+		ModuleRunners: 1,
+		Phases:        map[string]time.Duration{PhaseConfig: time.Second, PhaseCheck: 3 * time.Second},
+		Elapsed:       3 * time.Second,
+	}
+	other := &Summary{
+		Rules:         map[string]int{"rule1": 1, "rule2": 1},
+		Severities:    map[string]int{"error": 1, "warning": 1},
+		Plugins:       map[string]int{"foo": 2},
+		Dirs:          map[string]int{"subdir2": 2},
+		Unfixable:     2,
+		Files:         3,
+		ModuleRunners: 2,
+		Phases:        map[string]time.Duration{PhaseConfig: time.Second, PhaseCheck: 2 * time.Second},
+		Elapsed:       5 * time.Second,
+	}
+
+	summary.Merge(other)
+
+	expected := &Summary{
+		Rules:         map[string]int{"rule1": 2, "rule2": 1},
+		Severities:    map[string]int{"error": 2, "warning": 1},
+		Plugins:       map[string]int{"foo": 3},
+		Dirs:          map[string]int{"subdir1": 1, "subdir2": 2},
+		Fixable:       1,
+		Unfixable:     2,
+		Files:         5,
+		ModuleRunners: 3,
+		Phases:        map[string]time.Duration{PhaseConfig: time.Second, PhaseCheck: 3 * time.Second},
+		Elapsed:       3 * time.Second,
+	}
+	if diff := cmp.Diff(expected, summary); diff != "" {
+		t.Fatal(diff)
+	}
+}